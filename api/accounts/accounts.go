@@ -15,10 +15,12 @@ import (
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/api/deployments"
 	"github.com/vechain/thor/v2/api/utils"
 	"github.com/vechain/thor/v2/bft"
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/logdb"
 	"github.com/vechain/thor/v2/runtime"
 	"github.com/vechain/thor/v2/state"
 	"github.com/vechain/thor/v2/thor"
@@ -32,6 +34,7 @@ type Accounts struct {
 	callGasLimit uint64
 	forkConfig   thor.ForkConfig
 	bft          bft.Committer
+	logDB        logdb.LogStore // nil if logs are skipped
 }
 
 func New(
@@ -40,6 +43,7 @@ func New(
 	callGasLimit uint64,
 	forkConfig thor.ForkConfig,
 	bft bft.Committer,
+	logDB logdb.LogStore,
 ) *Accounts {
 	return &Accounts{
 		repo,
@@ -47,6 +51,7 @@ func New(
 		callGasLimit,
 		forkConfig,
 		bft,
+		logDB,
 	}
 }
 
@@ -69,19 +74,58 @@ func (a *Accounts) handleGetCode(w http.ResponseWriter, req *http.Request) error
 		return utils.BadRequest(errors.WithMessage(err, "revision"))
 	}
 
-	_, st, err := utils.GetSummaryAndState(revision, a.repo, a.bft, a.stater)
+	summary, st, err := utils.GetSummaryAndState(revision, a.repo, a.bft, a.stater)
 	if err != nil {
 		if a.repo.IsNotFound(err) {
 			return utils.BadRequest(errors.WithMessage(err, "revision"))
 		}
 		return err
 	}
+	if err := a.setImmutable(w, revision, summary); err != nil {
+		return err
+	}
 	code, err := a.getCode(addr, st)
 	if err != nil {
 		return err
 	}
 
-	return utils.WriteJSON(w, map[string]string{"code": hexutil.Encode(code)})
+	return utils.WriteJSON(w, &GetCodeResult{Code: hexutil.Encode(code)})
+}
+
+// handleGetCreation returns the clause creating the contract, which is not found for contracts created
+// by contracts or in the genesis block.
+func (a *Accounts) handleGetCreation(w http.ResponseWriter, req *http.Request) error {
+	addr, err := thor.ParseAddress(mux.Vars(req)["address"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "address"))
+	}
+
+	found, err := a.logDB.FilterDeployments(req.Context(), &logdb.DeploymentFilter{
+		CriteriaSet: []*logdb.DeploymentCriteria{{Address: &addr}},
+		Options:     &logdb.Options{Limit: 1},
+	})
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		return utils.HTTPError(errors.New("contract creation not found"), http.StatusNotFound)
+	}
+	return utils.WriteJSON(w, deployments.ConvertDeployment(found[0]))
+}
+
+// setImmutable marks the response immutable if the state is of a finalized block referenced by ID or number.
+func (a *Accounts) setImmutable(w http.ResponseWriter, revision *utils.Revision, summary *chain.BlockSummary) error {
+	if !revision.IsFixed() {
+		return nil
+	}
+	finalized, err := utils.IsFinalized(summary.Header.ID(), a.repo, a.bft)
+	if err != nil {
+		return err
+	}
+	if finalized {
+		utils.SetImmutable(w)
+	}
+	return nil
 }
 
 func (a *Accounts) getAccount(addr thor.Address, header *block.Header, state *state.State) (*Account, error) {
@@ -130,6 +174,9 @@ func (a *Accounts) handleGetAccount(w http.ResponseWriter, req *http.Request) er
 		}
 		return err
 	}
+	if err := a.setImmutable(w, revision, summary); err != nil {
+		return err
+	}
 
 	acc, err := a.getAccount(addr, summary.Header, st)
 	if err != nil {
@@ -152,19 +199,22 @@ func (a *Accounts) handleGetStorage(w http.ResponseWriter, req *http.Request) er
 		return utils.BadRequest(errors.WithMessage(err, "revision"))
 	}
 
-	_, st, err := utils.GetSummaryAndState(revision, a.repo, a.bft, a.stater)
+	summary, st, err := utils.GetSummaryAndState(revision, a.repo, a.bft, a.stater)
 	if err != nil {
 		if a.repo.IsNotFound(err) {
 			return utils.BadRequest(errors.WithMessage(err, "revision"))
 		}
 		return err
 	}
+	if err := a.setImmutable(w, revision, summary); err != nil {
+		return err
+	}
 
 	storage, err := a.getStorage(addr, key, st)
 	if err != nil {
 		return err
 	}
-	return utils.WriteJSON(w, map[string]string{"value": storage.String()})
+	return utils.WriteJSON(w, &GetStorageResult{Value: storage.String()})
 }
 
 func (a *Accounts) handleCallContract(w http.ResponseWriter, req *http.Request) error {
@@ -245,16 +295,26 @@ func (a *Accounts) batchCall(
 	}
 
 	signer, _ := header.Signer()
-	rt := runtime.New(a.repo.NewChain(header.ParentID()), st,
-		&xenv.BlockContext{
-			Beneficiary: header.Beneficiary(),
-			Signer:      signer,
-			Number:      header.Number(),
-			Time:        header.Timestamp(),
-			GasLimit:    header.GasLimit(),
-			TotalScore:  header.TotalScore(),
-		},
-		a.forkConfig)
+	blockCtx := &xenv.BlockContext{
+		Beneficiary: header.Beneficiary(),
+		Signer:      signer,
+		Number:      header.Number(),
+		Time:        header.Timestamp(),
+		GasLimit:    header.GasLimit(),
+		TotalScore:  header.TotalScore(),
+	}
+	if err := batchCallData.BlockOverrides.Apply(blockCtx); err != nil {
+		return nil, err
+	}
+	if err := batchCallData.StateOverrides.Apply(st, blockCtx.Time); err != nil {
+		return nil, err
+	}
+
+	chain, err := utils.NewCallChain(a.repo, header, blockCtx)
+	if err != nil {
+		return nil, err
+	}
+	rt := runtime.New(chain, st, blockCtx, a.forkConfig)
 	results = make(BatchCallResults, 0)
 	resultCh := make(chan interface{}, 1)
 	for i, clause := range clauses {
@@ -372,6 +432,12 @@ func (a *Accounts) Mount(root *mux.Router, pathPrefix string) {
 		Methods("GET").
 		Name("accounts_get_storage").
 		HandlerFunc(utils.WrapHandlerFunc(a.handleGetStorage))
+	if a.logDB != nil {
+		sub.Path("/{address}/creation").
+			Methods(http.MethodGet).
+			Name("accounts_get_creation").
+			HandlerFunc(utils.WrapHandlerFunc(a.handleGetCreation))
+	}
 	// These two methods are currently deprecated
 	sub.Path("").
 		Methods(http.MethodPost).
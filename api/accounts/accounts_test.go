@@ -13,6 +13,7 @@ import (
 	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,10 +25,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	ABI "github.com/vechain/thor/v2/abi"
 	"github.com/vechain/thor/v2/api/accounts"
+	"github.com/vechain/thor/v2/api/deployments"
+	"github.com/vechain/thor/v2/api/utils"
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/cmd/thor/solo"
 	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/logdb"
 	"github.com/vechain/thor/v2/muxdb"
 	"github.com/vechain/thor/v2/packer"
 	"github.com/vechain/thor/v2/state"
@@ -104,25 +108,30 @@ var invalidNumberRevision = "4294967296"
 
 var acc *accounts.Accounts
 var ts *httptest.Server
+var logDB logdb.LogStore
 
 func TestAccount(t *testing.T) {
 	initAccountServer(t)
 	defer ts.Close()
+	defer logDB.Close()
 
 	for name, tt := range map[string]func(*testing.T){
 		"getAccount":                           getAccount,
 		"getAccountWithNonExisitingRevision":   getAccountWithNonExisitingRevision,
 		"getAccountWithGenesisRevision":        getAccountWithGenesisRevision,
 		"getAccountWithFinalizedRevision":      getAccountWithFinalizedRevision,
+		"getAccountCacheControl":               getAccountCacheControl,
 		"getCode":                              getCode,
 		"getCodeWithNonExisitingRevision":      getCodeWithNonExisitingRevision,
 		"getStorage":                           getStorage,
 		"getStorageWithNonExisitingRevision":   getStorageWithNonExisitingRevision,
+		"getCreation":                          getCreation,
 		"deployContractWithCall":               deployContractWithCall,
 		"callContract":                         callContract,
 		"callContractWithNonExisitingRevision": callContractWithNonExisitingRevision,
 		"batchCall":                            batchCall,
 		"batchCallWithNonExisitingRevision":    batchCallWithNonExisitingRevision,
+		"batchCallWithOverrides":               batchCallWithOverrides,
 	} {
 		t.Run(name, tt)
 	}
@@ -145,6 +154,24 @@ func getAccount(t *testing.T) {
 	assert.Equal(t, http.StatusOK, statusCode, "OK")
 }
 
+func getAccountCacheControl(t *testing.T) {
+	for path, immutable := range map[string]bool{
+		addr.String() + "?revision=0":                                           true,
+		addr.String() + "/code?revision=" + genesisBlock.Header().ID().String(): true,
+		addr.String() + "/storage/" + storageKey.String() + "?revision=0":       true,
+		addr.String() + "?revision=finalized":                                   false,
+		addr.String():                                                           false,
+	} {
+		res, err := http.Get(ts.URL + "/accounts/" + path) // nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode, path)
+		assert.Equal(t, immutable, utils.IsImmutable(res.Header), path)
+	}
+}
+
 func getAccountWithNonExisitingRevision(t *testing.T) {
 	revision64Len := "0x00000000851caf3cfdb6e899cf5958bfb1ac3413d346d43539627e6be7ec1b4a"
 
@@ -249,6 +276,26 @@ func getStorageWithNonExisitingRevision(t *testing.T) {
 	assert.Equal(t, "revision: leveldb: not found\n", string(res), "revision not found")
 }
 
+func getCreation(t *testing.T) {
+	_, statusCode := httpGet(t, ts.URL+"/accounts/"+invalidAddr+"/creation")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad address")
+
+	res, statusCode := httpGet(t, ts.URL+"/accounts/"+addr.String()+"/creation")
+	assert.Equal(t, http.StatusNotFound, statusCode, "not a contract")
+	assert.Equal(t, "contract creation not found", strings.TrimSpace(string(res)))
+
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/creation")
+	assert.Equal(t, http.StatusOK, statusCode)
+	var creation deployments.FilteredDeployment
+	if err := json.Unmarshal(res, &creation); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, contractAddr, creation.Address)
+	assert.Equal(t, genesis.DevAccounts()[0].Address, creation.Deployer)
+	assert.Equal(t, uint32(1), creation.Meta.BlockNumber)
+	assert.Equal(t, uint32(1), creation.Meta.ClauseIndex)
+}
+
 func initAccountServer(t *testing.T) {
 	db := muxdb.NewMem()
 	stater := state.NewStater(db)
@@ -260,6 +307,7 @@ func initAccountServer(t *testing.T) {
 	}
 	genesisBlock = b
 	repo, _ := chain.NewRepository(db, b)
+	logDB = logdb.NewKVMem()
 	claTransfer := tx.NewClause(&addr).WithValue(value)
 	claDeploy := tx.NewClause(nil).WithData(bytecode)
 	transaction := buildTxWithClauses(t, repo.ChainTag(), claTransfer, claDeploy)
@@ -279,7 +327,7 @@ func initAccountServer(t *testing.T) {
 
 	router := mux.NewRouter()
 	gasLimit = math.MaxUint32
-	acc = accounts.New(repo, stater, gasLimit, thor.NoFork, solo.NewBFTEngine(repo))
+	acc = accounts.New(repo, stater, gasLimit, thor.NoFork, solo.NewBFTEngine(repo), logDB)
 	acc.Mount(router, "/accounts")
 	ts = httptest.NewServer(router)
 }
@@ -321,6 +369,13 @@ func packTx(repo *chain.Repository, stater *state.Stater, transaction *tx.Transa
 	if err := repo.AddBlock(b, receipts, 0); err != nil {
 		t.Fatal(err)
 	}
+	w := logDB.NewWriter()
+	if err := w.Write(b, receipts); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
 	if err := repo.SetBestBlockID(b.Header().ID()); err != nil {
 		t.Fatal(err)
 	}
@@ -529,6 +584,124 @@ func batchCall(t *testing.T) {
 	assert.Equal(t, http.StatusForbidden, statusCode)
 }
 
+func batchCallWithOverrides(t *testing.T) {
+	target := thor.BytesToAddress([]byte("override"))
+
+	// the contract does not exist at the target address
+	abi, _ := ABI.New([]byte(abiJSON))
+	m, _ := abi.MethodByName("add")
+	input, err := m.EncodeInput(uint8(1), uint8(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := hexutil.Encode(runtimeBytecode)
+	reqBody := &accounts.BatchCallData{
+		Clauses: accounts.Clauses{
+			accounts.Clause{To: &target, Data: hexutil.Encode(input)},
+		},
+		StateOverrides: utils.StateOverrides{
+			target.String(): &utils.AccountOverride{Code: &code},
+		},
+	}
+	res, statusCode := httpPost(t, ts.URL+"/accounts/*", reqBody)
+	assert.Equal(t, http.StatusOK, statusCode)
+	var results accounts.BatchCallResults
+	if err := json.Unmarshal(res, &results); err != nil {
+		t.Fatal(err)
+	}
+	data, err := hexutil.Decode(results[0].Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ret uint8
+	if err := m.DecodeOutput(data, &ret); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint8(3), ret)
+
+	// transfer from an account funded by the override
+	caller := thor.BytesToAddress([]byte("poor"))
+	amount := math.HexOrDecimal256(*big.NewInt(100))
+	transferBody := &accounts.BatchCallData{
+		Clauses: accounts.Clauses{
+			accounts.Clause{To: &target, Value: &amount},
+		},
+		Caller: &caller,
+	}
+	res, statusCode = httpPost(t, ts.URL+"/accounts/*", transferBody)
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(res, &results); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, results[0].Reverted, "caller has no balance")
+
+	transferBody.StateOverrides = utils.StateOverrides{
+		caller.String(): &utils.AccountOverride{Balance: &amount},
+	}
+	res, statusCode = httpPost(t, ts.URL+"/accounts/*", transferBody)
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(res, &results); err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, results[0].Reverted)
+
+	// TIMESTAMP PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+	timestampCode := "0x4260005260206000f3"
+	timestamp := uint64(12345)
+	blockBody := &accounts.BatchCallData{
+		Clauses: accounts.Clauses{
+			accounts.Clause{To: &target},
+		},
+		StateOverrides: utils.StateOverrides{
+			target.String(): &utils.AccountOverride{Code: &timestampCode},
+		},
+		BlockOverrides: &utils.BlockOverrides{Timestamp: &timestamp},
+	}
+	res, statusCode = httpPost(t, ts.URL+"/accounts/*", blockBody)
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(res, &results); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, hexutil.Encode(common.LeftPadBytes(big.NewInt(12345).Bytes(), 32)), results[0].Data)
+
+	// NUMBER PUSH1 1 SWAP1 SUB BLOCKHASH PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+	blockHashCode := "0x43600190034060005260206000f3"
+	number := uint32(1)
+	blockBody.StateOverrides = utils.StateOverrides{
+		target.String(): &utils.AccountOverride{Code: &blockHashCode},
+	}
+	blockBody.BlockOverrides = &utils.BlockOverrides{Number: &number}
+	res, statusCode = httpPost(t, ts.URL+"/accounts/*?revision=0", blockBody)
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(res, &results); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, genesisBlock.Header().ID().String(), results[0].Data)
+
+	// no hash of blocks after the one next to the revision
+	number = 2
+	res, statusCode = httpPost(t, ts.URL+"/accounts/*?revision=0", blockBody)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "blockOverrides: number exceeds 1\n", string(res))
+
+	// overrides are not persisted
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+target.String()+"/code")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, `{"code":"0x"}`, string(bytes.TrimSpace(res)))
+
+	// bad overrides
+	badCode := "0xzz"
+	for _, overrides := range []utils.StateOverrides{
+		{invalidAddr: &utils.AccountOverride{}},
+		{target.String(): &utils.AccountOverride{Code: &badCode}},
+		{target.String(): &utils.AccountOverride{Storage: map[string]thor.Bytes32{invalidBytes32: {}}}},
+	} {
+		reqBody.StateOverrides = overrides
+		_, statusCode = httpPost(t, ts.URL+"/accounts/*", reqBody)
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+	}
+}
+
 func batchCallWithNonExisitingRevision(t *testing.T) {
 	revision64Len := "0x00000000851caf3cfdb6e899cf5958bfb1ac3413d346d43539627e6be7ec1b4a"
 
@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/vechain/thor/v2/api/transactions"
+	"github.com/vechain/thor/v2/api/utils"
 	"github.com/vechain/thor/v2/runtime"
 	"github.com/vechain/thor/v2/thor"
 )
@@ -20,6 +21,16 @@ type Account struct {
 	HasCode bool                 `json:"hasCode"`
 }
 
+// GetCodeResult result of getting the code of an account.
+type GetCodeResult struct {
+	Code string `json:"code"`
+}
+
+// GetStorageResult result of getting a storage value of an account.
+type GetStorageResult struct {
+	Value string `json:"value"`
+}
+
 // CallData represents contract-call body
 type CallData struct {
 	Value    *math.HexOrDecimal256 `json:"value"`
@@ -100,6 +111,9 @@ type BatchCallData struct {
 	GasPayer   *thor.Address         `json:"gasPayer"`
 	Expiration uint32                `json:"expiration"`
 	BlockRef   string                `json:"blockRef"`
+
+	StateOverrides utils.StateOverrides  `json:"stateOverrides"`
+	BlockOverrides *utils.BlockOverrides `json:"blockOverrides"`
 }
 
 type BatchCallResults []*CallResult
@@ -15,10 +15,15 @@ import (
 	"github.com/vechain/thor/v2/api/accounts"
 	"github.com/vechain/thor/v2/api/blocks"
 	"github.com/vechain/thor/v2/api/debug"
+	"github.com/vechain/thor/v2/api/deployments"
 	"github.com/vechain/thor/v2/api/doc"
 	"github.com/vechain/thor/v2/api/events"
+	"github.com/vechain/thor/v2/api/failures"
+	"github.com/vechain/thor/v2/api/health"
 	"github.com/vechain/thor/v2/api/node"
+	"github.com/vechain/thor/v2/api/pool"
 	"github.com/vechain/thor/v2/api/subscriptions"
+	"github.com/vechain/thor/v2/api/tokentransfers"
 	"github.com/vechain/thor/v2/api/transactions"
 	"github.com/vechain/thor/v2/api/transfers"
 	"github.com/vechain/thor/v2/bft"
@@ -39,20 +44,28 @@ func New(
 	stater *state.Stater,
 	txPool *txpool.TxPool,
 	schedule *schedule.Schedule,
-	logDB *logdb.LogDB,
+	logDB logdb.LogStore,
 	bft bft.Committer,
 	nw node.Network,
+	optimizer node.Optimizer,
 	forkConfig thor.ForkConfig,
+	version string,
 	allowedOrigins string,
 	backtraceLimit uint32,
 	callGasLimit uint64,
 	pprofOn bool,
 	skipLogs bool,
+	logsFailures bool,
+	logsTokenTransfers bool,
 	allowCustomTracer bool,
 	enableReqLogger bool,
 	enableMetrics bool,
 	logsLimit uint64,
+	logsMaxSetSize uint64,
 	allowedTracers []string,
+	readiness health.Options,
+	limiter *RateLimiter,
+	responseCacheSize int,
 	soloMode bool,
 ) (http.HandlerFunc, func()) {
 	origins := strings.Split(strings.TrimSpace(allowedOrigins), ",")
@@ -73,24 +86,43 @@ func New(
 			http.Redirect(w, req, "doc/stoplight-ui/", http.StatusTemporaryRedirect)
 		})
 
-	accounts.New(repo, stater, callGasLimit, forkConfig, bft).
+	nodeLogDB := logDB
+	if skipLogs {
+		nodeLogDB = nil
+	}
+	accounts.New(repo, stater, callGasLimit, forkConfig, bft, nodeLogDB).
 		Mount(router, "/accounts")
 
 	if !skipLogs {
-		events.New(repo, logDB, logsLimit).
+		events.New(repo, logDB, logsLimit, logsMaxSetSize).
 			Mount(router, "/logs/event")
 		transfers.New(repo, logDB, logsLimit).
 			Mount(router, "/logs/transfer")
+		deployments.New(repo, logDB, logsLimit).
+			Mount(router, "/logs/deployment")
+		if logsFailures {
+			failures.New(repo, logDB, logsLimit).
+				Mount(router, "/logs/failure")
+		}
+		if logsTokenTransfers {
+			tokentransfers.New(repo, logDB, logsLimit).
+				Mount(router, "/logs/token-transfer")
+		}
 	}
 	blocks.New(repo, bft).
 		Mount(router, "/blocks")
-	transactions.New(repo, txPool, schedule).
+	transactions.New(repo, txPool, schedule, bft).
 		Mount(router, "/transactions")
 	debug.New(repo, stater, forkConfig, callGasLimit, allowCustomTracer, bft, allowedTracers, soloMode).
 		Mount(router, "/debug")
-	node.New(nw).
+	node.New(nw, repo, bft, nodeLogDB, txPool, optimizer, forkConfig, version,
+		features(skipLogs, logsFailures, logsTokenTransfers, pprofOn, allowCustomTracer, enableMetrics, soloMode)).
 		Mount(router, "/node")
-	subs := subscriptions.New(repo, origins, backtraceLimit, txPool)
+	pool.New(txPool).
+		Mount(router, "/txpool")
+	health.New(repo, nw, nodeLogDB, readiness).
+		Mount(router, "/health")
+	subs := subscriptions.New(repo, origins, backtraceLimit, txPool, bft)
 	subs.Mount(router, "/subscriptions")
 
 	if pprofOn {
@@ -101,15 +133,20 @@ func New(
 		router.PathPrefix("/debug/pprof/").HandlerFunc(pprof.Index)
 	}
 
+	// the limiter goes first, to reject requests early and to pass the key name to metrics
+	if limiter != nil {
+		router.Use(limiter.Middleware)
+	}
 	if enableMetrics {
 		router.Use(metricsMiddleware)
 	}
+	router.Use(newResponseCache(responseCacheSize).Middleware)
 
 	handler := handlers.CompressHandler(router)
 	handler = handlers.CORS(
 		handlers.AllowedOrigins(origins),
-		handlers.AllowedHeaders([]string{"content-type", "x-genesis-id"}),
-		handlers.ExposedHeaders([]string{"x-genesis-id", "x-thorest-ver"}),
+		handlers.AllowedHeaders([]string{"content-type", "x-genesis-id", "last-event-id", "if-none-match", APIKeyHeader}),
+		handlers.ExposedHeaders([]string{"x-genesis-id", "x-thorest-ver", "x-next-cursor", "x-pruned-before", "retry-after", "etag"}),
 	)(handler)
 
 	if enableReqLogger {
@@ -118,3 +155,30 @@ func New(
 
 	return handler.ServeHTTP, subs.Close // subscriptions handles hijacked conns, which need to be closed
 }
+
+// features returns the names of enabled API features.
+func features(skipLogs, logsFailures, logsTokenTransfers, pprofOn, allowCustomTracer, enableMetrics, soloMode bool) []string {
+	features := []string{}
+	if !skipLogs {
+		features = append(features, "logs")
+		if logsFailures {
+			features = append(features, "failures")
+		}
+		if logsTokenTransfers {
+			features = append(features, "tokenTransfers")
+		}
+	}
+	if pprofOn {
+		features = append(features, "pprof")
+	}
+	if allowCustomTracer {
+		features = append(features, "customTracer")
+	}
+	if enableMetrics {
+		features = append(features, "metrics")
+	}
+	if soloMode {
+		features = append(features, "solo")
+	}
+	return features
+}
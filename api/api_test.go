@@ -0,0 +1,132 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/api/health"
+	"github.com/vechain/thor/v2/api/node"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/cmd/thor/solo"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+func newTestServer(t *testing.T, enableMetrics, logsFailures, logsTokenTransfers bool) (*httptest.Server, *chain.Repository) {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	b, _, _, err := genesis.NewDevnet().Build(stater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, _ := chain.NewRepository(db, b)
+	logDB, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := txpool.New(repo, stater, txpool.Options{Limit: 100, LimitPerAccount: 16, MaxLifetime: 10 * 60})
+	t.Cleanup(pool.Close)
+
+	handler, close := New(repo, stater, pool, nil, logDB, solo.NewBFTEngine(repo), &solo.Communicator{}, nil,
+		thor.NoFork, "test", "*", 100, 10_000_000, false, false, logsFailures, logsTokenTransfers, false, false, enableMetrics, 1000, 100, nil,
+		health.Options{}, nil, 1024*1024, false)
+	t.Cleanup(close)
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return ts, repo
+}
+
+// testEventStream checks that events are streamed through the middlewares, which need to support flushing.
+// The beat subject is used since the active subscription metric of it is expected by TestWebsocketMetrics.
+func testEventStream(t *testing.T, enableMetrics bool) {
+	ts, repo := newTestServer(t, enableMetrics, false, false)
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/subscriptions/beat?pos="+repo.GenesisBlock().Header().ID().String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	// the first event is never received if it's not flushed
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	assert.Nil(t, err)
+	assert.Equal(t, "id: "+repo.GenesisBlock().Header().ID().String()+"\n", line)
+}
+
+func TestEventStream(t *testing.T) {
+	testEventStream(t, false)
+}
+
+func TestEventStreamWithMetrics(t *testing.T) {
+	testEventStream(t, true)
+}
+
+// testLogsOption checks that the logs of an option are only served, and listed as a feature, when they are recorded.
+func testLogsOption(t *testing.T, path, feature string, newServer func(enabled bool) *httptest.Server) {
+	for _, enabled := range []bool{false, true} {
+		ts := newServer(enabled)
+
+		resp, err := http.Post(ts.URL+path, "application/json", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if enabled {
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		} else {
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		}
+
+		resp, err = http.Get(ts.URL + "/node/info")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var info node.Info
+		err = json.NewDecoder(resp.Body).Decode(&info)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, enabled, slices.Contains(info.Features, feature))
+	}
+}
+
+func TestLogsFailures(t *testing.T) {
+	testLogsOption(t, "/logs/failure", "failures", func(enabled bool) *httptest.Server {
+		ts, _ := newTestServer(t, false, enabled, false)
+		return ts
+	})
+}
+
+func TestLogsTokenTransfers(t *testing.T) {
+	testLogsOption(t, "/logs/token-transfer", "tokenTransfers", func(enabled bool) *httptest.Server {
+		ts, _ := newTestServer(t, false, false, enabled)
+		return ts
+	})
+}
@@ -60,6 +60,11 @@ func (b *Blocks) handleGetBlock(w http.ResponseWriter, req *http.Request) error
 		}
 	}
 
+	// a finalized block referenced by ID or number never changes
+	if isFinalized && revision.IsFixed() {
+		utils.SetImmutable(w)
+	}
+
 	jSummary := buildJSONBlockSummary(summary, isTrunk, isFinalized)
 	if expanded == "true" {
 		txs, err := b.repo.GetBlockTransactions(summary.Header.ID())
@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vechain/thor/v2/api/blocks"
+	"github.com/vechain/thor/v2/api/utils"
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/cmd/thor/solo"
@@ -55,6 +56,7 @@ func TestBlock(t *testing.T) {
 		"testGetFinalizedBlock":                 testGetFinalizedBlock,
 		"testGetJustifiedBlock":                 testGetJustifiedBlock,
 		"testGetBlockWithRevisionNumberTooHigh": testGetBlockWithRevisionNumberTooHigh,
+		"testGetBlockCacheControl":              testGetBlockCacheControl,
 	} {
 		t.Run(name, tt)
 	}
@@ -157,6 +159,25 @@ func testGetBlockWithRevisionNumberTooHigh(t *testing.T) {
 	assert.Equal(t, "revision: block number out of max uint32", strings.TrimSpace(string(res)))
 }
 
+func testGetBlockCacheControl(t *testing.T) {
+	for path, immutable := range map[string]bool{
+		"/blocks/0": true,
+		"/blocks/" + genesisBlock.Header().ID().String(): true,
+		"/blocks/0?expanded=true":                        true,
+		"/blocks/finalized":                              false,
+		"/blocks/1":                                      false,
+		"/blocks/best":                                   false,
+	} {
+		res, err := http.Get(ts.URL + path) // nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode, path)
+		assert.Equal(t, immutable, utils.IsImmutable(res.Header), path)
+	}
+}
+
 func initBlockServer(t *testing.T) {
 	db := muxdb.NewMem()
 	stater := state.NewStater(db)
@@ -146,8 +146,97 @@ func (d *Debug) traceClause(ctx context.Context, tracer tracers.Tracer, blockID
 		State:       rt.State(),
 	})
 	rt.SetVMConfig(vm.Config{Tracer: tracer})
-	errCh := make(chan error, 1)
 	exec, interrupt := txExec.PrepareNext()
+	if err := execClause(ctx, exec, interrupt, tracer); err != nil {
+		return nil, err
+	}
+	return tracer.GetResult()
+}
+
+// traceBlock executes the txs of the given block once, tracing each clause with a new tracer.
+// If txIndex is given, only the clauses of that tx are traced and the txs after it are not executed.
+func (d *Debug) traceBlock(ctx context.Context, name string, config json.RawMessage, blockID thor.Bytes32, txIndex *uint64, onTrace func(*ClauseTrace) error) error {
+	block, err := d.repo.GetBlock(blockID)
+	if err != nil {
+		if d.repo.IsNotFound(err) {
+			return utils.Forbidden(errors.New("block not found"))
+		}
+		return err
+	}
+	txs := block.Transactions()
+	if txIndex != nil && *txIndex >= uint64(len(txs)) {
+		return utils.Forbidden(errors.New("tx index out of range"))
+	}
+	rt, err := consensus.New(
+		d.repo,
+		d.stater,
+		d.forkConfig,
+	).NewRuntimeForReplay(block.Header(), d.skipPoA)
+	if err != nil {
+		return err
+	}
+
+	for i, tx := range txs {
+		if txIndex != nil && uint64(i) > *txIndex {
+			break
+		}
+		traced := txIndex == nil || uint64(i) == *txIndex
+
+		txExec, err := rt.PrepareTransaction(tx)
+		if err != nil {
+			return err
+		}
+		clauseIndex := uint32(0)
+		for txExec.HasNextClause() {
+			var tracer tracers.Tracer
+			if traced {
+				if tracer, err = d.createTracer(name, config); err != nil {
+					return err
+				}
+				tracer.SetContext(&tracers.Context{
+					BlockID:     blockID,
+					BlockTime:   rt.Context().Time,
+					TxID:        tx.ID(),
+					TxIndex:     uint64(i),
+					ClauseIndex: clauseIndex,
+					State:       rt.State(),
+				})
+				rt.SetVMConfig(vm.Config{Tracer: tracer})
+			} else {
+				rt.SetVMConfig(vm.Config{})
+			}
+
+			exec, interrupt := txExec.PrepareNext()
+			if err := execClause(ctx, exec, interrupt, tracer); err != nil {
+				return err
+			}
+
+			if traced {
+				res, err := tracer.GetResult()
+				if err != nil {
+					return err
+				}
+				if err := onTrace(&ClauseTrace{
+					TxID:        tx.ID(),
+					TxIndex:     uint64(i),
+					ClauseIndex: clauseIndex,
+					Result:      res,
+				}); err != nil {
+					return err
+				}
+			}
+			clauseIndex++
+		}
+		if _, err := txExec.Finalize(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execClause executes the prepared clause, it's interrupted when the ctx is done.
+func execClause(ctx context.Context, exec func() (uint64, *runtime.Output, error), interrupt func(), tracer tracers.Tracer) error {
+	errCh := make(chan error, 1)
 	go func() {
 		_, _, err := exec()
 		errCh <- err
@@ -156,15 +245,92 @@ func (d *Debug) traceClause(ctx context.Context, tracer tracers.Tracer, blockID
 	select {
 	case <-ctx.Done():
 		err := ctx.Err()
-		tracer.Stop(err)
+		if tracer != nil {
+			tracer.Stop(err)
+		}
 		interrupt()
-		return nil, err
+		return err
 	case err := <-errCh:
+		return err
+	}
+}
+
+// streamTraces writes the clause traces as a JSON array, each trace is flushed once it's ready.
+// Errors occurred after the first trace is written are reported as the last element of the array.
+func (d *Debug) streamTraces(w http.ResponseWriter, req *http.Request, opt *TraceClauseOption, blockID thor.Bytes32, txIndex *uint64) error {
+	// fail early if the tracer is not available
+	if _, err := d.createTracer(opt.Name, opt.Config); err != nil {
+		return utils.Forbidden(err)
+	}
+
+	var (
+		started bool
+		enc     = json.NewEncoder(w)
+		flusher = func() {
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+	)
+	err := d.traceBlock(req.Context(), opt.Name, opt.Config, blockID, txIndex, func(trace *ClauseTrace) error {
+		if !started {
+			w.Header().Set("Content-Type", utils.JSONContentType)
+			if _, err := w.Write([]byte("[")); err != nil {
+				return err
+			}
+			started = true
+		} else if _, err := w.Write([]byte(",")); err != nil {
+			return err
+		}
+		if err := enc.Encode(trace); err != nil {
+			return err
+		}
+		flusher()
+		return nil
+	})
+
+	if !started {
 		if err != nil {
-			return nil, err
+			return err
 		}
+		return utils.WriteJSON(w, []*ClauseTrace{})
 	}
-	return tracer.GetResult()
+	if err != nil {
+		if _, err := w.Write([]byte(",")); err != nil {
+			return nil
+		}
+		if err := enc.Encode(utils.M{"error": err.Error()}); err != nil {
+			return nil
+		}
+	}
+	_, _ = w.Write([]byte("]"))
+	return nil
+}
+
+func (d *Debug) handleTraceTransaction(w http.ResponseWriter, req *http.Request) error {
+	var opt TraceClauseOption
+	if err := utils.ParseJSON(req.Body, &opt); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+
+	blockID, txIndex, err := d.parseTxTarget(opt.Target)
+	if err != nil {
+		return err
+	}
+	return d.streamTraces(w, req, &opt, blockID, &txIndex)
+}
+
+func (d *Debug) handleTraceBlock(w http.ResponseWriter, req *http.Request) error {
+	var opt TraceClauseOption
+	if err := utils.ParseJSON(req.Body, &opt); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+
+	blockID, err := thor.ParseBytes32(opt.Target)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "target"))
+	}
+	return d.streamTraces(w, req, &opt, blockID, nil)
 }
 
 func (d *Debug) handleTraceClause(w http.ResponseWriter, req *http.Request) error {
@@ -216,7 +382,7 @@ func (d *Debug) handleTraceCall(w http.ResponseWriter, req *http.Request) error
 		return err
 	}
 
-	res, err := d.traceCall(req.Context(), tracer, summary.Header, st, txCtx, gas, clause)
+	res, err := d.traceCall(req.Context(), tracer, summary.Header, st, txCtx, gas, clause, opt.StateOverrides, opt.BlockOverrides)
 	if err != nil {
 		return err
 	}
@@ -248,25 +414,42 @@ func (d *Debug) createTracer(name string, config json.RawMessage) (tracers.Trace
 	return nil, errors.New("tracer is not defined")
 }
 
-func (d *Debug) traceCall(ctx context.Context, tracer tracers.Tracer, header *block.Header, st *state.State, txCtx *xenv.TransactionContext, gas uint64, clause *tx.Clause) (interface{}, error) {
+func (d *Debug) traceCall(
+	ctx context.Context,
+	tracer tracers.Tracer,
+	header *block.Header,
+	st *state.State,
+	txCtx *xenv.TransactionContext,
+	gas uint64,
+	clause *tx.Clause,
+	stateOverrides utils.StateOverrides,
+	blockOverrides *utils.BlockOverrides,
+) (interface{}, error) {
 	signer, _ := header.Signer()
+	blockCtx := &xenv.BlockContext{
+		Beneficiary: header.Beneficiary(),
+		Signer:      signer,
+		Number:      header.Number(),
+		Time:        header.Timestamp(),
+		GasLimit:    header.GasLimit(),
+		TotalScore:  header.TotalScore(),
+	}
+	if err := blockOverrides.Apply(blockCtx); err != nil {
+		return nil, err
+	}
+	if err := stateOverrides.Apply(st, blockCtx.Time); err != nil {
+		return nil, err
+	}
 
-	rt := runtime.New(
-		d.repo.NewChain(header.ParentID()),
-		st,
-		&xenv.BlockContext{
-			Beneficiary: header.Beneficiary(),
-			Signer:      signer,
-			Number:      header.Number(),
-			Time:        header.Timestamp(),
-			GasLimit:    header.GasLimit(),
-			TotalScore:  header.TotalScore(),
-		},
-		d.forkConfig)
+	chain, err := utils.NewCallChain(d.repo, header, blockCtx)
+	if err != nil {
+		return nil, err
+	}
+	rt := runtime.New(chain, st, blockCtx, d.forkConfig)
 
 	tracer.SetContext(&tracers.Context{
 		BlockID:   header.ID(),
-		BlockTime: header.Timestamp(),
+		BlockTime: blockCtx.Time,
 		State:     st,
 	})
 	rt.SetVMConfig(vm.Config{Tracer: tracer})
@@ -366,35 +549,59 @@ func (d *Debug) parseTarget(target string) (blockID thor.Bytes32, txIndex uint64
 	if err != nil {
 		return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[0]"))
 	}
-	if len(parts[1]) == 64 || len(parts[1]) == 66 {
-		txID, err := thor.ParseBytes32(parts[1])
+	txIndex, err = d.parseTxIndex(blockID, parts[1])
+	if err != nil {
+		return thor.Bytes32{}, 0, 0, err
+	}
+	i, err := strconv.ParseUint(parts[2], 0, 0)
+	if err != nil {
+		return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[2]"))
+	} else if i > math.MaxUint32 {
+		return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.New("invalid target[2]"))
+	}
+	clauseIndex = uint32(i)
+	return
+}
+
+// parseTxTarget parses target in the form of blockID/(txIndex|txID).
+func (d *Debug) parseTxTarget(target string) (blockID thor.Bytes32, txIndex uint64, err error) {
+	parts := strings.Split(target, "/")
+	if len(parts) != 2 {
+		return thor.Bytes32{}, 0, utils.BadRequest(errors.New("target:" + target + " unsupported"))
+	}
+	blockID, err = thor.ParseBytes32(parts[0])
+	if err != nil {
+		return thor.Bytes32{}, 0, utils.BadRequest(errors.WithMessage(err, "target[0]"))
+	}
+	txIndex, err = d.parseTxIndex(blockID, parts[1])
+	if err != nil {
+		return thor.Bytes32{}, 0, err
+	}
+	return
+}
+
+// parseTxIndex parses the tx part of a target, which is either a tx index or a tx ID.
+func (d *Debug) parseTxIndex(blockID thor.Bytes32, part string) (uint64, error) {
+	if len(part) == 64 || len(part) == 66 {
+		txID, err := thor.ParseBytes32(part)
 		if err != nil {
-			return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[1]"))
+			return 0, utils.BadRequest(errors.WithMessage(err, "target[1]"))
 		}
 
 		txMeta, err := d.repo.NewChain(blockID).GetTransactionMeta(txID)
 		if err != nil {
 			if d.repo.IsNotFound(err) {
-				return thor.Bytes32{}, 0, 0, utils.Forbidden(errors.New("transaction not found"))
+				return 0, utils.Forbidden(errors.New("transaction not found"))
 			}
-			return thor.Bytes32{}, 0, 0, err
+			return 0, err
 		}
-		txIndex = txMeta.Index
-	} else {
-		i, err := strconv.ParseUint(parts[1], 0, 0)
-		if err != nil {
-			return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[1]"))
-		}
-		txIndex = i
+		return txMeta.Index, nil
 	}
-	i, err := strconv.ParseUint(parts[2], 0, 0)
+	i, err := strconv.ParseUint(part, 0, 0)
 	if err != nil {
-		return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[2]"))
-	} else if i > math.MaxUint32 {
-		return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.New("invalid target[2]"))
+		return 0, utils.BadRequest(errors.WithMessage(err, "target[1]"))
 	}
-	clauseIndex = uint32(i)
-	return
+	return i, nil
 }
 
 func (d *Debug) handleTraceCallOption(opt *TraceCallOption) (*xenv.TransactionContext, uint64, *tx.Clause, error) {
@@ -468,6 +675,14 @@ func (d *Debug) Mount(root *mux.Router, pathPrefix string) {
 		Methods(http.MethodPost).
 		Name("debug_trace_clause").
 		HandlerFunc(utils.WrapHandlerFunc(d.handleTraceClause))
+	sub.Path("/tracers/transaction").
+		Methods(http.MethodPost).
+		Name("debug_trace_transaction").
+		HandlerFunc(utils.WrapHandlerFunc(d.handleTraceTransaction))
+	sub.Path("/tracers/block").
+		Methods(http.MethodPost).
+		Name("debug_trace_block").
+		HandlerFunc(utils.WrapHandlerFunc(d.handleTraceBlock))
 	sub.Path("/tracers/call").
 		Methods(http.MethodPost).
 		Name("debug_trace_call").
@@ -23,6 +23,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/api/utils"
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/builtin"
 	"github.com/vechain/thor/v2/chain"
@@ -67,6 +68,20 @@ func TestDebug(t *testing.T) {
 		t.Run(name, tt)
 	}
 
+	// /tracers/transaction and /tracers/block endpoints
+	for name, tt := range map[string]func(*testing.T){
+		"testTraceTransactionWithBadTarget":         testTraceTransactionWithBadTarget,
+		"testTraceTransactionWithTxIndexOutOfBound": testTraceTransactionWithTxIndexOutOfBound,
+		"testTraceTransactionWithInvalidTracer":     testTraceTransactionWithInvalidTracer,
+		"testTraceTransaction":                      testTraceTransaction,
+		"testTraceTransactionWithoutClauses":        testTraceTransactionWithoutClauses,
+		"testTraceBlockWithBadBlockID":              testTraceBlockWithBadBlockID,
+		"testTraceBlockWithNonExistingBlockID":      testTraceBlockWithNonExistingBlockID,
+		"testTraceBlock":                            testTraceBlock,
+	} {
+		t.Run(name, tt)
+	}
+
 	// /tracers/call endpoint
 	for name, tt := range map[string]func(*testing.T){
 		"testHandleTraceCallWithMalformedBodyRequest":        testHandleTraceCallWithMalformedBodyRequest,
@@ -80,6 +95,7 @@ func TestDebug(t *testing.T) {
 		"testHandleTraceCallWithBadBlockRef":                 testHandleTraceCallWithBadBlockRef,
 		"testHandleTraceCallWithInvalidLengthBlockRef":       testHandleTraceCallWithInvalidLengthBlockRef,
 		"testTraceCallNextBlock":                             testTraceCallNextBlock,
+		"testHandleTraceCallWithOverrides":                   testHandleTraceCallWithOverrides,
 	} {
 		t.Run(name, tt)
 	}
@@ -288,6 +304,172 @@ func testTraceClauseWithClauseIndexOutOfBound(t *testing.T) {
 	assert.Equal(t, "clause index out of range", strings.TrimSpace(res))
 }
 
+func testTraceTransactionWithBadTarget(t *testing.T) {
+	for _, target := range []string{"", "badTarget", fmt.Sprintf("%s/0/0", blk.Header().ID())} {
+		httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/transaction", &TraceClauseOption{Name: "structLogger", Target: target}, 400)
+	}
+
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/transaction", &TraceClauseOption{
+		Name:   "structLogger",
+		Target: fmt.Sprintf("%s/badTxIndex", blk.Header().ID()),
+	}, 400)
+	assert.Contains(t, res, "target[1]")
+
+	res = httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/transaction", &TraceClauseOption{
+		Name:   "structLogger",
+		Target: fmt.Sprintf("%s/%s", blk.Header().ID(), datagen.RandomHash()),
+	}, 403)
+	assert.Equal(t, "transaction not found", strings.TrimSpace(res))
+}
+
+func testTraceTransactionWithTxIndexOutOfBound(t *testing.T) {
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/transaction", &TraceClauseOption{
+		Name:   "structLogger",
+		Target: fmt.Sprintf("%s/10", blk.Header().ID()),
+	}, 403)
+
+	assert.Equal(t, "tx index out of range", strings.TrimSpace(res))
+}
+
+func testTraceTransactionWithInvalidTracer(t *testing.T) {
+	httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/transaction", &TraceClauseOption{
+		Name:   "randomTracer",
+		Target: fmt.Sprintf("%s/%s", blk.Header().ID(), transaction.ID()),
+	}, 403)
+}
+
+func testTraceTransaction(t *testing.T) {
+	for _, target := range []string{
+		fmt.Sprintf("%s/%s", blk.Header().ID(), transaction.ID()),
+		fmt.Sprintf("%s/1", blk.Header().ID()),
+	} {
+		res := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/transaction", &TraceClauseOption{
+			Name:   "structLogger",
+			Target: target,
+		}, 200)
+
+		var traces []*ClauseTrace
+		if err := json.Unmarshal([]byte(res), &traces); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 2, len(traces))
+		for i, trace := range traces {
+			assert.Equal(t, transaction.ID(), trace.TxID)
+			assert.Equal(t, uint64(1), trace.TxIndex)
+			assert.Equal(t, uint32(i), trace.ClauseIndex)
+			assert.NotNil(t, trace.Result)
+		}
+	}
+}
+
+func testTraceTransactionWithoutClauses(t *testing.T) {
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/transaction", &TraceClauseOption{
+		Name:   "structLogger",
+		Target: fmt.Sprintf("%s/0", blk.Header().ID()),
+	}, 200)
+
+	assert.Equal(t, "[]", strings.TrimSpace(res))
+}
+
+func testTraceBlockWithBadBlockID(t *testing.T) {
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/block", &TraceClauseOption{
+		Name:   "structLogger",
+		Target: "badBlockID",
+	}, 400)
+
+	assert.Contains(t, res, "target")
+}
+
+func testTraceBlockWithNonExistingBlockID(t *testing.T) {
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/block", &TraceClauseOption{
+		Name:   "structLogger",
+		Target: datagen.RandomHash().String(),
+	}, 403)
+
+	assert.Equal(t, "block not found", strings.TrimSpace(res))
+}
+
+func testTraceBlock(t *testing.T) {
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/block", &TraceClauseOption{
+		Name:   "structLogger",
+		Target: blk.Header().ID().String(),
+	}, 200)
+
+	var traces []*ClauseTrace
+	if err := json.Unmarshal([]byte(res), &traces); err != nil {
+		t.Fatal(err)
+	}
+	// the first tx has no clauses
+	assert.Equal(t, 2, len(traces))
+	for i, trace := range traces {
+		assert.Equal(t, transaction.ID(), trace.TxID)
+		assert.Equal(t, uint64(1), trace.TxIndex)
+		assert.Equal(t, uint32(i), trace.ClauseIndex)
+	}
+
+	// result of each clause matches the one traced by /tracers
+	single := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers", &TraceClauseOption{
+		Name:   "structLogger",
+		Target: fmt.Sprintf("%s/%s/1", blk.Header().ID(), transaction.ID()),
+	}, 200)
+	expected, err := json.Marshal(traces[1].Result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, strings.TrimSpace(single), string(expected))
+}
+
+func testHandleTraceCallWithOverrides(t *testing.T) {
+	addr := datagen.RandomAddress()
+	// TIMESTAMP PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+	code := "0x4260005260206000f3"
+	timestamp := uint64(12345)
+	traceCallOption := &TraceCallOption{
+		Name: "structLogger",
+		To:   &addr,
+		StateOverrides: utils.StateOverrides{
+			addr.String(): &utils.AccountOverride{Code: &code},
+		},
+		BlockOverrides: &utils.BlockOverrides{Timestamp: &timestamp},
+	}
+
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/call", traceCallOption, 200)
+
+	var parsedExecutionRes *logger.ExecutionResult
+	if err := json.Unmarshal([]byte(res), &parsedExecutionRes); err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, parsedExecutionRes.Failed)
+	assert.Equal(t, 6, len(parsedExecutionRes.StructLogs))
+	assert.Equal(t, fmt.Sprintf("%064x", timestamp), parsedExecutionRes.ReturnValue)
+
+	// NUMBER PUSH1 1 SWAP1 SUB BLOCKHASH PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+	blockHashCode := "0x43600190034060005260206000f3"
+	number := blk.Header().Number() + 1
+	traceCallOption.StateOverrides = utils.StateOverrides{
+		addr.String(): &utils.AccountOverride{Code: &blockHashCode},
+	}
+	traceCallOption.BlockOverrides = &utils.BlockOverrides{Number: &number}
+	res = httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/call?revision="+blk.Header().ID().String(), traceCallOption, 200)
+	if err := json.Unmarshal([]byte(res), &parsedExecutionRes); err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, parsedExecutionRes.Failed)
+	assert.Equal(t, blk.Header().ID().String()[2:], parsedExecutionRes.ReturnValue)
+
+	// no hash of blocks after the one next to the revision, nor of the fake next block
+	number++
+	httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/call?revision="+blk.Header().ID().String(), traceCallOption, 400)
+	httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/call?revision=next", traceCallOption, 400)
+
+	badCode := "0xzz"
+	traceCallOption.StateOverrides = utils.StateOverrides{
+		addr.String(): &utils.AccountOverride{Code: &badCode},
+	}
+	traceCallOption.BlockOverrides = nil
+	httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/call", traceCallOption, 400)
+}
+
 func testHandleTraceCallWithMalformedBodyRequest(t *testing.T) {
 	badBodyRequest := "badBodyRequest"
 	httpPostAndCheckResponseStatus(t, ts.URL+"/debug/tracers/call", badBodyRequest, 400)
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/v2/api/utils"
 	"github.com/vechain/thor/v2/thor"
 )
 
@@ -18,6 +19,14 @@ type TraceClauseOption struct {
 	Config json.RawMessage `json:"config"` // Config specific to given tracer.
 }
 
+// ClauseTrace trace result of a clause, used when tracing a whole tx or block.
+type ClauseTrace struct {
+	TxID        thor.Bytes32 `json:"txID"`
+	TxIndex     uint64       `json:"txIndex"`
+	ClauseIndex uint32       `json:"clauseIndex"`
+	Result      interface{}  `json:"result"`
+}
+
 type TraceCallOption struct {
 	To         *thor.Address         `json:"to"`
 	Value      *math.HexOrDecimal256 `json:"value"`
@@ -31,6 +40,9 @@ type TraceCallOption struct {
 	BlockRef   string                `json:"blockRef"`
 	Name       string                `json:"name"`   // Tracer
 	Config     json.RawMessage       `json:"config"` // Config specific to given tracer.
+
+	StateOverrides utils.StateOverrides  `json:"stateOverrides"`
+	BlockOverrides *utils.BlockOverrides `json:"blockOverrides"`
 }
 
 type StorageRangeOption struct {
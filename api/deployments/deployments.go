@@ -0,0 +1,80 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package deployments
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/api/events"
+	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/logdb"
+)
+
+type Deployments struct {
+	repo  *chain.Repository
+	db    logdb.LogStore
+	limit uint64
+}
+
+func New(repo *chain.Repository, db logdb.LogStore, logsLimit uint64) *Deployments {
+	return &Deployments{
+		repo,
+		db,
+		logsLimit,
+	}
+}
+
+// filter queries deployments with option, the cursor pointing to the last deployment is also returned.
+func (d *Deployments) filter(ctx context.Context, filter *DeploymentFilter) ([]*FilteredDeployment, *logdb.Cursor, error) {
+	rng, err := events.ConvertRange(d.repo.NewBestChain(), filter.Range)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deployments, err := d.db.FilterDeployments(ctx, &logdb.DeploymentFilter{
+		CriteriaSet: filter.CriteriaSet,
+		Range:       rng,
+		Options:     filter.Options,
+		Order:       filter.Order,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	results := make([]*FilteredDeployment, len(deployments))
+	for i, deployment := range deployments {
+		results[i] = ConvertDeployment(deployment)
+	}
+	var cursor *logdb.Cursor
+	if len(deployments) > 0 {
+		last := deployments[len(deployments)-1]
+		cursor = logdb.NewCursor(last.BlockNumber, last.Index)
+	}
+	return results, cursor, nil
+}
+
+func (d *Deployments) handleFilterDeployments(w http.ResponseWriter, req *http.Request) error {
+	var filter DeploymentFilter
+	if err := utils.ParseJSON(req.Body, &filter); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	return events.WriteFiltered(w, filter.Options, d.limit, func(options *logdb.Options) ([]*FilteredDeployment, *logdb.Cursor, error) {
+		filter.Options = options
+		return d.filter(req.Context(), &filter)
+	})
+}
+
+func (d *Deployments) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("").
+		Methods(http.MethodPost).
+		Name("logs_filter_deployment").
+		HandlerFunc(utils.WrapHandlerFunc(d.handleFilterDeployments))
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package deployments_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/api/deployments"
+	"github.com/vechain/thor/v2/api/events"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/test/logsapi"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+const defaultLogLimit uint64 = 1000
+
+func TestDeployments(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ts := logsapi.NewServer(t, func(repo *chain.Repository, router *mux.Router) {
+		deployments.New(repo, db, defaultLogLimit).Mount(router, "/deployments")
+	})
+
+	res, err := http.Post(ts.URL+"/deployments", "application/x-www-form-urlencoded", bytes.NewReader([]byte{0x00, 0x01, 0x02}))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	// the first two deployers deploy alternately in blocks #1..#4
+	deployers := insertBlocks(t, db, 4)
+
+	var all []*deployments.FilteredDeployment
+	body, statusCode := logsapi.Post(t, ts.URL+"/deployments", deployments.DeploymentFilter{})
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(body, &all); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, all, 4)
+	for i, d := range all {
+		assert.Equal(t, uint32(i+1), d.Meta.BlockNumber)
+		assert.Equal(t, deployers[i%2], d.Deployer)
+		assert.Equal(t, thor.CreateContractAddress(d.Meta.TxID, 0, 0), d.Address)
+	}
+
+	tests := []struct {
+		name   string
+		filter deployments.DeploymentFilter
+		want   []*deployments.FilteredDeployment
+	}{
+		{"by deployer", deployments.DeploymentFilter{CriteriaSet: []*logdb.DeploymentCriteria{{Deployer: &deployers[1]}}}, []*deployments.FilteredDeployment{all[1], all[3]}},
+		{"by address", deployments.DeploymentFilter{CriteriaSet: []*logdb.DeploymentCriteria{{Address: &all[2].Address}}}, all[2:3]},
+		{"by range", deployments.DeploymentFilter{Range: &events.Range{Unit: events.BlockRangeType, From: 2, To: 3}}, all[1:3]},
+		{"desc", deployments.DeploymentFilter{Order: logdb.DESC, Options: &logdb.Options{Limit: 1}}, all[3:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, statusCode := logsapi.Post(t, ts.URL+"/deployments", tt.filter)
+			assert.Equal(t, http.StatusOK, statusCode)
+			var got []*deployments.FilteredDeployment
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// insertBlocks writes n blocks, each with a tx creating a contract by the first clause, and returns the deployers.
+func insertBlocks(t *testing.T, db *logdb.LogDB, n int) []thor.Address {
+	var deployers []thor.Address
+	for _, acc := range genesis.DevAccounts()[:2] {
+		deployers = append(deployers, acc.Address)
+	}
+
+	b := new(block.Builder).Build()
+	w := db.NewWriter()
+	for i := 0; i < n; i++ {
+		trx := new(tx.Builder).
+			Clause(tx.NewClause(nil)).
+			Clause(tx.NewClause(&deployers[0])).
+			Nonce(uint64(i)).
+			Build()
+		sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[i%2].PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		builder := new(block.Builder).Transaction(trx.WithSignature(sig))
+		if i > 0 {
+			builder.ParentID(b.Header().ID())
+		}
+		b = builder.Build()
+		if err := w.Write(b, tx.Receipts{{Outputs: []*tx.Output{{}, {}}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	return deployers
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package deployments
+
+import (
+	"github.com/vechain/thor/v2/api/events"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/thor"
+)
+
+type LogMeta struct {
+	BlockID        thor.Bytes32 `json:"blockID"`
+	BlockNumber    uint32       `json:"blockNumber"`
+	BlockTimestamp uint64       `json:"blockTimestamp"`
+	TxID           thor.Bytes32 `json:"txID"`
+	TxOrigin       thor.Address `json:"txOrigin"`
+	ClauseIndex    uint32       `json:"clauseIndex"`
+}
+
+// FilteredDeployment is a contract created by a clause.
+type FilteredDeployment struct {
+	Address  thor.Address `json:"address"`
+	Deployer thor.Address `json:"deployer"`
+	Meta     LogMeta      `json:"meta"`
+}
+
+// ConvertDeployment converts the deployment stored in the log db.
+func ConvertDeployment(d *logdb.Deployment) *FilteredDeployment {
+	return &FilteredDeployment{
+		Address:  d.Address,
+		Deployer: d.TxOrigin,
+		Meta: LogMeta{
+			BlockID:        d.BlockID,
+			BlockNumber:    d.BlockNumber,
+			BlockTimestamp: d.BlockTime,
+			TxID:           d.TxID,
+			TxOrigin:       d.TxOrigin,
+			ClauseIndex:    d.ClauseIndex,
+		},
+	}
+}
+
+type DeploymentFilter struct {
+	CriteriaSet []*logdb.DeploymentCriteria
+	Range       *events.Range
+	Options     *logdb.Options
+	Order       logdb.Order //default asc
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
@@ -18,35 +19,118 @@ import (
 )
 
 type Events struct {
-	repo  *chain.Repository
-	db    *logdb.LogDB
-	limit uint64
+	repo       *chain.Repository
+	db         logdb.LogStore
+	limit      uint64
+	maxSetSize uint64
 }
 
-func New(repo *chain.Repository, db *logdb.LogDB, logsLimit uint64) *Events {
+func New(repo *chain.Repository, db logdb.LogStore, logsLimit uint64, maxSetSize uint64) *Events {
 	return &Events{
 		repo,
 		db,
 		logsLimit,
+		maxSetSize,
 	}
 }
 
-// Filter query events with option
-func (e *Events) filter(ctx context.Context, ef *EventFilter) ([]*FilteredEvent, error) {
+// Filter query events with option, the cursor pointing to the last event is also returned
+func (e *Events) filter(ctx context.Context, header http.Header, ef *EventFilter) ([]*FilteredEvent, *logdb.Cursor, error) {
 	chain := e.repo.NewBestChain()
 	filter, err := convertEventFilter(chain, ef)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if status := e.db.PruneStatus(); status.EventsPruned(filter) {
+		if filter.Range, err = ClampPruned(header, status, filter.Range); err != nil {
+			return nil, nil, err
+		}
 	}
 	events, err := e.db.FilterEvents(ctx, filter)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	fes := make([]*FilteredEvent, len(events))
 	for i, e := range events {
 		fes[i] = convertEvent(e)
 	}
-	return fes, nil
+	var cursor *logdb.Cursor
+	if len(events) > 0 {
+		last := events[len(events)-1]
+		cursor = logdb.NewCursor(last.BlockNumber, last.Index)
+	}
+	return fes, cursor, nil
+}
+
+// PrunedError returns the error of querying pruned logs.
+func PrunedError(status *logdb.PruneStatus) error {
+	return utils.HTTPError(fmt.Errorf("logs before block %d are pruned, except those of kept addresses", status.BlockNum), http.StatusGone)
+}
+
+// ClampPruned narrows the range of a query on logs which may be pruned to the blocks not pruned, and notes it
+// by the pruned header. It fails if all blocks of the range are pruned.
+func ClampPruned(header http.Header, status *logdb.PruneStatus, rng *logdb.Range) (*logdb.Range, error) {
+	clamped := status.Clamp(rng)
+	if clamped == nil {
+		return nil, PrunedError(status)
+	}
+	header.Set(utils.PrunedBeforeHeader, strconv.FormatUint(uint64(status.BlockNum), 10))
+	return clamped, nil
+}
+
+// WriteFiltered validates the options of a log filter, filters logs with them and writes the logs, with the cursor
+// pointing to the last one in the header. Absent options are set to the limit +1, to reject more logs than the limit.
+func WriteFiltered[T any](
+	w http.ResponseWriter,
+	options *logdb.Options,
+	limit uint64,
+	filter func(options *logdb.Options) ([]T, *logdb.Cursor, error),
+) error {
+	if options != nil && options.Limit > limit {
+		return utils.Forbidden(fmt.Errorf("options.limit exceeds the maximum allowed value of %d", limit))
+	}
+	if options != nil && options.Cursor != nil && options.Offset != 0 {
+		return utils.BadRequest(errors.New("options.offset must be 0 when options.cursor is set"))
+	}
+	if options == nil {
+		options = &logdb.Options{
+			Offset: 0,
+			Limit:  limit + 1,
+		}
+	}
+
+	logs, cursor, err := filter(options)
+	if err != nil {
+		return err
+	}
+
+	// ensure the result size is less than the configured limit
+	if len(logs) > int(limit) {
+		return utils.Forbidden(fmt.Errorf("the number of filtered logs exceeds the maximum allowed value of %d, please use pagination", limit))
+	}
+
+	if cursor != nil {
+		w.Header().Set(utils.NextCursorHeader, cursor.String())
+	}
+	return utils.WriteJSON(w, logs)
+}
+
+// checkSetSize ensures sets of the criteria are within the maximum size.
+func (e *Events) checkSetSize(criteriaSet []*EventCriteria) error {
+	for i, criteria := range criteriaSet {
+		if criteria == nil {
+			continue
+		}
+		if uint64(len(criteria.AddressSet)) > e.maxSetSize {
+			return utils.Forbidden(fmt.Errorf("criteriaSet[%d].address exceeds the maximum set size of %d", i, e.maxSetSize))
+		}
+		for j, set := range criteria.TopicSets {
+			if uint64(len(set)) > e.maxSetSize {
+				return utils.Forbidden(fmt.Errorf("criteriaSet[%d].topic%d exceeds the maximum set size of %d", i, j, e.maxSetSize))
+			}
+		}
+	}
+	return nil
 }
 
 func (e *Events) handleFilter(w http.ResponseWriter, req *http.Request) error {
@@ -54,29 +138,66 @@ func (e *Events) handleFilter(w http.ResponseWriter, req *http.Request) error {
 	if err := utils.ParseJSON(req.Body, &filter); err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "body"))
 	}
+	if err := e.checkSetSize(filter.CriteriaSet); err != nil {
+		return err
+	}
+	return WriteFiltered(w, filter.Options, e.limit, func(options *logdb.Options) ([]*FilteredEvent, *logdb.Cursor, error) {
+		filter.Options = options
+		return e.filter(req.Context(), w.Header(), &filter)
+	})
+}
+
+func (e *Events) handleCount(w http.ResponseWriter, req *http.Request) error {
+	var filter EventCountFilter
+	if err := utils.ParseJSON(req.Body, &filter); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
 	if filter.Options != nil && filter.Options.Limit > e.limit {
 		return utils.Forbidden(fmt.Errorf("options.limit exceeds the maximum allowed value of %d", e.limit))
 	}
+	if filter.Options != nil && filter.Options.Cursor != nil {
+		return utils.BadRequest(errors.New("options.cursor is not supported"))
+	}
+	if err := e.checkSetSize(filter.CriteriaSet); err != nil {
+		return err
+	}
 	if filter.Options == nil {
-		// if filter.Options is nil, set to the default limit +1
-		// to detect whether there are more logs than the default limit
+		// set to the default limit +1 to detect whether there are more buckets than the default limit
 		filter.Options = &logdb.Options{
 			Offset: 0,
 			Limit:  e.limit + 1,
 		}
 	}
 
-	fes, err := e.filter(req.Context(), &filter)
+	ef, err := convertEventFilter(e.repo.NewBestChain(), &EventFilter{
+		CriteriaSet: filter.CriteriaSet,
+		Range:       filter.Range,
+		Options:     filter.Options,
+		Order:       filter.Order,
+	})
+	if err != nil {
+		return err
+	}
+	if status := e.db.PruneStatus(); status.EventsPruned(ef) {
+		if ef.Range, err = ClampPruned(w.Header(), status, ef.Range); err != nil {
+			return err
+		}
+	}
+	counts, err := e.db.CountEvents(req.Context(), ef, filter.Bucket)
 	if err != nil {
 		return err
 	}
 
 	// ensure the result size is less than the configured limit
-	if len(fes) > int(e.limit) {
-		return utils.Forbidden(fmt.Errorf("the number of filtered logs exceeds the maximum allowed value of %d, please use pagination", e.limit))
+	if len(counts) > int(e.limit) {
+		return utils.Forbidden(fmt.Errorf("the number of buckets exceeds the maximum allowed value of %d, please use pagination", e.limit))
 	}
 
-	return utils.WriteJSON(w, fes)
+	results := make([]*EventCount, len(counts))
+	for i, c := range counts {
+		results[i] = &EventCount{Time: c.BucketTime, Count: c.Count}
+	}
+	return utils.WriteJSON(w, results)
 }
 
 func (e *Events) Mount(root *mux.Router, pathPrefix string) {
@@ -86,4 +207,8 @@ func (e *Events) Mount(root *mux.Router, pathPrefix string) {
 		Methods(http.MethodPost).
 		Name("logs_filter_event").
 		HandlerFunc(utils.WrapHandlerFunc(e.handleFilter))
+	sub.Path("/count").
+		Methods(http.MethodPost).
+		Name("logs_count_event").
+		HandlerFunc(utils.WrapHandlerFunc(e.handleCount))
 }
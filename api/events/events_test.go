@@ -7,16 +7,19 @@ package events_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/v2/api/events"
+	"github.com/vechain/thor/v2/api/utils"
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/genesis"
@@ -27,7 +30,10 @@ import (
 	"github.com/vechain/thor/v2/tx"
 )
 
-const defaultLogLimit uint64 = 1000
+const (
+	defaultLogLimit   uint64 = 1000
+	defaultMaxSetSize uint64 = 256
+)
 
 var ts *httptest.Server
 
@@ -38,7 +44,7 @@ var (
 
 func TestEmptyEvents(t *testing.T) {
 	db := createDb(t)
-	initEventServer(t, db, defaultLogLimit)
+	initEventServer(t, db, defaultLogLimit, defaultMaxSetSize)
 	defer ts.Close()
 
 	for name, tt := range map[string]func(*testing.T){
@@ -51,7 +57,7 @@ func TestEmptyEvents(t *testing.T) {
 
 func TestEvents(t *testing.T) {
 	db := createDb(t)
-	initEventServer(t, db, defaultLogLimit)
+	initEventServer(t, db, defaultLogLimit, defaultMaxSetSize)
 	defer ts.Close()
 
 	blocksToInsert := 5
@@ -61,7 +67,7 @@ func TestEvents(t *testing.T) {
 
 func TestOption(t *testing.T) {
 	db := createDb(t)
-	initEventServer(t, db, 5)
+	initEventServer(t, db, 5, defaultMaxSetSize)
 	defer ts.Close()
 	insertBlocks(t, db, 5)
 
@@ -99,6 +105,228 @@ func TestOption(t *testing.T) {
 	assert.Equal(t, "the number of filtered logs exceeds the maximum allowed value of 5, please use pagination", strings.Trim(string(res), "\n"))
 }
 
+func TestCursor(t *testing.T) {
+	db := createDb(t)
+	initEventServer(t, db, defaultLogLimit, defaultMaxSetSize)
+	defer ts.Close()
+	insertBlocks(t, db, 5)
+
+	res, _ := httpPost(t, ts.URL+"/events", events.EventFilter{})
+	var allLogs []*events.FilteredEvent
+	if err := json.Unmarshal(res, &allLogs); err != nil {
+		t.Fatal(err)
+	}
+
+	filter := events.EventFilter{
+		Options: &logdb.Options{Limit: 2},
+	}
+
+	var (
+		pagedLogs []*events.FilteredEvent
+		pages     int
+	)
+	for {
+		res, cursor := httpPostWithCursor(t, ts.URL+"/events", filter)
+		var tLogs []*events.FilteredEvent
+		if err := json.Unmarshal(res, &tLogs); err != nil {
+			t.Fatal(err)
+		}
+		if len(tLogs) == 0 {
+			assert.Empty(t, cursor)
+			break
+		}
+		assert.NotEmpty(t, cursor)
+		pagedLogs = append(pagedLogs, tLogs...)
+		pages++
+
+		filter.Options.Cursor = new(logdb.Cursor)
+		if err := filter.Options.Cursor.UnmarshalText([]byte(cursor)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.Equal(t, (len(allLogs)+1)/2, pages)
+	assert.Equal(t, allLogs, pagedLogs)
+
+	// cursor can not be combined with offset
+	filter.Options.Offset = 1
+	res, statusCode := httpPost(t, ts.URL+"/events", filter)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "options.offset must be 0 when options.cursor is set", strings.TrimSpace(string(res)))
+
+	// malformed cursor
+	res, statusCode = httpPost(t, ts.URL+"/events", map[string]interface{}{"options": map[string]interface{}{"limit": 2, "cursor": "bad"}})
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Contains(t, string(res), "invalid cursor")
+}
+
+func TestSets(t *testing.T) {
+	db := createDb(t)
+	initEventServer(t, db, defaultLogLimit, 2)
+	defer ts.Close()
+	insertBlocks(t, db, 5)
+
+	res, _ := httpPost(t, ts.URL+"/events", events.EventFilter{})
+	var allLogs []*events.FilteredEvent
+	if err := json.Unmarshal(res, &allLogs); err != nil {
+		t.Fatal(err)
+	}
+
+	other := thor.BytesToBytes32([]byte("other"))
+	filter := events.EventFilter{
+		CriteriaSet: []*events.EventCriteria{{
+			AddressSet: []thor.Address{addr, thor.BytesToAddress([]byte("other"))},
+			TopicSets:  [5][]thor.Bytes32{{other, topic}},
+		}},
+	}
+	res, statusCode := httpPost(t, ts.URL+"/events", filter)
+	assert.Equal(t, http.StatusOK, statusCode)
+	var tLogs []*events.FilteredEvent
+	if err := json.Unmarshal(res, &tLogs); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, allLogs, tLogs)
+
+	// sets are ANDed with the other fields
+	filter.CriteriaSet[0].Topic1 = &other
+	res, statusCode = httpPost(t, ts.URL+"/events", filter)
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(res, &tLogs); err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, tLogs)
+
+	// single values and arrays can be mixed in the JSON criteria
+	res, statusCode = httpPost(t, ts.URL+"/events", map[string]interface{}{
+		"criteriaSet": []map[string]interface{}{{
+			"address": addr.String(),
+			"topic0":  []string{topic.String()},
+			"topic4":  topic.String(),
+		}},
+	})
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(res, &tLogs); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, allLogs, tLogs)
+
+	// set size exceeds the limit
+	filter.CriteriaSet[0].TopicSets[2] = []thor.Bytes32{topic, other, other}
+	res, statusCode = httpPost(t, ts.URL+"/events", filter)
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.Equal(t, "criteriaSet[0].topic2 exceeds the maximum set size of 2", strings.TrimSpace(string(res)))
+
+	// empty set
+	res, statusCode = httpPost(t, ts.URL+"/events", map[string]interface{}{
+		"criteriaSet": []map[string]interface{}{{"address": []thor.Address{}}},
+	})
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Contains(t, string(res), "address: empty set")
+
+	// unknown fields are still rejected
+	res, statusCode = httpPost(t, ts.URL+"/events", map[string]interface{}{
+		"criteriaSet": []map[string]interface{}{{"topic5": topic.String()}},
+	})
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Contains(t, string(res), "unknown field")
+}
+
+func TestCount(t *testing.T) {
+	db := createDb(t)
+	initEventServer(t, db, defaultLogLimit, defaultMaxSetSize)
+	defer ts.Close()
+	insertBlocks(t, db, 5)
+
+	res, _ := httpPost(t, ts.URL+"/events", events.EventFilter{})
+	var allLogs []*events.FilteredEvent
+	if err := json.Unmarshal(res, &allLogs); err != nil {
+		t.Fatal(err)
+	}
+
+	// the blocks inserted have zero timestamps, so the same in a single bucket
+	for _, bucket := range []uint64{0, 60} {
+		res, statusCode := httpPost(t, ts.URL+"/events/count", events.EventCountFilter{
+			CriteriaSet: []*events.EventCriteria{{Address: &addr}},
+			Bucket:      bucket,
+		})
+		assert.Equal(t, http.StatusOK, statusCode)
+		var counts []*events.EventCount
+		if err := json.Unmarshal(res, &counts); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []*events.EventCount{{Time: 0, Count: uint64(len(allLogs))}}, counts)
+	}
+
+	other := thor.BytesToAddress([]byte("other"))
+	res, statusCode := httpPost(t, ts.URL+"/events/count", events.EventCountFilter{
+		CriteriaSet: []*events.EventCriteria{{Address: &other}},
+	})
+	assert.Equal(t, http.StatusOK, statusCode)
+	var counts []*events.EventCount
+	if err := json.Unmarshal(res, &counts); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*events.EventCount{{Time: 0, Count: 0}}, counts)
+
+	res, statusCode = httpPost(t, ts.URL+"/events/count", events.EventCountFilter{Options: &logdb.Options{Limit: defaultLogLimit + 1}})
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.Equal(t, "options.limit exceeds the maximum allowed value of 1000", strings.TrimSpace(string(res)))
+
+	res, statusCode = httpPost(t, ts.URL+"/events/count", events.EventCountFilter{Options: &logdb.Options{Limit: 1, Cursor: logdb.NewCursor(1, 0)}})
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "options.cursor is not supported", strings.TrimSpace(string(res)))
+}
+
+func TestPruned(t *testing.T) {
+	// a file db, not to prune the shared in-memory one
+	db, err := logdb.New(filepath.Join(t.TempDir(), "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	initEventServer(t, db, defaultLogLimit, defaultMaxSetSize)
+	defer ts.Close()
+	insertBlocks(t, db, 5)
+
+	kept := thor.BytesToAddress([]byte("kept"))
+	if err := db.Prune(context.Background(), 4, []thor.Address{kept}); err != nil {
+		t.Fatal(err)
+	}
+
+	// ranges partly pruned are clamped
+	for _, url := range []string{ts.URL + "/events", ts.URL + "/events/count"} {
+		for _, filter := range []events.EventFilter{
+			{},
+			{Range: &events.Range{Unit: events.BlockRangeType, From: 1, To: 10}},
+		} {
+			res, header := httpPostWithHeader(t, url, filter, http.StatusOK)
+			assert.Equal(t, "4", header.Get(utils.PrunedBeforeHeader))
+			if url == ts.URL+"/events" {
+				var fes []*events.FilteredEvent
+				if err := json.Unmarshal(res, &fes); err != nil {
+					t.Fatal(err)
+				}
+				assert.Len(t, fes, 3)
+				for _, fe := range fes {
+					assert.GreaterOrEqual(t, fe.Meta.BlockNumber, uint32(4))
+				}
+			}
+		}
+	}
+
+	// ranges fully pruned are gone
+	res, _ := httpPostWithHeader(t, ts.URL+"/events", events.EventFilter{Range: &events.Range{Unit: events.BlockRangeType, From: 1, To: 3}}, http.StatusGone)
+	assert.Equal(t, "logs before block 4 are pruned, except those of kept addresses", strings.TrimSpace(string(res)))
+
+	// not clamped
+	for _, filter := range []events.EventFilter{
+		{Range: &events.Range{Unit: events.BlockRangeType, From: 4, To: 10}},
+		{CriteriaSet: []*events.EventCriteria{{Address: &kept}}},
+	} {
+		_, header := httpPostWithHeader(t, ts.URL+"/events", filter, http.StatusOK)
+		assert.Empty(t, header.Get(utils.PrunedBeforeHeader))
+	}
+}
+
 // Test functions
 func testEventsBadRequest(t *testing.T) {
 	badBody := []byte{0x00, 0x01, 0x02}
@@ -171,10 +399,31 @@ func testEventWithBlocks(t *testing.T, expectedBlocks int) {
 	for _, tLog := range tLogs {
 		assert.NotEmpty(t, tLog)
 	}
+
+	// Test with tx criteria, the inserted blocks have no txs
+	txFilter := events.EventFilter{
+		CriteriaSet: []*events.EventCriteria{{
+			TxOrigin: &thor.Address{},
+		}},
+	}
+	res, statusCode = httpPost(t, ts.URL+"/events", txFilter)
+	if err := json.Unmarshal(res, &tLogs); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, expectedBlocks, len(tLogs))
+
+	txFilter.CriteriaSet[0].TxID = &topic
+	res, statusCode = httpPost(t, ts.URL+"/events", txFilter)
+	if err := json.Unmarshal(res, &tLogs); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Empty(t, tLogs)
 }
 
 // Init functions
-func initEventServer(t *testing.T, logDb *logdb.LogDB, limit uint64) {
+func initEventServer(t *testing.T, logDb *logdb.LogDB, limit uint64, maxSetSize uint64) {
 	router := mux.NewRouter()
 
 	muxDb := muxdb.NewMem()
@@ -188,7 +437,7 @@ func initEventServer(t *testing.T, logDb *logdb.LogDB, limit uint64) {
 
 	repo, _ := chain.NewRepository(muxDb, b)
 
-	events.New(repo, logDb, limit).Mount(router, "/events")
+	events.New(repo, logDb, limit, maxSetSize).Mount(router, "/events")
 	ts = httptest.NewServer(router)
 }
 
@@ -218,6 +467,42 @@ func httpPost(t *testing.T, url string, body interface{}) ([]byte, int) {
 	return r, res.StatusCode
 }
 
+func httpPostWithHeader(t *testing.T, url string, body interface{}, statusCode int) ([]byte, http.Header) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.Post(url, "application/x-www-form-urlencoded", bytes.NewReader(data)) // nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, statusCode, res.StatusCode)
+	return r, res.Header
+}
+
+func httpPostWithCursor(t *testing.T, url string, body interface{}) ([]byte, string) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.Post(url, "application/x-www-form-urlencoded", bytes.NewReader(data)) // nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	return r, res.Header.Get(utils.NextCursorHeader)
+}
+
 func insertBlocks(t *testing.T, db *logdb.LogDB, n int) {
 	b := new(block.Builder).Build()
 	for i := 0; i < n; i++ {
@@ -6,6 +6,9 @@
 package events
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 
@@ -90,8 +93,98 @@ func (e *FilteredEvent) String() string {
 }
 
 type EventCriteria struct {
-	Address *thor.Address `json:"address"`
+	Address  *thor.Address `json:"address"`
+	TxID     *thor.Bytes32 `json:"txID"`
+	TxOrigin *thor.Address `json:"txOrigin"`
 	TopicSet
+
+	// AddressSet and TopicSets are given in JSON as arrays in place of the single address or topic,
+	// matching any of the values.
+	AddressSet []thor.Address    `json:"-"`
+	TopicSets  [5][]thor.Bytes32 `json:"-"`
+}
+
+// eventCriteriaJSON is the JSON form of EventCriteria, where the address and topics can be either a value or an array.
+type eventCriteriaJSON struct {
+	Address  json.RawMessage `json:"address"`
+	TxID     *thor.Bytes32   `json:"txID"`
+	TxOrigin *thor.Address   `json:"txOrigin"`
+	Topic0   json.RawMessage `json:"topic0"`
+	Topic1   json.RawMessage `json:"topic1"`
+	Topic2   json.RawMessage `json:"topic2"`
+	Topic3   json.RawMessage `json:"topic3"`
+	Topic4   json.RawMessage `json:"topic4"`
+}
+
+// unmarshalOneOrSet decodes either a single value or a non-empty array of values.
+func unmarshalOneOrSet[T any](data json.RawMessage, one **T, set *[]T) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if data[0] != '[' {
+		return json.Unmarshal(data, one)
+	}
+	if err := json.Unmarshal(data, set); err != nil {
+		return err
+	}
+	if len(*set) == 0 {
+		return errors.New("empty set")
+	}
+	return nil
+}
+
+func (c *EventCriteria) UnmarshalJSON(data []byte) error {
+	var raw eventCriteriaJSON
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+
+	var criteria EventCriteria
+	if err := unmarshalOneOrSet(raw.Address, &criteria.Address, &criteria.AddressSet); err != nil {
+		return fmt.Errorf("address: %w", err)
+	}
+	topics := [5]**thor.Bytes32{&criteria.Topic0, &criteria.Topic1, &criteria.Topic2, &criteria.Topic3, &criteria.Topic4}
+	for i, data := range []json.RawMessage{raw.Topic0, raw.Topic1, raw.Topic2, raw.Topic3, raw.Topic4} {
+		if err := unmarshalOneOrSet(data, topics[i], &criteria.TopicSets[i]); err != nil {
+			return fmt.Errorf("topic%v: %w", i, err)
+		}
+	}
+	criteria.TxID = raw.TxID
+	criteria.TxOrigin = raw.TxOrigin
+	*c = criteria
+	return nil
+}
+
+func (c EventCriteria) MarshalJSON() ([]byte, error) {
+	// oneOrSet returns the set if not empty, otherwise the single value
+	oneOrSet := func(one any, set any, n int) any {
+		if n > 0 {
+			return set
+		}
+		return one
+	}
+	return json.Marshal(&struct {
+		Address  any           `json:"address"`
+		TxID     *thor.Bytes32 `json:"txID"`
+		TxOrigin *thor.Address `json:"txOrigin"`
+		Topic0   any           `json:"topic0"`
+		Topic1   any           `json:"topic1"`
+		Topic2   any           `json:"topic2"`
+		Topic3   any           `json:"topic3"`
+		Topic4   any           `json:"topic4"`
+	}{
+		oneOrSet(c.Address, c.AddressSet, len(c.AddressSet)),
+		c.TxID,
+		c.TxOrigin,
+		oneOrSet(c.Topic0, c.TopicSets[0], len(c.TopicSets[0])),
+		oneOrSet(c.Topic1, c.TopicSets[1], len(c.TopicSets[1])),
+		oneOrSet(c.Topic2, c.TopicSets[2], len(c.TopicSets[2])),
+		oneOrSet(c.Topic3, c.TopicSets[3], len(c.TopicSets[3])),
+		oneOrSet(c.Topic4, c.TopicSets[4], len(c.TopicSets[4])),
+	})
 }
 
 type EventFilter struct {
@@ -121,14 +214,34 @@ func convertEventFilter(chain *chain.Chain, filter *EventFilter) (*logdb.EventFi
 			topics[3] = criterion.Topic3
 			topics[4] = criterion.Topic4
 			f.CriteriaSet[i] = &logdb.EventCriteria{
-				Address: criterion.Address,
-				Topics:  topics,
+				Address:    criterion.Address,
+				Topics:     topics,
+				TxID:       criterion.TxID,
+				TxOrigin:   criterion.TxOrigin,
+				AddressSet: criterion.AddressSet,
+				TopicSets:  criterion.TopicSets,
 			}
 		}
 	}
 	return f, nil
 }
 
+// EventCountFilter filters events to count, grouped by buckets of block time if bucket is not zero.
+// The order and options apply to buckets.
+type EventCountFilter struct {
+	CriteriaSet []*EventCriteria `json:"criteriaSet"`
+	Range       *Range           `json:"range"`
+	Bucket      uint64           `json:"bucket"` // in seconds
+	Options     *logdb.Options   `json:"options"`
+	Order       logdb.Order      `json:"order"`
+}
+
+// EventCount is the number of events within a bucket of block time.
+type EventCount struct {
+	Time  uint64 `json:"time"` // start of the bucket, 0 if not grouped
+	Count uint64 `json:"count"`
+}
+
 type RangeType string
 
 const (
@@ -0,0 +1,92 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package failures
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/api/events"
+	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/logdb"
+)
+
+type Failures struct {
+	repo  *chain.Repository
+	db    logdb.LogStore
+	limit uint64
+}
+
+func New(repo *chain.Repository, db logdb.LogStore, logsLimit uint64) *Failures {
+	return &Failures{
+		repo,
+		db,
+		logsLimit,
+	}
+}
+
+// filter queries failures with option, the cursor pointing to the last failure is also returned.
+func (f *Failures) filter(ctx context.Context, header http.Header, filter *FailureFilter) ([]*FilteredFailure, *logdb.Cursor, error) {
+	rng, err := events.ConvertRange(f.repo.NewBestChain(), filter.Range)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ff := &logdb.FailureFilter{
+		Range:   rng,
+		Options: filter.Options,
+		Order:   filter.Order,
+	}
+	for _, c := range filter.CriteriaSet {
+		criteria, err := convertCriteria(c)
+		if err != nil {
+			return nil, nil, utils.BadRequest(errors.WithMessage(err, "criteriaSet"))
+		}
+		ff.CriteriaSet = append(ff.CriteriaSet, criteria)
+	}
+	if status := f.db.PruneStatus(); status.FailuresPruned(ff) {
+		if ff.Range, err = events.ClampPruned(header, status, ff.Range); err != nil {
+			return nil, nil, err
+		}
+	}
+	failures, err := f.db.FilterFailures(ctx, ff)
+	if err != nil {
+		return nil, nil, err
+	}
+	results := make([]*FilteredFailure, len(failures))
+	for i, failure := range failures {
+		results[i] = convertFailure(failure)
+	}
+	var cursor *logdb.Cursor
+	if len(failures) > 0 {
+		last := failures[len(failures)-1]
+		cursor = logdb.NewCursor(last.BlockNumber, last.Index)
+	}
+	return results, cursor, nil
+}
+
+func (f *Failures) handleFilterFailures(w http.ResponseWriter, req *http.Request) error {
+	var filter FailureFilter
+	if err := utils.ParseJSON(req.Body, &filter); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	return events.WriteFiltered(w, filter.Options, f.limit, func(options *logdb.Options) ([]*FilteredFailure, *logdb.Cursor, error) {
+		filter.Options = options
+		return f.filter(req.Context(), w.Header(), &filter)
+	})
+}
+
+func (f *Failures) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("").
+		Methods(http.MethodPost).
+		Name("logs_filter_failure").
+		HandlerFunc(utils.WrapHandlerFunc(f.handleFilterFailures))
+}
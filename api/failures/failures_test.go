@@ -0,0 +1,177 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package failures_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/api/events"
+	"github.com/vechain/thor/v2/api/failures"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/test/logsapi"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+const defaultLogLimit uint64 = 1000
+
+var (
+	target   = thor.BytesToAddress([]byte("target"))
+	selector = "0xa9059cbb"
+)
+
+func TestFailures(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ts := newServer(t, db)
+
+	res, err := http.Post(ts.URL+"/failures", "application/x-www-form-urlencoded", bytes.NewReader([]byte{0x00, 0x01, 0x02}))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	// the first two senders fail alternately in blocks #1..#4
+	senders := insertBlocks(t, db, 4)
+
+	var all []*failures.FilteredFailure
+	body, statusCode := logsapi.Post(t, ts.URL+"/failures", failures.FailureFilter{})
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(body, &all); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, all, 8)
+	for i, f := range all {
+		assert.Equal(t, uint32(i/2+1), f.Meta.BlockNumber)
+		assert.Equal(t, uint32(i%2), f.Meta.ClauseIndex)
+		assert.Equal(t, senders[i/2%2], f.Meta.TxOrigin)
+		if i%2 == 0 {
+			assert.Equal(t, &target, f.To)
+			assert.Equal(t, &selector, f.Selector)
+		} else {
+			assert.Equal(t, &senders[0], f.To)
+			assert.Nil(t, f.Selector)
+		}
+	}
+
+	otherSelector := "0x01020304"
+	tests := []struct {
+		name   string
+		filter failures.FailureFilter
+		want   []*failures.FilteredFailure
+	}{
+		{"by to and selector", failures.FailureFilter{CriteriaSet: []*failures.FailureCriteria{{To: &target, Selector: &selector}}}, []*failures.FilteredFailure{all[0], all[2], all[4], all[6]}},
+		{"by origin", failures.FailureFilter{CriteriaSet: []*failures.FailureCriteria{{TxOrigin: &senders[1]}}, Range: &events.Range{Unit: events.BlockRangeType, From: 3, To: 4}}, all[6:]},
+		{"by other selector", failures.FailureFilter{CriteriaSet: []*failures.FailureCriteria{{Selector: &otherSelector}}}, []*failures.FilteredFailure{}},
+		{"desc", failures.FailureFilter{Order: logdb.DESC, Options: &logdb.Options{Limit: 1}}, all[7:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, statusCode := logsapi.Post(t, ts.URL+"/failures", tt.filter)
+			assert.Equal(t, http.StatusOK, statusCode)
+			var got []*failures.FilteredFailure
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, invalid := range []string{"0xa9059c", "a9059cbb", "0xzz059cbb"} {
+		res, statusCode := logsapi.Post(t, ts.URL+"/failures", failures.FailureFilter{CriteriaSet: []*failures.FailureCriteria{{Selector: &invalid}}})
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Contains(t, string(res), "4 bytes hex expected")
+	}
+}
+
+// TestPruned checks that failures sent to kept addresses are not clamped to the blocks not pruned.
+func TestPruned(t *testing.T) {
+	// a file db, not to prune the shared in-memory one
+	db, err := logdb.New(filepath.Join(t.TempDir(), "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ts := newServer(t, db)
+	insertBlocks(t, db, 4)
+
+	if err := db.Prune(context.Background(), 3, []thor.Address{target}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*failures.FilteredFailure
+	body, statusCode := logsapi.Post(t, ts.URL+"/failures", failures.FailureFilter{})
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, got, 4)
+
+	body, statusCode = logsapi.Post(t, ts.URL+"/failures", failures.FailureFilter{CriteriaSet: []*failures.FailureCriteria{{To: &target}}})
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, got, 4)
+	for _, f := range got {
+		assert.Equal(t, &target, f.To)
+	}
+}
+
+// insertBlocks writes n blocks, each with a reverted tx calling the target with a selector by the first clause,
+// and returns the senders.
+func insertBlocks(t *testing.T, db logdb.LogStore, n int) []thor.Address {
+	var senders []thor.Address
+	for _, acc := range genesis.DevAccounts()[:2] {
+		senders = append(senders, acc.Address)
+	}
+
+	db.RecordFailures(true)
+	b := new(block.Builder).Build()
+	w := db.NewWriter()
+	for i := 0; i < n; i++ {
+		trx := new(tx.Builder).
+			Clause(tx.NewClause(&target).WithData([]byte{0xa9, 0x05, 0x9c, 0xbb, 0x01})).
+			Clause(tx.NewClause(&senders[0])).
+			Nonce(uint64(i)).
+			Build()
+		sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[i%2].PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		builder := new(block.Builder).Transaction(trx.WithSignature(sig))
+		if i > 0 {
+			builder.ParentID(b.Header().ID())
+		}
+		b = builder.Build()
+		if err := w.Write(b, tx.Receipts{{Reverted: true}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	return senders
+}
+
+func newServer(t *testing.T, db logdb.LogStore) *httptest.Server {
+	return logsapi.NewServer(t, func(repo *chain.Repository, router *mux.Router) {
+		failures.New(repo, db, defaultLogLimit).Mount(router, "/failures")
+	})
+}
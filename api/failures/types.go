@@ -0,0 +1,78 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package failures
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/vechain/thor/v2/api/events"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/thor"
+)
+
+type LogMeta struct {
+	BlockID        thor.Bytes32 `json:"blockID"`
+	BlockNumber    uint32       `json:"blockNumber"`
+	BlockTimestamp uint64       `json:"blockTimestamp"`
+	TxID           thor.Bytes32 `json:"txID"`
+	TxOrigin       thor.Address `json:"txOrigin"`
+	ClauseIndex    uint32       `json:"clauseIndex"`
+}
+
+// FilteredFailure is a clause of a reverted tx.
+type FilteredFailure struct {
+	To       *thor.Address `json:"to"`
+	Selector *string       `json:"selector"`
+	Meta     LogMeta       `json:"meta"`
+}
+
+func convertFailure(f *logdb.Failure) *FilteredFailure {
+	v := &FilteredFailure{
+		To: f.To,
+		Meta: LogMeta{
+			BlockID:        f.BlockID,
+			BlockNumber:    f.BlockNumber,
+			BlockTimestamp: f.BlockTime,
+			TxID:           f.TxID,
+			TxOrigin:       f.TxOrigin,
+			ClauseIndex:    f.ClauseIndex,
+		},
+	}
+	if f.Selector != nil {
+		selector := hexutil.Encode(f.Selector[:])
+		v.Selector = &selector
+	}
+	return v
+}
+
+type FailureCriteria struct {
+	TxOrigin *thor.Address `json:"txOrigin"`
+	To       *thor.Address `json:"to"`
+	Selector *string       `json:"selector"`
+}
+
+func convertCriteria(c *FailureCriteria) (*logdb.FailureCriteria, error) {
+	criteria := &logdb.FailureCriteria{
+		TxOrigin: c.TxOrigin,
+		To:       c.To,
+	}
+	if c.Selector != nil {
+		data, err := hexutil.Decode(*c.Selector)
+		if err != nil || len(data) != 4 {
+			return nil, fmt.Errorf("invalid selector %q, 4 bytes hex expected", *c.Selector)
+		}
+		criteria.Selector = (*[4]byte)(data)
+	}
+	return criteria, nil
+}
+
+type FailureFilter struct {
+	CriteriaSet []*FailureCriteria
+	Range       *events.Range
+	Options     *logdb.Options
+	Order       logdb.Order //default asc
+}
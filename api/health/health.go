@@ -0,0 +1,119 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/vechain/thor/v2/api/node"
+	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/logdb"
+)
+
+type Health struct {
+	repo  *chain.Repository
+	nw    node.Network
+	logDB logdb.LogStore
+	opts  Options
+}
+
+// New creates the health api. logDB is optional, the logdb condition is skipped if it's nil.
+func New(repo *chain.Repository, nw node.Network, logDB logdb.LogStore, opts Options) *Health {
+	return &Health{
+		repo,
+		nw,
+		logDB,
+		opts,
+	}
+}
+
+// Readiness checks whether the node is ready to serve reads.
+func (h *Health) Readiness() *Readiness {
+	var (
+		r    = &Readiness{Ready: true}
+		best = h.repo.BestBlockSummary().Header
+	)
+	check := func(name string, reason string) {
+		r.Checks = append(r.Checks, &Check{Name: name, Passed: reason == "", Reason: reason})
+		if reason != "" {
+			r.Ready = false
+		}
+	}
+
+	select {
+	case <-h.nw.Synced():
+		check("synced", "")
+	default:
+		check("synced", "initial synchronization in progress")
+	}
+
+	if h.opts.MaxHeadAge > 0 {
+		age := time.Since(time.Unix(int64(best.Timestamp()), 0)).Truncate(time.Second)
+		reason := ""
+		if age > h.opts.MaxHeadAge {
+			reason = fmt.Sprintf("best block is %v old, exceeds %v", age, h.opts.MaxHeadAge)
+		}
+		check("headAge", reason)
+	}
+
+	if h.opts.MinPeers > 0 {
+		peers := len(h.nw.PeersStats())
+		reason := ""
+		if peers < h.opts.MinPeers {
+			reason = fmt.Sprintf("%d peers connected, requires %d", peers, h.opts.MinPeers)
+		}
+		check("peers", reason)
+	}
+
+	if h.logDB != nil && h.opts.MaxLogDBLag > 0 {
+		reason := ""
+		written := h.logDB.WrittenBlockID()
+		var err error
+		if written.IsZero() {
+			// nothing written since opened, fall back to the newest block with logs
+			written, err = h.logDB.NewestBlockID()
+		}
+		if err != nil {
+			reason = fmt.Sprintf("failed to get the newest logdb block: %v", err)
+		} else if lag := best.Number() - min(block.Number(written), best.Number()); lag > h.opts.MaxLogDBLag {
+			reason = fmt.Sprintf("logdb lags %d blocks behind, exceeds %d", lag, h.opts.MaxLogDBLag)
+		}
+		check("logDBLag", reason)
+	}
+	return r
+}
+
+func (h *Health) handleLive(w http.ResponseWriter, _ *http.Request) error {
+	return utils.WriteJSON(w, &Liveness{Alive: true})
+}
+
+func (h *Health) handleReady(w http.ResponseWriter, _ *http.Request) error {
+	r := h.Readiness()
+	w.Header().Set("Content-Type", utils.JSONContentType)
+	if !r.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	return json.NewEncoder(w).Encode(r)
+}
+
+func (h *Health) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("/live").
+		Methods(http.MethodGet).
+		Name("health_live").
+		HandlerFunc(utils.WrapHandlerFunc(h.handleLive))
+	sub.Path("/ready").
+		Methods(http.MethodGet).
+		Name("health_ready").
+		HandlerFunc(utils.WrapHandlerFunc(h.handleReady))
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package health_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/api/health"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/comm"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/tx"
+)
+
+type mockNetwork struct {
+	synced chan struct{}
+	peers  []*comm.PeerStats
+}
+
+func (n *mockNetwork) PeersStats() []*comm.PeerStats { return n.peers }
+
+func (n *mockNetwork) Synced() <-chan struct{} { return n.synced }
+
+func initChain(t *testing.T) *chain.Repository {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	b, _, _, err := genesis.NewDevnet().Build(stater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := chain.NewRepository(db, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestReadiness(t *testing.T) {
+	repo := initChain(t)
+	logDB, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logDB.Close()
+
+	nw := &mockNetwork{synced: make(chan struct{})}
+	h := health.New(repo, nw, logDB, health.Options{MinPeers: 1, MaxLogDBLag: 1})
+
+	r := h.Readiness()
+	assert.False(t, r.Ready)
+	assert.Equal(t, []*health.Check{
+		{Name: "synced", Reason: "initial synchronization in progress"},
+		{Name: "peers", Reason: "0 peers connected, requires 1"},
+		{Name: "logDBLag", Passed: true},
+	}, r.Checks)
+
+	close(nw.synced)
+	nw.peers = []*comm.PeerStats{{}}
+	r = h.Readiness()
+	assert.True(t, r.Ready)
+
+	// logdb stopped writing, while the chain grows
+	w := logDB.NewWriter()
+	if err := w.Write(repo.GenesisBlock(), tx.Receipts{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	parentID := repo.GenesisBlock().Header().ID()
+	for i := 0; i < 2; i++ {
+		blk := new(block.Builder).ParentID(parentID).Build()
+		if err := repo.AddBlock(blk, nil, 0); err != nil {
+			t.Fatal(err)
+		}
+		parentID = blk.Header().ID()
+	}
+	if err := repo.SetBestBlockID(parentID); err != nil {
+		t.Fatal(err)
+	}
+
+	r = h.Readiness()
+	assert.False(t, r.Ready)
+	assert.Equal(t, &health.Check{Name: "logDBLag", Reason: "logdb lags 2 blocks behind, exceeds 1"}, r.Checks[2])
+}
+
+func TestReadinessNothingWritten(t *testing.T) {
+	repo := initChain(t)
+	logDB, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logDB.Close()
+
+	parentID := repo.GenesisBlock().Header().ID()
+	for i := 0; i < 2; i++ {
+		blk := new(block.Builder).ParentID(parentID).Build()
+		if err := repo.AddBlock(blk, nil, 0); err != nil {
+			t.Fatal(err)
+		}
+		parentID = blk.Header().ID()
+	}
+	if err := repo.SetBestBlockID(parentID); err != nil {
+		t.Fatal(err)
+	}
+
+	synced := make(chan struct{})
+	close(synced)
+	h := health.New(repo, &mockNetwork{synced: synced}, logDB, health.Options{MaxLogDBLag: 1})
+
+	// the newest block in the logdb is compared with the best block
+	r := h.Readiness()
+	assert.False(t, r.Ready)
+	assert.Equal(t, &health.Check{Name: "logDBLag", Reason: "logdb lags 2 blocks behind, exceeds 1"}, r.Checks[1])
+}
+
+func TestHealth(t *testing.T) {
+	repo := initChain(t)
+	synced := make(chan struct{})
+	close(synced)
+
+	router := mux.NewRouter()
+	// genesis of devnet is old enough to fail the head age condition
+	health.New(repo, &mockNetwork{synced: synced}, nil, health.Options{MaxHeadAge: time.Minute}).
+		Mount(router, "/health")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	res, body := httpGet(t, ts.URL+"/health/live")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "{\"alive\":true}\n", string(body))
+
+	res, body = httpGet(t, ts.URL+"/health/ready")
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	var r health.Readiness
+	if err := json.Unmarshal(body, &r); err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, r.Ready)
+	assert.Len(t, r.Checks, 2)
+	assert.True(t, r.Checks[0].Passed)
+	assert.Equal(t, "headAge", r.Checks[1].Name)
+	assert.Contains(t, r.Checks[1].Reason, "exceeds 1m0s")
+}
+
+func httpGet(t *testing.T, url string) (*http.Response, []byte) {
+	res, err := http.Get(url) // nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res, body
+}
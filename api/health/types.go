@@ -0,0 +1,35 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package health
+
+import (
+	"time"
+)
+
+// Options readiness conditions, a zero value disables the condition.
+type Options struct {
+	MaxHeadAge  time.Duration // max age of the best block
+	MinPeers    int           // min number of connected peers
+	MaxLogDBLag uint32        // max number of blocks the logdb lags behind the best block
+}
+
+// Liveness response of /health/live.
+type Liveness struct {
+	Alive bool `json:"alive"`
+}
+
+// Readiness response of /health/ready.
+type Readiness struct {
+	Ready  bool     `json:"ready"`
+	Checks []*Check `json:"checks"`
+}
+
+// Check result of a readiness condition.
+type Check struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Reason string `json:"reason,omitempty"` // why the check failed
+}
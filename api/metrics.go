@@ -22,6 +22,7 @@ var (
 	metricHTTPReqCounter       = metrics.LazyLoadCounterVec("api_request_count", []string{"name", "code", "method"})
 	metricHTTPReqDuration      = metrics.LazyLoadHistogramVec("api_duration_ms", []string{"name", "code", "method"}, metrics.BucketHTTPReqs)
 	metricActiveWebsocketCount = metrics.LazyLoadGaugeVec("api_active_websocket_count", []string{"subject"})
+	metricAPIKeyReqCounter     = metrics.LazyLoadCounterVec("api_key_request_count", []string{"key", "name", "code"})
 )
 
 // metricsResponseWriter is a wrapper around http.ResponseWriter that captures the status code.
@@ -54,6 +55,13 @@ func (m *metricsResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return h.Hijack()
 }
 
+// Flush complies the writer with SSE subscriptions interface.
+func (m *metricsResponseWriter) Flush() {
+	if f, ok := m.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 // metricsMiddleware is a middleware that records metrics for each request.
 func metricsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -92,5 +100,10 @@ func metricsMiddleware(next http.Handler) http.Handler {
 			metricHTTPReqCounter().AddWithLabel(1, map[string]string{"name": name, "code": strconv.Itoa(mrw.statusCode), "method": r.Method})
 			metricHTTPReqDuration().ObserveWithLabels(time.Since(now).Milliseconds(), map[string]string{"name": name, "code": strconv.Itoa(mrw.statusCode), "method": r.Method})
 		}
+
+		// the usage of API keys, the key name is set by the rate limiter
+		if key := apiKeyName(r); key != "" && enabled {
+			metricAPIKeyReqCounter().AddWithLabel(1, map[string]string{"key": key, "name": name, "code": strconv.Itoa(mrw.statusCode)})
+		}
 	})
 }
@@ -13,6 +13,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -58,7 +60,7 @@ func TestMetricsMiddleware(t *testing.T) {
 	assert.NotNil(t, err)
 
 	router := mux.NewRouter()
-	acc := accounts.New(repo, stater, math.MaxUint64, thor.NoFork, solo.NewBFTEngine(repo))
+	acc := accounts.New(repo, stater, math.MaxUint64, thor.NoFork, solo.NewBFTEngine(repo), nil)
 	acc.Mount(router, "/accounts")
 	router.PathPrefix("/metrics").Handler(metrics.HTTPHandler())
 	router.Use(metricsMiddleware)
@@ -120,7 +122,7 @@ func TestWebsocketMetrics(t *testing.T) {
 	repo, _ := chain.NewRepository(db, b)
 
 	router := mux.NewRouter()
-	sub := subscriptions.New(repo, []string{"*"}, 10, txpool.New(repo, stater, txpool.Options{}))
+	sub := subscriptions.New(repo, []string{"*"}, 10, txpool.New(repo, stater, txpool.Options{}), solo.NewBFTEngine(repo))
 	sub.Mount(router, "/subscriptions")
 	router.PathPrefix("/metrics").Handler(metrics.HTTPHandler())
 	router.Use(metricsMiddleware)
@@ -180,6 +182,46 @@ func TestWebsocketMetrics(t *testing.T) {
 	assert.Equal(t, "block", labels[0].GetValue())
 }
 
+func TestAPIKeyMetrics(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(keysFile, []byte(`[{"name":"explorer","key":"k"}]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	limiter, err := NewRateLimiter(RateLimitOptions{KeysFile: keysFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer limiter.Close()
+
+	router := mux.NewRouter()
+	router.Path("/ping").Name("ping").HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {})
+	router.PathPrefix("/metrics").Handler(metrics.HTTPHandler())
+	router.Use(limiter.Middleware)
+	router.Use(metricsMiddleware)
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	httpGet(t, ts.URL+"/ping?api_key=k")
+	httpGet(t, ts.URL+"/ping?api_key=k")
+	httpGet(t, ts.URL+"/ping")
+
+	body, _ := httpGet(t, ts.URL+"/metrics")
+	parser := expfmt.TextParser{}
+	metrics, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	assert.Nil(t, err)
+
+	m := metrics["thor_metrics_api_key_request_count"].GetMetric()
+	assert.Equal(t, 1, len(m), "should be 1 metric entries")
+	assert.Equal(t, float64(2), m[0].GetCounter().GetValue())
+
+	labels := m[0].GetLabel()
+	assert.Equal(t, 3, len(labels))
+	assert.Equal(t, "200", labels[0].GetValue())
+	assert.Equal(t, "key", labels[1].GetName())
+	assert.Equal(t, "explorer", labels[1].GetValue())
+	assert.Equal(t, "ping", labels[2].GetValue())
+}
+
 func httpGet(t *testing.T, url string) ([]byte, int) {
 	res, err := http.Get(url) // nolint:gosec
 	if err != nil {
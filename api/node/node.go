@@ -7,18 +7,51 @@ package node
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/bft"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/txpool"
 )
 
 type Node struct {
-	nw Network
+	nw         Network
+	repo       *chain.Repository
+	bft        bft.Committer
+	logDB      logdb.LogStore
+	txPool     *txpool.TxPool
+	optimizer  Optimizer
+	forkConfig thor.ForkConfig
+	version    string
+	features   []string
 }
 
-func New(nw Network) *Node {
+// New creates the node api. logDB and optimizer are optional.
+func New(
+	nw Network,
+	repo *chain.Repository,
+	bft bft.Committer,
+	logDB logdb.LogStore,
+	txPool *txpool.TxPool,
+	optimizer Optimizer,
+	forkConfig thor.ForkConfig,
+	version string,
+	features []string,
+) *Node {
 	return &Node{
 		nw,
+		repo,
+		bft,
+		logDB,
+		txPool,
+		optimizer,
+		forkConfig,
+		version,
+		features,
 	}
 }
 
@@ -30,6 +63,94 @@ func (n *Node) handleNetwork(w http.ResponseWriter, _ *http.Request) error {
 	return utils.WriteJSON(w, n.PeersStats())
 }
 
+func (n *Node) blockInfo(id thor.Bytes32) (*BlockInfo, error) {
+	summary, err := n.repo.GetBlockSummary(id)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockInfo{
+		ID:        id,
+		Number:    summary.Header.Number(),
+		Timestamp: summary.Header.Timestamp(),
+	}, nil
+}
+
+func (n *Node) Info() (*Info, error) {
+	genesis := n.repo.GenesisBlock().Header()
+	best := n.repo.BestBlockSummary().Header
+
+	info := &Info{
+		Version:    n.version,
+		GenesisID:  genesis.ID(),
+		ForkConfig: n.forkConfig,
+		Best: &BlockInfo{
+			ID:        best.ID(),
+			Number:    best.Number(),
+			Timestamp: best.Timestamp(),
+		},
+		Sync: &SyncInfo{
+			Progress: estimateSyncProgress(genesis.Timestamp(), best.Timestamp(), uint64(time.Now().Unix())),
+			Peers:    len(n.nw.PeersStats()),
+		},
+		Features: n.features,
+	}
+
+	select {
+	case <-n.nw.Synced():
+		info.Sync.Synced = true
+		info.Sync.Progress = 1
+	default:
+	}
+
+	justified, err := n.bft.Justified()
+	if err != nil {
+		return nil, err
+	}
+	if info.Justified, err = n.blockInfo(justified); err != nil {
+		return nil, err
+	}
+	if info.Finalized, err = n.blockInfo(n.bft.Finalized()); err != nil {
+		return nil, err
+	}
+
+	if n.logDB != nil {
+		newest, err := n.logDB.NewestBlockID()
+		if err != nil {
+			return nil, err
+		}
+		// logdb is empty
+		if newest.IsZero() {
+			newest = genesis.ID()
+		}
+		if info.LogDB, err = n.blockInfo(newest); err != nil {
+			return nil, err
+		}
+	}
+
+	if n.optimizer != nil {
+		base, pruneBase := n.optimizer.Progress()
+		info.Optimizer = &OptimizerInfo{
+			OptimizedTo: base,
+			PrunedTo:    pruneBase,
+		}
+	}
+
+	status := n.txPool.Status()
+	info.TxPool = &TxPoolInfo{
+		Total:      status.Total,
+		Executable: status.Executable,
+	}
+	return info, nil
+}
+
+func (n *Node) handleInfo(w http.ResponseWriter, _ *http.Request) error {
+	info, err := n.Info()
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, info)
+}
+
 func (n *Node) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 
@@ -37,4 +158,8 @@ func (n *Node) Mount(root *mux.Router, pathPrefix string) {
 		Methods(http.MethodGet).
 		Name("node_get_peers").
 		HandlerFunc(utils.WrapHandlerFunc(n.handleNetwork))
+	sub.Path("/info").
+		Methods(http.MethodGet).
+		Name("node_get_info").
+		HandlerFunc(utils.WrapHandlerFunc(n.handleInfo))
 }
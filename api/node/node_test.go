@@ -16,14 +16,20 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/v2/api/node"
 	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/cmd/thor/solo"
 	"github.com/vechain/thor/v2/comm"
 	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/logdb"
 	"github.com/vechain/thor/v2/muxdb"
 	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/txpool"
 )
 
-var ts *httptest.Server
+var (
+	ts   *httptest.Server
+	repo *chain.Repository
+)
 
 func TestNode(t *testing.T) {
 	initCommServer(t)
@@ -35,6 +41,35 @@ func TestNode(t *testing.T) {
 	assert.Equal(t, 0, len(peersStats), "count should be zero")
 }
 
+func TestNodeInfo(t *testing.T) {
+	initCommServer(t)
+	res := httpGet(t, ts.URL+"/node/info")
+	var info node.Info
+	if err := json.Unmarshal(res, &info); err != nil {
+		t.Fatal(err)
+	}
+
+	genesisID := repo.GenesisBlock().Header().ID()
+	assert.Equal(t, "1.0.0-test", info.Version)
+	assert.Equal(t, genesisID, info.GenesisID)
+	assert.Equal(t, thor.NoFork, info.ForkConfig)
+	assert.Equal(t, genesisID, info.Best.ID)
+	assert.Equal(t, genesisID, info.Justified.ID)
+	assert.Equal(t, genesisID, info.Finalized.ID)
+	assert.Equal(t, genesisID, info.LogDB.ID)
+	assert.False(t, info.Sync.Synced)
+	assert.Equal(t, 0, info.Sync.Peers)
+	assert.Equal(t, &node.OptimizerInfo{OptimizedTo: 2000, PrunedTo: 1000}, info.Optimizer)
+	assert.Equal(t, &node.TxPoolInfo{}, info.TxPool)
+	assert.Equal(t, []string{"logs"}, info.Features)
+}
+
+type mockOptimizer struct{}
+
+func (o *mockOptimizer) Progress() (uint32, uint32) {
+	return 2000, 1000
+}
+
 func initCommServer(t *testing.T) {
 	db := muxdb.NewMem()
 	stater := state.NewStater(db)
@@ -44,14 +79,20 @@ func initCommServer(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	repo, _ := chain.NewRepository(db, b)
-	comm := comm.New(repo, txpool.New(repo, stater, txpool.Options{
+	repo, _ = chain.NewRepository(db, b)
+	txPool := txpool.New(repo, stater, txpool.Options{
 		Limit:           10000,
 		LimitPerAccount: 16,
 		MaxLifetime:     10 * time.Minute,
-	}))
+	})
+	comm := comm.New(repo, txPool)
+	logDB, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
 	router := mux.NewRouter()
-	node.New(comm).Mount(router, "/node")
+	node.New(comm, repo, solo.NewBFTEngine(repo), logDB, txPool, &mockOptimizer{}, thor.NoFork, "1.0.0-test", []string{"logs"}).
+		Mount(router, "/node")
 	ts = httptest.NewServer(router)
 }
 
@@ -12,6 +12,60 @@ import (
 
 type Network interface {
 	PeersStats() []*comm.PeerStats
+	Synced() <-chan struct{}
+}
+
+// Optimizer reports the progress of the trie optimizer.
+type Optimizer interface {
+	Progress() (base, pruneBase uint32)
+}
+
+// Info rich status of the node.
+type Info struct {
+	Version    string          `json:"version"`
+	GenesisID  thor.Bytes32    `json:"genesisID"`
+	ForkConfig thor.ForkConfig `json:"forkConfig"`
+	Best       *BlockInfo      `json:"best"`
+	Justified  *BlockInfo      `json:"justified"`
+	Finalized  *BlockInfo      `json:"finalized"`
+	Sync       *SyncInfo       `json:"sync"`
+	LogDB      *BlockInfo      `json:"logDB"`     // the newest block written into logdb, null if logs are skipped
+	Optimizer  *OptimizerInfo  `json:"optimizer"` // null if the optimizer is not running
+	TxPool     *TxPoolInfo     `json:"txPool"`
+	Features   []string        `json:"features"` // enabled API features
+}
+
+type BlockInfo struct {
+	ID        thor.Bytes32 `json:"id"`
+	Number    uint32       `json:"number"`
+	Timestamp uint64       `json:"timestamp"`
+}
+
+type SyncInfo struct {
+	Synced   bool    `json:"synced"`
+	Progress float64 `json:"progress"` // estimated by the timestamp of the best block
+	Peers    int     `json:"peers"`
+}
+
+type OptimizerInfo struct {
+	OptimizedTo uint32 `json:"optimizedTo"`
+	PrunedTo    uint32 `json:"prunedTo"`
+}
+
+type TxPoolInfo struct {
+	Total      int `json:"total"`
+	Executable int `json:"executable"`
+}
+
+// estimateSyncProgress estimates the sync progress by the elapsed time from genesis to the best block.
+func estimateSyncProgress(genesisTime, bestTime, now uint64) float64 {
+	if bestTime >= now || genesisTime >= now {
+		return 1
+	}
+	if bestTime <= genesisTime {
+		return 0
+	}
+	return float64(bestTime-genesisTime) / float64(now-genesisTime)
 }
 
 type PeerStats struct {
@@ -0,0 +1,99 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package pool
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+type Pool struct {
+	pool *txpool.TxPool
+}
+
+func New(pool *txpool.TxPool) *Pool {
+	return &Pool{
+		pool,
+	}
+}
+
+func (p *Pool) handleGetStatus(w http.ResponseWriter, _ *http.Request) error {
+	return utils.WriteJSON(w, convertStatus(p.pool.Status()))
+}
+
+func (p *Pool) handleGetTransactions(w http.ResponseWriter, req *http.Request) error {
+	var origin *thor.Address
+	if s := req.URL.Query().Get("origin"); s != "" {
+		addr, err := thor.ParseAddress(s)
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "origin"))
+		}
+		origin = &addr
+	}
+
+	executable := req.URL.Query().Get("executable")
+	if executable != "" && executable != "false" && executable != "true" {
+		return utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "executable"))
+	}
+
+	infos := p.pool.DumpInfo()
+	txs := make([]*Transaction, 0, len(infos))
+	for _, info := range infos {
+		if origin != nil && info.Origin != *origin {
+			continue
+		}
+		if executable != "" && info.Executable != (executable == "true") {
+			continue
+		}
+		txs = append(txs, convertTransaction(info))
+	}
+
+	// the pool is unordered, sort by time added to get a stable result
+	sort.Slice(txs, func(i, j int) bool {
+		if txs[i].TimeAdded != txs[j].TimeAdded {
+			return txs[i].TimeAdded < txs[j].TimeAdded
+		}
+		return bytes.Compare(txs[i].ID[:], txs[j].ID[:]) < 0
+	})
+	return utils.WriteJSON(w, txs)
+}
+
+func (p *Pool) handleGetTransactionByID(w http.ResponseWriter, req *http.Request) error {
+	txID, err := thor.ParseBytes32(mux.Vars(req)["id"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "id"))
+	}
+
+	info := p.pool.GetInfo(txID)
+	if info == nil {
+		return utils.WriteJSON(w, nil)
+	}
+	return utils.WriteJSON(w, convertTransaction(info))
+}
+
+func (p *Pool) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("/status").
+		Methods(http.MethodGet).
+		Name("txpool_get_status").
+		HandlerFunc(utils.WrapHandlerFunc(p.handleGetStatus))
+	sub.Path("/transactions").
+		Methods(http.MethodGet).
+		Name("txpool_get_transactions").
+		HandlerFunc(utils.WrapHandlerFunc(p.handleGetTransactions))
+	sub.Path("/transactions/{id}").
+		Methods(http.MethodGet).
+		Name("txpool_get_transaction").
+		HandlerFunc(utils.WrapHandlerFunc(p.handleGetTransactionByID))
+}
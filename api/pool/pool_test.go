@@ -0,0 +1,202 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package pool_test
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/api/pool"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+var ts *httptest.Server
+var executableTx, futureTx *tx.Transaction
+
+func TestPool(t *testing.T) {
+	initPoolServer(t)
+	defer ts.Close()
+
+	for name, tt := range map[string]func(*testing.T){
+		"getStatus":                     getStatus,
+		"getTransactions":               getTransactions,
+		"getTransactionsByOrigin":       getTransactionsByOrigin,
+		"getTransactionsByExecutable":   getTransactionsByExecutable,
+		"getTransactionsWithBadQueries": getTransactionsWithBadQueries,
+		"getTransaction":                getTransaction,
+		"getTransactionNotFound":        getTransactionNotFound,
+		"getTransactionWithBadID":       getTransactionWithBadID,
+	} {
+		t.Run(name, tt)
+	}
+}
+
+func getStatus(t *testing.T) {
+	res := httpGetAndCheckResponseStatus(t, ts.URL+"/txpool/status", http.StatusOK)
+	var status pool.Status
+	if err := json.Unmarshal(res, &status); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, pool.Status{
+		Total:           2,
+		Executable:      1,
+		NonExecutable:   1,
+		Local:           1,
+		Origins:         2,
+		Limit:           10000,
+		LimitPerAccount: 16,
+	}, status)
+}
+
+func getTransactions(t *testing.T) {
+	txs := fetchTransactions(t, "")
+	assert.Equal(t, 2, len(txs))
+}
+
+func getTransactionsByOrigin(t *testing.T) {
+	txs := fetchTransactions(t, "?origin="+genesis.DevAccounts()[1].Address.String())
+	assert.Equal(t, 1, len(txs))
+	assert.Equal(t, futureTx.ID(), txs[0].ID)
+
+	txs = fetchTransactions(t, "?origin="+thor.Address{}.String())
+	assert.Equal(t, 0, len(txs))
+}
+
+func getTransactionsByExecutable(t *testing.T) {
+	txs := fetchTransactions(t, "?executable=true")
+	assert.Equal(t, 1, len(txs))
+	assert.Equal(t, executableTx.ID(), txs[0].ID)
+	assert.True(t, txs[0].Local)
+	assert.Equal(t, "", txs[0].Reason)
+
+	txs = fetchTransactions(t, "?executable=false")
+	assert.Equal(t, 1, len(txs))
+	assert.Equal(t, futureTx.ID(), txs[0].ID)
+	assert.Equal(t, "block ref in future", txs[0].Reason)
+}
+
+func getTransactionsWithBadQueries(t *testing.T) {
+	res := httpGetAndCheckResponseStatus(t, ts.URL+"/txpool/transactions?origin=0x1", http.StatusBadRequest)
+	assert.Contains(t, string(res), "origin")
+
+	res = httpGetAndCheckResponseStatus(t, ts.URL+"/txpool/transactions?executable=yes", http.StatusBadRequest)
+	assert.Equal(t, "executable: should be boolean\n", string(res))
+}
+
+func getTransaction(t *testing.T) {
+	res := httpGetAndCheckResponseStatus(t, ts.URL+"/txpool/transactions/"+futureTx.ID().String(), http.StatusOK)
+	var trx pool.Transaction
+	if err := json.Unmarshal(res, &trx); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, futureTx.ID(), trx.ID)
+	assert.Equal(t, genesis.DevAccounts()[1].Address, trx.Origin)
+	assert.Equal(t, futureTx.Gas(), trx.Gas)
+	assert.False(t, trx.Executable)
+	assert.False(t, trx.Local)
+	assert.Nil(t, trx.OverallGasPrice)
+}
+
+func getTransactionNotFound(t *testing.T) {
+	res := httpGetAndCheckResponseStatus(t, ts.URL+"/txpool/transactions/"+thor.Bytes32{}.String(), http.StatusOK)
+	assert.Equal(t, "null\n", string(res))
+}
+
+func getTransactionWithBadID(t *testing.T) {
+	res := httpGetAndCheckResponseStatus(t, ts.URL+"/txpool/transactions/0x1", http.StatusBadRequest)
+	assert.Contains(t, string(res), "id")
+}
+
+func initPoolServer(t *testing.T) {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	now := uint64(time.Now().Unix())
+	gene := new(genesis.Builder).
+		GasLimit(thor.InitialGasLimit).
+		Timestamp(now).
+		State(func(state *state.State) error {
+			bal, _ := new(big.Int).SetString("1000000000000000000000000000", 10)
+			for _, acc := range genesis.DevAccounts() {
+				state.SetBalance(acc.Address, bal)
+				state.SetEnergy(acc.Address, bal, now)
+			}
+			return nil
+		})
+	b, _, _, err := gene.Build(stater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, _ := chain.NewRepository(db, b)
+
+	executableTx = newTx(t, repo.ChainTag(), tx.BlockRef{}, genesis.DevAccounts()[0])
+	futureTx = newTx(t, repo.ChainTag(), tx.NewBlockRef(10), genesis.DevAccounts()[1])
+
+	txPool := txpool.New(repo, stater, txpool.Options{Limit: 10000, LimitPerAccount: 16, MaxLifetime: 10 * time.Minute})
+	if err := txPool.AddLocal(executableTx); err != nil {
+		t.Fatal(err)
+	}
+	if err := txPool.Add(futureTx); err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	pool.New(txPool).Mount(router, "/txpool")
+	ts = httptest.NewServer(router)
+}
+
+func newTx(t *testing.T, chainTag byte, blockRef tx.BlockRef, from genesis.DevAccount) *tx.Transaction {
+	addr := thor.BytesToAddress([]byte("to"))
+	trx := new(tx.Builder).
+		ChainTag(chainTag).
+		Expiration(100).
+		Gas(21000).
+		BlockRef(blockRef).
+		Clause(tx.NewClause(&addr).WithValue(big.NewInt(10000))).
+		Build()
+
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), from.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trx.WithSignature(sig)
+}
+
+func fetchTransactions(t *testing.T, query string) []*pool.Transaction {
+	res := httpGetAndCheckResponseStatus(t, ts.URL+"/txpool/transactions"+query, http.StatusOK)
+	var txs []*pool.Transaction
+	if err := json.Unmarshal(res, &txs); err != nil {
+		t.Fatal(err)
+	}
+	return txs
+}
+
+func httpGetAndCheckResponseStatus(t *testing.T, url string, responseStatusCode int) []byte {
+	res, err := http.Get(url) // nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, responseStatusCode, res.StatusCode)
+	r, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package pool
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+// reasonNotEvaluated is reported for non-executable txs which have not been evaluated
+// against the head block yet, e.g. txs added while the chain is not synced.
+const reasonNotEvaluated = "not evaluated"
+
+// Status pool status
+type Status struct {
+	Total           int `json:"total"`
+	Executable      int `json:"executable"`
+	NonExecutable   int `json:"nonExecutable"`
+	Local           int `json:"local"`
+	Origins         int `json:"origins"`
+	Limit           int `json:"limit"`
+	LimitPerAccount int `json:"limitPerAccount"`
+	BlocklistSize   int `json:"blocklistSize"`
+}
+
+func convertStatus(s *txpool.Status) *Status {
+	return &Status{
+		Total:           s.Total,
+		Executable:      s.Executable,
+		NonExecutable:   s.Total - s.Executable,
+		Local:           s.Local,
+		Origins:         s.Origins,
+		Limit:           s.Limit,
+		LimitPerAccount: s.LimitPerAccount,
+		BlocklistSize:   s.BlocklistSize,
+	}
+}
+
+// Transaction pooled transaction with its pool metadata
+type Transaction struct {
+	ID              thor.Bytes32          `json:"id"`
+	Origin          thor.Address          `json:"origin"`
+	Delegator       *thor.Address         `json:"delegator"`
+	BlockRef        string                `json:"blockRef"`
+	Expiration      uint32                `json:"expiration"`
+	Clauses         int                   `json:"clauses"`
+	GasPriceCoef    uint8                 `json:"gasPriceCoef"`
+	Gas             uint64                `json:"gas"`
+	Nonce           math.HexOrDecimal64   `json:"nonce"`
+	DependsOn       *thor.Bytes32         `json:"dependsOn"`
+	Size            uint32                `json:"size"`
+	OverallGasPrice *math.HexOrDecimal256 `json:"overallGasPrice"`
+	TimeAdded       uint64                `json:"timeAdded"`
+	Local           bool                  `json:"local"`
+	Executable      bool                  `json:"executable"`
+	Reason          string                `json:"reason,omitempty"`
+}
+
+func convertTransaction(info *txpool.TxInfo) *Transaction {
+	br := info.Tx.BlockRef()
+	t := &Transaction{
+		ID:           info.Tx.ID(),
+		Origin:       info.Origin,
+		Delegator:    info.Delegator,
+		BlockRef:     hexutil.Encode(br[:]),
+		Expiration:   info.Tx.Expiration(),
+		Clauses:      len(info.Tx.Clauses()),
+		GasPriceCoef: info.Tx.GasPriceCoef(),
+		Gas:          info.Tx.Gas(),
+		Nonce:        math.HexOrDecimal64(info.Tx.Nonce()),
+		DependsOn:    info.Tx.DependsOn(),
+		Size:         uint32(info.Tx.Size()),
+		TimeAdded:    uint64(info.TimeAdded / 1e9),
+		Local:        info.LocalSubmitted,
+		Executable:   info.Executable,
+	}
+	if info.OverallGasPrice != nil {
+		t.OverallGasPrice = (*math.HexOrDecimal256)(new(big.Int).Set(info.OverallGasPrice))
+	}
+	if !info.Executable {
+		t.Reason = info.PendingReason
+		if t.Reason == "" {
+			t.Reason = reasonNotEvaluated
+		}
+	}
+	return t
+}
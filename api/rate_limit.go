@@ -0,0 +1,332 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/metrics"
+)
+
+const (
+	// APIKeyHeader is the request header carrying the API key.
+	APIKeyHeader = "x-api-key"
+	// APIKeyQuery is the query parameter carrying the API key, for clients unable to set headers, e.g. websocket in browsers.
+	APIKeyQuery = "api_key"
+
+	rateLimitReloadInterval = 5 * time.Second
+)
+
+var metricRateLimitedCounter = metrics.LazyLoadCounterVec("api_rate_limited_count", []string{"key", "expensive"})
+
+type apiKeyCtxKey struct{}
+
+// RateLimitOptions options for API key authentication and rate limiting.
+type RateLimitOptions struct {
+	KeysFile      string  // path of the JSON file of API keys, reloaded once modified
+	KeyRequired   bool    // whether requests without an API key are rejected
+	Rate          float64 // requests per second allowed for each client, 0 for unlimited
+	ExpensiveRate float64 // requests per second allowed for each client on expensive routes, 0 for unlimited
+}
+
+// APIKey an entry of the API keys file.
+// Rates of the key override the default ones if present.
+type APIKey struct {
+	Name          string   `json:"name"`
+	Key           string   `json:"key"`
+	Rate          *float64 `json:"rate"`
+	ExpensiveRate *float64 `json:"expensiveRate"`
+}
+
+// tokenBucket refills rate tokens per second, up to burst tokens.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, now time.Time) *tokenBucket {
+	burst := math.Max(1, math.Ceil(rate))
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: now}
+}
+
+// take takes a token from the bucket, returns the duration to wait for the next token if none left.
+func (b *tokenBucket) take(now time.Time) (time.Duration, bool) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second)), false
+}
+
+// full returns whether the bucket is refilled, which makes it safe to be dropped.
+func (b *tokenBucket) full(now time.Time) bool {
+	return b.tokens+now.Sub(b.last).Seconds()*b.rate >= b.burst
+}
+
+type bucketKey struct {
+	client    string
+	expensive bool
+}
+
+// RateLimiter authenticates requests by API keys, and limits the request rate of each key or IP
+// by token buckets.
+type RateLimiter struct {
+	opts    RateLimitOptions
+	keys    atomic.Pointer[map[string]*APIKey]
+	modTime time.Time
+
+	lock    sync.Mutex
+	buckets map[bucketKey]*tokenBucket
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewRateLimiter creates a rate limiter, and loads API keys from the keys file if given.
+func NewRateLimiter(opts RateLimitOptions) (*RateLimiter, error) {
+	if opts.Rate < 0 || opts.ExpensiveRate < 0 {
+		return nil, errors.New("rate: should not be negative")
+	}
+	if opts.KeyRequired && opts.KeysFile == "" {
+		return nil, errors.New("keys file: required if api key is required")
+	}
+
+	l := &RateLimiter{
+		opts:    opts,
+		buckets: make(map[bucketKey]*tokenBucket),
+		done:    make(chan struct{}),
+	}
+	l.keys.Store(&map[string]*APIKey{})
+	if opts.KeysFile != "" {
+		if _, err := l.reload(); err != nil {
+			return nil, err
+		}
+	}
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		l.loop()
+	}()
+	return l, nil
+}
+
+// Close stops the background routine.
+func (l *RateLimiter) Close() {
+	close(l.done)
+	l.wg.Wait()
+}
+
+func (l *RateLimiter) loop() {
+	ticker := time.NewTicker(rateLimitReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if l.opts.KeysFile != "" {
+				if reloaded, err := l.reload(); err != nil {
+					logger.Warn("failed to reload api keys, keep the previous ones", "err", err)
+				} else if reloaded {
+					logger.Info("api keys reloaded", "count", len(*l.keys.Load()))
+				}
+			}
+			l.cleanup(time.Now())
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// reload loads the keys file if it's modified since the last load.
+func (l *RateLimiter) reload() (bool, error) {
+	info, err := os.Stat(l.opts.KeysFile)
+	if err != nil {
+		return false, errors.Wrap(err, "stat keys file")
+	}
+	if info.ModTime().Equal(l.modTime) {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(l.opts.KeysFile)
+	if err != nil {
+		return false, errors.Wrap(err, "read keys file")
+	}
+	keys, err := parseAPIKeys(data)
+	if err != nil {
+		return false, errors.WithMessage(err, "parse keys file")
+	}
+	l.keys.Store(&keys)
+	l.modTime = info.ModTime()
+	return true, nil
+}
+
+func parseAPIKeys(data []byte) (map[string]*APIKey, error) {
+	var list []*APIKey
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*APIKey, len(list))
+	names := make(map[string]bool, len(list))
+	for i, k := range list {
+		if k.Name == "" || k.Key == "" {
+			return nil, fmt.Errorf("entry %d: name and key are required", i)
+		}
+		if (k.Rate != nil && *k.Rate < 0) || (k.ExpensiveRate != nil && *k.ExpensiveRate < 0) {
+			return nil, fmt.Errorf("entry %d: rate should not be negative", i)
+		}
+		if _, ok := keys[k.Key]; ok {
+			return nil, fmt.Errorf("entry %d: duplicated key", i)
+		}
+		if names[k.Name] {
+			return nil, fmt.Errorf("entry %d: duplicated name", i)
+		}
+		keys[k.Key] = k
+		names[k.Name] = true
+	}
+	return keys, nil
+}
+
+// cleanup drops refilled buckets to bound the memory used by idle clients.
+func (l *RateLimiter) cleanup(now time.Time) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for k, b := range l.buckets {
+		if b.full(now) {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// take takes a token from the bucket of the client, returns the duration to wait if rate limited.
+func (l *RateLimiter) take(client string, expensive bool, rate float64, now time.Time) (time.Duration, bool) {
+	if rate == 0 {
+		return 0, true
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	k := bucketKey{client, expensive}
+	b, ok := l.buckets[k]
+	// recreate the bucket if the rate of the key is changed
+	if !ok || b.rate != rate {
+		b = newTokenBucket(rate, now)
+		l.buckets[k] = b
+	}
+	return b.take(now)
+}
+
+// Middleware returns a handler applying API key authentication and rate limiting to requests.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// health probes are never limited
+		if strings.HasPrefix(r.URL.Path, "/health/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var (
+			rate, expensiveRate = l.opts.Rate, l.opts.ExpensiveRate
+			client              string
+			key                 *APIKey
+		)
+
+		if value := requestAPIKey(r); value != "" {
+			k, ok := (*l.keys.Load())[value]
+			if !ok {
+				http.Error(w, "invalid api key", http.StatusUnauthorized)
+				return
+			}
+			key = k
+			client = "key:" + k.Name
+			if k.Rate != nil {
+				rate = *k.Rate
+			}
+			if k.ExpensiveRate != nil {
+				expensiveRate = *k.ExpensiveRate
+			}
+		} else {
+			if l.opts.KeyRequired {
+				http.Error(w, "api key required", http.StatusUnauthorized)
+				return
+			}
+			client = "ip:" + remoteIP(r)
+		}
+
+		expensive := isExpensiveRequest(r)
+		if expensive {
+			rate = expensiveRate
+		}
+
+		if wait, ok := l.take(client, expensive, rate, time.Now()); !ok {
+			name := ""
+			if key != nil {
+				name = key.Name
+			}
+			metricRateLimitedCounter().AddWithLabel(1, map[string]string{"key": name, "expensive": strconv.FormatBool(expensive)})
+
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		if key != nil {
+			r = r.WithContext(context.WithValue(r.Context(), apiKeyCtxKey{}, key.Name))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestAPIKey returns the API key of the request, the header takes precedence over the query.
+func requestAPIKey(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	return r.URL.Query().Get(APIKeyQuery)
+}
+
+// apiKeyName returns the name of the API key authenticated the request.
+func apiKeyName(r *http.Request) string {
+	name, _ := r.Context().Value(apiKeyCtxKey{}).(string)
+	return name
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// isExpensiveRequest returns whether the request runs with a separate budget,
+// i.e. debug APIs, logs filtering and contract calls.
+func isExpensiveRequest(r *http.Request) bool {
+	path := r.URL.Path
+	if strings.HasPrefix(path, "/debug/") || strings.HasPrefix(path, "/logs/") {
+		return true
+	}
+	return r.Method == http.MethodPost && (path == "/accounts" || strings.HasPrefix(path, "/accounts/"))
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(2, now)
+
+	for i := 0; i < 2; i++ {
+		_, ok := b.take(now)
+		assert.True(t, ok)
+	}
+	wait, ok := b.take(now)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+	assert.False(t, b.full(now))
+
+	_, ok = b.take(now.Add(500 * time.Millisecond))
+	assert.True(t, ok)
+	assert.True(t, b.full(now.Add(2*time.Second)))
+
+	// burst is at least 1
+	b = newTokenBucket(0.5, now)
+	_, ok = b.take(now)
+	assert.True(t, ok)
+	wait, ok = b.take(now)
+	assert.False(t, ok)
+	assert.Equal(t, 2*time.Second, wait)
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	tests := map[string]string{
+		"not json":         `{}`,
+		"missing key":      `[{"name":"a"}]`,
+		"negative rate":    `[{"name":"a","key":"k","rate":-1}]`,
+		"duplicated key":   `[{"name":"a","key":"k"},{"name":"b","key":"k"}]`,
+		"duplicated name":  `[{"name":"a","key":"k1"},{"name":"a","key":"k2"}]`,
+		"negative expense": `[{"name":"a","key":"k","expensiveRate":-1}]`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseAPIKeys([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+
+	keys, err := parseAPIKeys([]byte(`[{"name":"a","key":"k1","rate":0},{"name":"b","key":"k2"}]`))
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, float64(0), *keys["k1"].Rate)
+	assert.Nil(t, keys["k2"].Rate)
+}
+
+func TestRateLimiter(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.json")
+	writeKeys := func(data string, modTime time.Time) {
+		if err := os.WriteFile(keysFile, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(keysFile, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeKeys(`[{"name":"unlimited","key":"k1","rate":0,"expensiveRate":0},{"name":"default","key":"k2"}]`, time.Now())
+
+	_, err := NewRateLimiter(RateLimitOptions{KeyRequired: true})
+	assert.Error(t, err)
+
+	limiter, err := NewRateLimiter(RateLimitOptions{KeysFile: keysFile, Rate: 1, ExpensiveRate: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer limiter.Close()
+
+	router := mux.NewRouter()
+	ok := func(w http.ResponseWriter, _ *http.Request) {}
+	router.HandleFunc("/blocks/best", ok)
+	router.HandleFunc("/accounts/*", ok)
+	router.HandleFunc("/health/ready", ok)
+	router.Use(limiter.Middleware)
+
+	call := func(method, path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if key != "" {
+			req.Header.Set(APIKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// per ip
+	assert.Equal(t, http.StatusOK, call("GET", "/blocks/best", "").Code)
+	rec := call("GET", "/blocks/best", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "rate limit exceeded\n", rec.Body.String())
+
+	// expensive routes have a separate budget
+	assert.Equal(t, http.StatusOK, call("POST", "/accounts/*", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, call("POST", "/accounts/*", "").Code)
+
+	// health probes are not limited
+	assert.Equal(t, http.StatusOK, call("GET", "/health/ready", "").Code)
+	assert.Equal(t, http.StatusOK, call("GET", "/health/ready", "").Code)
+
+	// per key
+	assert.Equal(t, http.StatusUnauthorized, call("GET", "/blocks/best", "bad").Code)
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, call("GET", "/blocks/best", "k1").Code)
+	}
+	assert.Equal(t, http.StatusOK, call("GET", "/blocks/best", "k2").Code)
+	assert.Equal(t, http.StatusTooManyRequests, call("GET", "/blocks/best", "k2").Code)
+
+	// key in query
+	assert.Equal(t, http.StatusOK, call("GET", "/blocks/best?api_key=k1", "").Code)
+
+	// reload
+	writeKeys(`[{"name":"default","key":"k2","rate":0}]`, time.Now().Add(time.Minute))
+	reloaded, err := limiter.reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, http.StatusUnauthorized, call("GET", "/blocks/best", "k1").Code)
+	assert.Equal(t, http.StatusOK, call("GET", "/blocks/best", "k2").Code)
+
+	// keep the previous keys if the file is broken
+	writeKeys(`[`, time.Now().Add(2*time.Minute))
+	_, err = limiter.reload()
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusOK, call("GET", "/blocks/best", "k2").Code)
+
+	// refilled buckets are dropped
+	limiter.cleanup(time.Now().Add(time.Minute))
+	assert.Empty(t, limiter.buckets)
+}
+
+func TestRateLimiterKeyRequired(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(keysFile, []byte(`[{"name":"a","key":"k"}]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	limiter, err := NewRateLimiter(RateLimitOptions{KeysFile: keysFile, KeyRequired: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer limiter.Close()
+
+	var keyName string
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keyName = apiKeyName(r)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/blocks/best", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "api key required\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/blocks/best?api_key=k", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "a", keyName)
+}
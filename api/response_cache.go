@@ -0,0 +1,195 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/metrics"
+	"github.com/vechain/thor/v2/thor"
+)
+
+var metricResponseCacheCounter = metrics.LazyLoadCounterVec("api_response_cache_count", []string{"event"})
+
+// cachedResponse an immutable response with its ETag.
+type cachedResponse struct {
+	contentType string
+	etag        string
+	body        []byte
+}
+
+func newCachedResponse(contentType string, body []byte) *cachedResponse {
+	hash := thor.Blake2b(body)
+	return &cachedResponse{
+		contentType: contentType,
+		etag:        `"` + hex.EncodeToString(hash[:16]) + `"`,
+		body:        body,
+	}
+}
+
+// write writes the response, or 304 if the ETag matches the If-None-Match header of the request.
+func (resp *cachedResponse) write(w http.ResponseWriter, req *http.Request) {
+	h := w.Header()
+	h.Set("Cache-Control", utils.ImmutableCacheControl)
+	h.Set("ETag", resp.etag)
+	if etagMatch(req.Header.Get("If-None-Match"), resp.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if resp.contentType != "" {
+		h.Set("Content-Type", resp.contentType)
+	}
+	w.Write(resp.body)
+}
+
+// etagMatch returns whether the If-None-Match header matches the etag, using the weak comparison.
+func etagMatch(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// responseCache keeps immutable responses in memory, bounded by the total size of the bodies.
+// Responses are marked as immutable by handlers via utils.SetImmutable.
+type responseCache struct {
+	lock    sync.Mutex
+	lru     *simplelru.LRU
+	size    int
+	maxSize int
+}
+
+// newResponseCache creates the response cache, responses are not kept if maxSize is 0,
+// but ETags and conditional requests are still handled.
+func newResponseCache(maxSize int) *responseCache {
+	c := &responseCache{maxSize: maxSize}
+	c.lru, _ = simplelru.NewLRU(math.MaxInt32, func(_, value interface{}) {
+		c.size -= len(value.(*cachedResponse).body)
+	})
+	return c
+}
+
+func (c *responseCache) get(key string) (*cachedResponse, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if v, ok := c.lru.Get(key); ok {
+		return v.(*cachedResponse), true
+	}
+	return nil, false
+}
+
+func (c *responseCache) add(key string, resp *cachedResponse) {
+	// too large to be cached
+	if len(resp.body) > c.maxSize {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.lru.Remove(key)
+	c.lru.Add(key, resp)
+	c.size += len(resp.body)
+	for c.size > c.maxSize {
+		c.lru.RemoveOldest()
+	}
+}
+
+// Middleware returns a handler serving immutable GET responses from the cache,
+// and handling ETags of immutable responses.
+func (c *responseCache) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		key := req.URL.RequestURI()
+		if resp, ok := c.get(key); ok {
+			metricResponseCacheCounter().AddWithLabel(1, map[string]string{"event": "hit"})
+			resp.write(w, req)
+			return
+		}
+
+		crw := &cacheResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(crw, req)
+		if crw.buf == nil {
+			// not immutable, already written
+			return
+		}
+
+		metricResponseCacheCounter().AddWithLabel(1, map[string]string{"event": "miss"})
+		resp := newCachedResponse(w.Header().Get("Content-Type"), crw.buf.Bytes())
+		c.add(key, resp)
+		resp.write(w, req)
+	})
+}
+
+// cacheResponseWriter buffers the response if it's marked as immutable, otherwise writes it through.
+type cacheResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	buf         *bytes.Buffer
+}
+
+func (w *cacheResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if utils.IsImmutable(w.Header()) {
+		if code == http.StatusOK {
+			w.buf = new(bytes.Buffer)
+			return
+		}
+		// errors occurred after being marked
+		w.Header().Del("Cache-Control")
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buf != nil {
+		return w.buf.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Hijack complies the writer with WS subscriptions interface.
+func (w *cacheResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijack not supported")
+	}
+	return h.Hijack()
+}
+
+// Flush complies the writer with SSE subscriptions interface, buffered responses are written at the end.
+func (w *cacheResponseWriter) Flush() {
+	if w.buf != nil {
+		return
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
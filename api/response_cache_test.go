@@ -0,0 +1,122 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/api/utils"
+)
+
+func TestEtagMatch(t *testing.T) {
+	etag := `"abc"`
+	assert.True(t, etagMatch(`"abc"`, etag))
+	assert.True(t, etagMatch(`W/"abc"`, etag))
+	assert.True(t, etagMatch(`"foo", "abc"`, etag))
+	assert.True(t, etagMatch(`*`, etag))
+	assert.False(t, etagMatch(``, etag))
+	assert.False(t, etagMatch(`"foo"`, etag))
+}
+
+func TestResponseCache(t *testing.T) {
+	calls := make(map[string]int)
+	router := mux.NewRouter()
+	router.Path("/immutable/{id}").HandlerFunc(utils.WrapHandlerFunc(func(w http.ResponseWriter, req *http.Request) error {
+		id := mux.Vars(req)["id"]
+		calls[id]++
+		utils.SetImmutable(w)
+		return utils.WriteJSON(w, map[string]string{"id": id})
+	}))
+	router.Path("/mutable").HandlerFunc(utils.WrapHandlerFunc(func(w http.ResponseWriter, _ *http.Request) error {
+		calls["mutable"]++
+		return utils.WriteJSON(w, "mutable")
+	}))
+	router.Path("/failed").HandlerFunc(utils.WrapHandlerFunc(func(w http.ResponseWriter, _ *http.Request) error {
+		utils.SetImmutable(w)
+		return errors.New("failed")
+	}))
+	// each body is 11 bytes, holds 2 responses
+	c := newResponseCache(22)
+	router.Use(c.Middleware)
+
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/immutable/a", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "{\"id\":\"a\"}\n", rec.Body.String())
+	assert.Equal(t, utils.ImmutableCacheControl, rec.Header().Get("Cache-Control"))
+	assert.Equal(t, utils.JSONContentType, rec.Header().Get("Content-Type"))
+	etag := rec.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// served from the cache
+	rec = get("/immutable/a", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "{\"id\":\"a\"}\n", rec.Body.String())
+	assert.Equal(t, etag, rec.Header().Get("ETag"))
+	assert.Equal(t, 1, calls["a"])
+
+	rec = get("/immutable/a", etag)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	// evicted by the size bound
+	get("/immutable/b", "")
+	get("/immutable/c", "")
+	assert.Equal(t, 22, c.size)
+	get("/immutable/a", "")
+	assert.Equal(t, 2, calls["a"])
+
+	// mutable responses are neither cached nor tagged
+	for i := 0; i < 2; i++ {
+		rec = get("/mutable", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("ETag"))
+		assert.Empty(t, rec.Header().Get("Cache-Control"))
+	}
+	assert.Equal(t, 2, calls["mutable"])
+
+	rec = get("/failed", "")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Empty(t, rec.Header().Get("Cache-Control"))
+}
+
+func TestResponseCacheDisabled(t *testing.T) {
+	calls := 0
+	router := mux.NewRouter()
+	router.Path("/immutable").HandlerFunc(utils.WrapHandlerFunc(func(w http.ResponseWriter, _ *http.Request) error {
+		calls++
+		utils.SetImmutable(w)
+		return utils.WriteJSON(w, "immutable")
+	}))
+	router.Use(newResponseCache(0).Middleware)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/immutable", nil))
+	etag := rec.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// conditional requests are still handled
+	req := httptest.NewRequest(http.MethodGet, "/immutable", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"sync"
+
+	"github.com/vechain/thor/v2/chain"
+)
+
+const blockQueueSize = 20
+
+// blockFeed reads new blocks from the chain once and fans them out to all listeners.
+type blockFeed struct {
+	repo      *chain.Repository
+	listeners map[chan []*chain.ExtendedBlock]struct{}
+	mu        sync.Mutex
+}
+
+func newBlockFeed(repo *chain.Repository) *blockFeed {
+	return &blockFeed{
+		repo:      repo,
+		listeners: make(map[chan []*chain.ExtendedBlock]struct{}),
+	}
+}
+
+func (f *blockFeed) Subscribe(ch chan []*chain.ExtendedBlock) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.listeners[ch] = struct{}{}
+}
+
+func (f *blockFeed) Unsubscribe(ch chan []*chain.ExtendedBlock) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.listeners, ch)
+}
+
+func (f *blockFeed) DispatchLoop(done <-chan struct{}) {
+	reader := f.repo.NewBlockReader(f.repo.BestBlockSummary().Header.ID())
+	ticker := f.repo.NewTicker()
+
+	for {
+		blocks, err := reader.Read()
+		if err != nil {
+			logger.Warn("block feed read", "err", err)
+		} else if len(blocks) > 0 {
+			f.dispatch(blocks)
+			select {
+			case <-done:
+				return
+			default:
+				continue
+			}
+		}
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+func (f *blockFeed) dispatch(blocks []*chain.ExtendedBlock) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for lsn := range f.listeners {
+		select {
+		case lsn <- blocks:
+		default:
+			// the listener can't keep up, drop it and close the channel to notify it
+			delete(f.listeners, lsn)
+			close(lsn)
+		}
+	}
+}
+
+// blockBuffer is a chain.BlockReader which returns the blocks pushed by the feed,
+// so that the msg readers can be reused on top of the shared feed.
+type blockBuffer struct {
+	blocks []*chain.ExtendedBlock
+}
+
+func (b *blockBuffer) Push(blocks []*chain.ExtendedBlock) {
+	b.blocks = append(b.blocks, blocks...)
+}
+
+func (b *blockBuffer) Read() ([]*chain.ExtendedBlock, error) {
+	blocks := b.blocks
+	b.blocks = nil
+	return blocks, nil
+}
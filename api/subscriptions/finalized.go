@@ -0,0 +1,122 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/bft"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// finalizedBlockReader reads blocks of the finalized chain, so the blocks are never obsolete.
+type finalizedBlockReader struct {
+	repo *chain.Repository
+	bft  bft.Committer
+	pos  thor.Bytes32
+}
+
+func (r *finalizedBlockReader) Read() ([]*chain.ExtendedBlock, error) {
+	finalized := r.bft.Finalized()
+	num := block.Number(r.pos)
+	if block.Number(finalized) <= num {
+		return nil, nil
+	}
+
+	finalizedChain := r.repo.NewChain(finalized)
+	// the position might be on a branch when it's given ahead of the finalized block
+	id, err := finalizedChain.GetBlockID(num)
+	if err != nil {
+		return nil, err
+	}
+	if id != r.pos {
+		return nil, errors.New("pos: not on the finalized chain")
+	}
+
+	next, err := finalizedChain.GetBlock(num + 1)
+	if err != nil {
+		return nil, err
+	}
+	r.pos = next.Header().ID()
+	return []*chain.ExtendedBlock{{Block: next}}, nil
+}
+
+// finalizedReader holds msgs until their block is finalized, and appends a FinalizedMessage
+// after the msgs of each finalized block.
+type finalizedReader struct {
+	msgReader
+	blockReader *finalizedBlockReader
+}
+
+func (s *Subscriptions) newFinalizedReader(reader msgReader, position thor.Bytes32) *finalizedReader {
+	br := &finalizedBlockReader{
+		repo: s.repo,
+		bft:  s.bft,
+		pos:  position,
+	}
+	*blockReaderOf(reader) = br
+	return &finalizedReader{
+		msgReader:   reader,
+		blockReader: br,
+	}
+}
+
+func (r *finalizedReader) Read() ([]interface{}, bool, error) {
+	lastPos := r.blockReader.pos
+	msgs, hasMore, err := r.msgReader.Read()
+	if err != nil {
+		return nil, false, err
+	}
+	if pos := r.blockReader.pos; pos != lastPos {
+		msgs = append(msgs, &FinalizedMessage{
+			Finalized: FinalizedBlock{
+				ID:     pos,
+				Number: block.Number(pos),
+			},
+		})
+	}
+	return msgs, hasMore, nil
+}
+
+// parseFinalizedPosition parses the position in finalized mode, defaults to the finalized block.
+func (s *Subscriptions) parseFinalizedPosition(posStr string) (thor.Bytes32, error) {
+	finalized := s.bft.Finalized()
+	if posStr == "" {
+		return finalized, nil
+	}
+	pos, err := thor.ParseBytes32(posStr)
+	if err != nil {
+		return thor.Bytes32{}, utils.BadRequest(errors.WithMessage(err, "pos"))
+	}
+	if block.Number(pos) < block.Number(finalized) && block.Number(finalized)-block.Number(pos) > s.backtraceLimit {
+		return thor.Bytes32{}, utils.Forbidden(errors.New("pos: backtrace limit exceeded"))
+	}
+	return pos, nil
+}
+
+// blockReaderOf returns the pointer to the block reader of the msg reader, to replace the block source.
+func blockReaderOf(reader msgReader) *chain.BlockReader {
+	switch r := reader.(type) {
+	case *blockReader:
+		return &r.blockReader
+	case *eventReader:
+		return &r.blockReader
+	case *transferReader:
+		return &r.blockReader
+	case *beatReader:
+		return &r.blockReader
+	case *beat2Reader:
+		return &r.blockReader
+	case *finalizedReader:
+		return blockReaderOf(r.msgReader)
+	default:
+		panic(fmt.Sprintf("unexpected msg reader %T", reader))
+	}
+}
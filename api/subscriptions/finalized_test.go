@@ -0,0 +1,137 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/thor"
+)
+
+type mockCommitter struct {
+	finalized thor.Bytes32
+}
+
+func (c *mockCommitter) Finalized() thor.Bytes32 {
+	return c.finalized
+}
+
+func (c *mockCommitter) Justified() (thor.Bytes32, error) {
+	return c.finalized, nil
+}
+
+func TestFinalizedReader(t *testing.T) {
+	repo, blocks, _ := initChainMultipleBlocks(t, 3)
+	committer := &mockCommitter{finalized: blocks[2].Header().ID()}
+	s := &Subscriptions{repo: repo, bft: committer, backtraceLimit: 5}
+
+	reader := s.newFinalizedReader(newBlockReader(repo, blocks[0].Header().ID()), blocks[0].Header().ID())
+
+	for _, b := range blocks[1:3] {
+		msgs, hasMore, err := reader.Read()
+		assert.NoError(t, err)
+		assert.True(t, hasMore)
+		assert.Len(t, msgs, 2)
+		assert.Equal(t, b.Header().ID(), msgs[0].(*BlockMessage).ID)
+		assert.False(t, msgs[0].(*BlockMessage).Obsolete)
+		assert.Equal(t, &FinalizedMessage{Finalized: FinalizedBlock{ID: b.Header().ID(), Number: b.Header().Number()}}, msgs[1])
+	}
+
+	// held until the next block is finalized
+	msgs, hasMore, err := reader.Read()
+	assert.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.Empty(t, msgs)
+
+	committer.finalized = blocks[3].Header().ID()
+	msgs, _, err = reader.Read()
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 2)
+	assert.Equal(t, blocks[3].Header().ID(), msgs[0].(*BlockMessage).ID)
+
+	// position not on the finalized chain
+	var branch thor.Bytes32
+	copy(branch[:], blocks[1].Header().ID().Bytes()[:4])
+	branch[31] = 1
+	reader = s.newFinalizedReader(newBlockReader(repo, branch), branch)
+	_, _, err = reader.Read()
+	assert.EqualError(t, err, "pos: not on the finalized chain")
+}
+
+func TestParseFinalizedPosition(t *testing.T) {
+	repo, blocks, _ := initChainMultipleBlocks(t, 3)
+	s := &Subscriptions{repo: repo, bft: &mockCommitter{finalized: blocks[3].Header().ID()}, backtraceLimit: 1}
+
+	pos, err := s.parseFinalizedPosition("")
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[3].Header().ID(), pos)
+
+	pos, err = s.parseFinalizedPosition(blocks[2].Header().ID().String())
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[2].Header().ID(), pos)
+
+	_, err = s.parseFinalizedPosition("0xbad")
+	assert.Error(t, err)
+
+	_, err = s.parseFinalizedPosition(blocks[0].Header().ID().String())
+	assert.EqualError(t, err, "pos: backtrace limit exceeded")
+}
+
+func TestFinalizedSubscription(t *testing.T) {
+	repo, blocks, txPool := initChainMultipleBlocks(t, 3)
+	router := mux.NewRouter()
+	sub := New(repo, []string{}, 5, txPool, &mockCommitter{finalized: blocks[1].Header().ID()})
+	defer sub.Close()
+	sub.Mount(router, "/subscriptions")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	for _, query := range []string{"finalized=bad", "finalized=true"} {
+		res, err := http.Get(ts.URL + "/subscriptions/beat2?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+	}
+
+	u := url.URL{
+		Scheme:   "ws",
+		Host:     strings.TrimPrefix(ts.URL, "http://"),
+		Path:     "/subscriptions/block",
+		RawQuery: "finalized=true&pos=" + blocks[0].Header().ID().String(),
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var blockMsg BlockMessage
+	if err := conn.ReadJSON(&blockMsg); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, blocks[1].Header().ID(), blockMsg.ID)
+
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var marker FinalizedMessage
+	if err := json.Unmarshal(data, &marker); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, blocks[1].Header().ID(), marker.Finalized.ID)
+	assert.Equal(t, uint32(1), marker.Finalized.Number)
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/tx"
+)
+
+// max number of subscriptions on a single multiplexed connection
+const maxMuxSubscriptions = 100
+
+const (
+	muxMethodSubscribe   = "subscribe"
+	muxMethodUnsubscribe = "unsubscribe"
+)
+
+type muxSubscription struct {
+	buf    *blockBuffer
+	reader msgReader // nil for txpool subject
+}
+
+// newMuxSubscription creates a subscription reading blocks from the shared feed.
+func (s *Subscriptions) newMuxSubscription(subject string, filter json.RawMessage) (*muxSubscription, error) {
+	hasFilter := len(filter) > 0 && !bytes.Equal(filter, []byte("null"))
+	decodeFilter := func(v interface{}) error {
+		if !hasFilter {
+			return nil
+		}
+		decoder := json.NewDecoder(bytes.NewReader(filter))
+		decoder.DisallowUnknownFields()
+		return errors.WithMessage(decoder.Decode(v), "filter")
+	}
+
+	buf := &blockBuffer{}
+	sub := &muxSubscription{buf: buf}
+	switch subject {
+	case "event":
+		filter := &EventFilter{}
+		if err := decodeFilter(filter); err != nil {
+			return nil, err
+		}
+		sub.reader = &eventReader{repo: s.repo, filter: filter, blockReader: buf}
+		return sub, nil
+	case "transfer":
+		filter := &TransferFilter{}
+		if err := decodeFilter(filter); err != nil {
+			return nil, err
+		}
+		sub.reader = &transferReader{repo: s.repo, filter: filter, blockReader: buf}
+		return sub, nil
+	}
+
+	if hasFilter {
+		return nil, errors.New("filter: not supported by subject")
+	}
+	switch subject {
+	case "block":
+		sub.reader = &blockReader{repo: s.repo, blockReader: buf}
+	case "beat2":
+		sub.reader = &beat2Reader{repo: s.repo, blockReader: buf}
+	case "txpool":
+	default:
+		return nil, errors.New("subject: unsupported")
+	}
+	return sub, nil
+}
+
+func (s *Subscriptions) handleMultiplexed(w http.ResponseWriter, req *http.Request) error {
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	conn, err := s.upgrader.Upgrade(w, req, nil)
+	// since the conn is hijacked here, no error should be returned in lines below
+	if err != nil {
+		logger.Debug("upgrade to websocket", "err", err)
+		return nil
+	}
+
+	type request struct {
+		req *MuxRequest
+		err error
+	}
+	var (
+		reqCh  = make(chan request)
+		closed = make(chan struct{})
+		quit   = make(chan struct{})
+	)
+	defer close(quit)
+
+	// read loop to handle requests and close event
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(closed)
+
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error {
+			conn.SetReadDeadline(time.Now().Add(pongWait))
+			return nil
+		})
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				logger.Debug("websocket read err", "err", err)
+				return
+			}
+			var r request
+			r.req = &MuxRequest{}
+			if err := json.Unmarshal(data, r.req); err != nil {
+				r.err = err
+			}
+			select {
+			case reqCh <- r:
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	var (
+		subs    = make(map[string]*muxSubscription)
+		nextID  uint64
+		blockCh chan []*chain.ExtendedBlock
+		txCh    chan *tx.Transaction
+	)
+	defer func() {
+		if blockCh != nil {
+			s.blockFeed.Unsubscribe(blockCh)
+		}
+		if txCh != nil {
+			s.pendingTx.Unsubscribe(txCh)
+		}
+	}()
+
+	handleRequest := func(r request) *MuxResponse {
+		if r.err != nil {
+			return &MuxResponse{Error: "invalid request: " + r.err.Error()}
+		}
+		resp := &MuxResponse{ID: r.req.ID}
+		switch r.req.Method {
+		case muxMethodSubscribe:
+			if len(subs) >= maxMuxSubscriptions {
+				resp.Error = "too many subscriptions"
+				return resp
+			}
+			sub, err := s.newMuxSubscription(r.req.Subject, r.req.Filter)
+			if err != nil {
+				resp.Error = err.Error()
+				return resp
+			}
+			if sub.reader != nil && blockCh == nil {
+				blockCh = make(chan []*chain.ExtendedBlock, blockQueueSize)
+				s.blockFeed.Subscribe(blockCh)
+			}
+			if sub.reader == nil && txCh == nil {
+				txCh = make(chan *tx.Transaction, txQueueSize)
+				s.pendingTx.Subscribe(txCh)
+			}
+			nextID++
+			resp.Subscription = strconv.FormatUint(nextID, 10)
+			subs[resp.Subscription] = sub
+		case muxMethodUnsubscribe:
+			if _, ok := subs[r.req.Subscription]; !ok {
+				resp.Error = "subscription not found"
+				return resp
+			}
+			delete(subs, r.req.Subscription)
+			resp.Subscription = r.req.Subscription
+		default:
+			resp.Error = "method: unsupported"
+		}
+		return resp
+	}
+
+	pingTicker := time.NewTicker(pingPeriod)
+	defer pingTicker.Stop()
+
+	err = func() error {
+		for {
+			select {
+			case r := <-reqCh:
+				if err := conn.WriteJSON(handleRequest(r)); err != nil {
+					return err
+				}
+			case blocks, ok := <-blockCh:
+				if !ok {
+					// closed by the feed
+					blockCh = nil
+					return errors.New("subscriber lagged behind")
+				}
+				for id, sub := range subs {
+					if sub.reader == nil {
+						continue
+					}
+					sub.buf.Push(blocks)
+					msgs, _, err := sub.reader.Read()
+					if err != nil {
+						return err
+					}
+					for _, msg := range msgs {
+						if err := conn.WriteJSON(&MuxMessage{Subscription: id, Data: msg}); err != nil {
+							return err
+						}
+					}
+				}
+			case tx := <-txCh:
+				for id, sub := range subs {
+					if sub.reader != nil {
+						continue
+					}
+					if err := conn.WriteJSON(&MuxMessage{Subscription: id, Data: &PendingTxIDMessage{ID: tx.ID()}}); err != nil {
+						return err
+					}
+				}
+			case <-s.done:
+				return nil
+			case <-closed:
+				return nil
+			case <-pingTicker.C:
+				conn.WriteMessage(websocket.PingMessage, nil)
+			}
+		}
+	}()
+	s.closeConn(conn, err)
+	return nil
+}
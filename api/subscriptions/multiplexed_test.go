@@ -0,0 +1,166 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/cmd/thor/solo"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+type muxPush struct {
+	Subscription string          `json:"subscription"`
+	Data         json.RawMessage `json:"data"`
+}
+
+func TestMultiplexed(t *testing.T) {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	b0, _, _, err := genesis.NewDevnet().Build(stater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, _ := chain.NewRepository(db, b0)
+	txPool := txpool.New(repo, stater, txpool.Options{
+		Limit:           100,
+		LimitPerAccount: 16,
+		MaxLifetime:     time.Hour,
+	})
+
+	router := mux.NewRouter()
+	sub := New(repo, []string{}, 5, txPool, solo.NewBFTEngine(repo))
+	defer sub.Close()
+	sub.Mount(router, "/subscriptions")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	u := url.URL{Scheme: "ws", Host: strings.TrimPrefix(ts.URL, "http://"), Path: "/subscriptions/ws"}
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	call := func(req interface{}) *MuxResponse {
+		if err := conn.WriteJSON(req); err != nil {
+			t.Fatal(err)
+		}
+		var resp MuxResponse
+		if err := conn.ReadJSON(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return &resp
+	}
+
+	// bad requests
+	assert.Contains(t, call("not a request").Error, "invalid request")
+	assert.Equal(t, &MuxResponse{ID: 1, Error: "method: unsupported"}, call(&MuxRequest{ID: 1, Method: "foo"}))
+	assert.Equal(t, &MuxResponse{ID: 2, Error: "subject: unsupported"}, call(&MuxRequest{ID: 2, Method: "subscribe", Subject: "beat"}))
+	assert.Equal(t, &MuxResponse{ID: 3, Error: "filter: not supported by subject"},
+		call(&MuxRequest{ID: 3, Method: "subscribe", Subject: "block", Filter: json.RawMessage(`{"address":"0x00"}`)}))
+	assert.Contains(t, call(&MuxRequest{ID: 4, Method: "subscribe", Subject: "event", Filter: json.RawMessage(`{"foo":1}`)}).Error, "filter")
+	assert.Equal(t, &MuxResponse{ID: 5, Error: "subscription not found"}, call(&MuxRequest{ID: 5, Method: "unsubscribe", Subscription: "1"}))
+
+	// subscribe
+	assert.Equal(t, &MuxResponse{ID: 6, Subscription: "1"}, call(&MuxRequest{ID: 6, Method: "subscribe", Subject: "block"}))
+	assert.Equal(t, &MuxResponse{ID: 7, Subscription: "2"}, call(&MuxRequest{ID: 7, Method: "subscribe", Subject: "beat2"}))
+	assert.Equal(t, &MuxResponse{ID: 8, Subscription: "3"},
+		call(&MuxRequest{ID: 8, Method: "subscribe", Subject: "transfer", Filter: json.RawMessage(`{"sender":"` + genesis.DevAccounts()[0].Address.String() + `"}`)}))
+	assert.Equal(t, &MuxResponse{ID: 9, Subscription: "3"}, call(&MuxRequest{ID: 9, Method: "unsubscribe", Subscription: "3"}))
+
+	// new block is piped to each subscription
+	addNewBlock(repo, stater, b0, t)
+	best := repo.BestBlockSummary().Header
+
+	pushes := make(map[string]json.RawMessage)
+	for i := 0; i < 2; i++ {
+		var push muxPush
+		if err := conn.ReadJSON(&push); err != nil {
+			t.Fatal(err)
+		}
+		pushes[push.Subscription] = push.Data
+	}
+	assert.Len(t, pushes, 2)
+
+	var blockMsg BlockMessage
+	if err := json.Unmarshal(pushes["1"], &blockMsg); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, best.ID(), blockMsg.ID)
+
+	var beat2Msg Beat2Message
+	if err := json.Unmarshal(pushes["2"], &beat2Msg); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, best.ID(), beat2Msg.ID)
+
+	// pending txs
+	assert.Equal(t, &MuxResponse{ID: 10, Subscription: "4"}, call(&MuxRequest{ID: 10, Method: "subscribe", Subject: "txpool"}))
+	trx := createTx(t, repo, 0)
+	if err := txPool.AddLocal(trx); err != nil {
+		t.Fatal(err)
+	}
+
+	var push muxPush
+	if err := conn.ReadJSON(&push); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "4", push.Subscription)
+	var txMsg PendingTxIDMessage
+	if err := json.Unmarshal(push.Data, &txMsg); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, trx.ID(), txMsg.ID)
+}
+
+func TestBlockFeedDropsLaggedListener(t *testing.T) {
+	repo, _, _ := initChain(t)
+	feed := newBlockFeed(repo)
+
+	lagged := make(chan []*chain.ExtendedBlock)
+	ch := make(chan []*chain.ExtendedBlock, 1)
+	feed.Subscribe(lagged)
+	feed.Subscribe(ch)
+
+	feed.dispatch([]*chain.ExtendedBlock{})
+
+	_, ok := <-lagged
+	assert.False(t, ok, "lagged listener should be closed")
+	assert.NotContains(t, feed.listeners, lagged)
+	assert.Contains(t, feed.listeners, ch)
+	assert.Len(t, ch, 1)
+}
+
+func TestBlockBuffer(t *testing.T) {
+	buf := &blockBuffer{}
+
+	blocks, err := buf.Read()
+	assert.NoError(t, err)
+	assert.Empty(t, blocks)
+
+	buf.Push([]*chain.ExtendedBlock{{}, {}})
+	buf.Push([]*chain.ExtendedBlock{{}})
+	blocks, err = buf.Read()
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 3)
+
+	blocks, err = buf.Read()
+	assert.NoError(t, err)
+	assert.Empty(t, blocks)
+}
@@ -15,15 +15,17 @@ import (
 )
 
 type pendingTx struct {
-	txPool    *txpool.TxPool
-	listeners map[chan *tx.Transaction]struct{}
-	mu        sync.Mutex
+	txPool         *txpool.TxPool
+	listeners      map[chan *tx.Transaction]struct{}
+	eventListeners map[chan *txpool.TxEvent]struct{}
+	mu             sync.Mutex
 }
 
 func newPendingTx(txPool *txpool.TxPool) *pendingTx {
 	p := &pendingTx{
-		txPool:    txPool,
-		listeners: make(map[chan *tx.Transaction]struct{}),
+		txPool:         txPool,
+		listeners:      make(map[chan *tx.Transaction]struct{}),
+		eventListeners: make(map[chan *txpool.TxEvent]struct{}),
 	}
 
 	return p
@@ -43,6 +45,21 @@ func (p *pendingTx) Unsubscribe(ch chan *tx.Transaction) {
 	delete(p.listeners, ch)
 }
 
+// SubscribeEvent subscribes all tx events of the pool, including non-executable and evicted txs.
+func (p *pendingTx) SubscribeEvent(ch chan *txpool.TxEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.eventListeners[ch] = struct{}{}
+}
+
+func (p *pendingTx) UnsubscribeEvent(ch chan *txpool.TxEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.eventListeners, ch)
+}
+
 func (p *pendingTx) DispatchLoop(done <-chan struct{}) {
 	txCh := make(chan *txpool.TxEvent)
 	sub := p.txPool.SubscribeTxEvent(txCh)
@@ -53,17 +70,20 @@ func (p *pendingTx) DispatchLoop(done <-chan struct{}) {
 	for {
 		select {
 		case txEv := <-txCh:
-			if txEv.Executable == nil || !*txEv.Executable {
-				continue
-			}
-			now := time.Now().Unix()
-			// ignored if seen within half block interval
-			if seen, ok := knownTx.Get(txEv.Tx.ID()); ok && now-seen.(int64) <= int64(thor.BlockInterval/2) {
-				continue
+			executable := txEv.Executable != nil && *txEv.Executable
+			if executable {
+				now := time.Now().Unix()
+				// ignored if seen within half block interval
+				if seen, ok := knownTx.Get(txEv.Tx.ID()); ok && now-seen.(int64) <= int64(thor.BlockInterval/2) {
+					continue
+				}
+				knownTx.Add(txEv.Tx.ID(), now)
 			}
-			knownTx.Add(txEv.Tx.ID(), now)
 
-			p.dispatch(txEv.Tx, done)
+			p.dispatchEvent(txEv, done)
+			if executable {
+				p.dispatch(txEv.Tx, done)
+			}
 		case <-done:
 			return
 		}
@@ -83,3 +103,17 @@ func (p *pendingTx) dispatch(tx *tx.Transaction, done <-chan struct{}) {
 		}
 	}
 }
+
+func (p *pendingTx) dispatchEvent(ev *txpool.TxEvent, done <-chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for lsn := range p.eventListeners {
+		select {
+		case lsn <- ev:
+		case <-done:
+			return
+		default:
+		}
+	}
+}
@@ -6,14 +6,22 @@
 package subscriptions
 
 import (
+	"io"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/cmd/thor/solo"
 	"github.com/vechain/thor/v2/genesis"
 	"github.com/vechain/thor/v2/muxdb"
 	"github.com/vechain/thor/v2/packer"
@@ -107,6 +115,94 @@ func TestPendingTx_DispatchLoop(t *testing.T) {
 	}
 }
 
+func TestPendingTx_DispatchEvent(t *testing.T) {
+	_, _, txPool := initChain(t)
+	p := newPendingTx(txPool)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	evCh := make(chan *txpool.TxEvent, 1)
+	p.SubscribeEvent(evCh)
+	assert.Contains(t, p.eventListeners, evCh)
+
+	ev := &txpool.TxEvent{Evicted: true, Reason: "expired"}
+	p.dispatchEvent(ev, done)
+	assert.Equal(t, ev, <-evCh)
+
+	p.UnsubscribeEvent(evCh)
+	assert.NotContains(t, p.eventListeners, evCh)
+}
+
+func TestPendingTxSubscription(t *testing.T) {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	b0, _, _, err := genesis.NewDevnet().Build(stater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, _ := chain.NewRepository(db, b0)
+	txPool := txpool.New(repo, stater, txpool.Options{
+		Limit:           100,
+		LimitPerAccount: 16,
+		MaxLifetime:     time.Hour,
+	})
+	addNewBlock(repo, stater, b0, t)
+
+	router := mux.NewRouter()
+	sub := New(repo, []string{}, 5, txPool, solo.NewBFTEngine(repo))
+	defer sub.Close()
+	sub.Mount(router, "/subscriptions")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	for query, msg := range map[string]string{
+		"origin=0xbad":     "origin",
+		"to=0xbad":         "to",
+		"delegator=0xbad":  "delegator",
+		"selector=0x1234":  "selector: should be 4 bytes",
+		"selector=bad":     "selector",
+		"expanded=invalid": "expanded: should be boolean",
+	} {
+		res, err := http.Get(ts.URL + "/subscriptions/txpool?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+		assert.Contains(t, string(body), msg, query)
+	}
+
+	origin := genesis.DevAccounts()[1].Address
+	u := url.URL{
+		Scheme:   "ws",
+		Host:     strings.TrimPrefix(ts.URL, "http://"),
+		Path:     "/subscriptions/txpool",
+		RawQuery: "expanded=true&origin=" + origin.String(),
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// tx of other origin is filtered out
+	for _, i := range []uint{0, 1} {
+		if err := txPool.AddLocal(createTx(t, repo, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var msg PendingTxMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, PendingTxStatusExecutable, msg.Status)
+	assert.Equal(t, origin, msg.Tx.Origin)
+	assert.Equal(t, msg.ID, msg.Tx.ID)
+}
+
 func addNewBlock(repo *chain.Repository, stater *state.Stater, b0 *block.Block, t *testing.T) {
 	packer := packer.New(repo, stater, genesis.DevAccounts()[0].Address, &genesis.DevAccounts()[0].Address, thor.NoFork)
 	sum, _ := repo.GetBlockSummary(b0.Header().ID())
@@ -0,0 +1,142 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/thor"
+)
+
+const eventStreamContentType = "text/event-stream"
+
+// isEventStream returns whether the request asks for server-sent events instead of websocket.
+func isEventStream(req *http.Request) bool {
+	if websocket.IsWebSocketUpgrade(req) {
+		return false
+	}
+	return strings.Contains(req.Header.Get("Accept"), eventStreamContentType)
+}
+
+// positionTracker is a chain.BlockReader tracking the ID of the last read block,
+// which is used as the event ID, so that clients can resume with the Last-Event-ID header.
+type positionTracker struct {
+	chain.BlockReader
+	pos thor.Bytes32
+}
+
+func (t *positionTracker) Read() ([]*chain.ExtendedBlock, error) {
+	blocks, err := t.BlockReader.Read()
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) > 0 {
+		t.pos = blocks[len(blocks)-1].Header().ID()
+	}
+	return blocks, nil
+}
+
+// trackPosition replaces the block reader of the msg reader with a position tracker.
+func trackPosition(reader msgReader, pos thor.Bytes32) *positionTracker {
+	br := blockReaderOf(reader)
+	tracker := &positionTracker{BlockReader: *br, pos: pos}
+	*br = tracker
+	return tracker
+}
+
+func (s *Subscriptions) serveEventStream(w http.ResponseWriter, req *http.Request, reader msgReader, pos thor.Bytes32) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("streaming unsupported")
+	}
+	tracker := trackPosition(reader, pos)
+
+	w.Header().Set("Content-Type", eventStreamContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // disable buffering of nginx
+	w.WriteHeader(http.StatusOK)
+
+	// since the response is started here, no error should be returned in lines below
+	if err := s.pipeEvents(w, flusher, reader, tracker, req.Context().Done()); err != nil {
+		fmt.Fprintf(w, "event: error\ndata: %s\n\n", strings.ReplaceAll(err.Error(), "\n", " "))
+		flusher.Flush()
+	}
+	return nil
+}
+
+// pipeEvents writes msgs as server-sent events. The event ID is updated once all msgs of the
+// read blocks are written, so the delivery is at-least-once after resumption.
+func (s *Subscriptions) pipeEvents(w http.ResponseWriter, flusher http.Flusher, reader msgReader, tracker *positionTracker, closed <-chan struct{}) error {
+	ticker := s.repo.NewTicker()
+	pingTicker := time.NewTicker(pingPeriod)
+	defer pingTicker.Stop()
+
+	// a event with id only updates the last event ID without dispatching any event in clients
+	writeID := func() error {
+		if _, err := fmt.Fprintf(w, "id: %s\n\n", tracker.pos); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+	if err := writeID(); err != nil {
+		return nil
+	}
+
+	for {
+		lastPos := tracker.pos
+		msgs, hasMore, err := reader.Read()
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			data, err := json.Marshal(msg)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return nil
+			}
+		}
+		if tracker.pos != lastPos {
+			if err := writeID(); err != nil {
+				return nil
+			}
+		}
+
+		if hasMore {
+			select {
+			case <-s.done:
+				return nil
+			case <-closed:
+				return nil
+			default:
+			}
+		} else {
+			select {
+			case <-s.done:
+				return nil
+			case <-closed:
+				return nil
+			case <-ticker.C():
+			case <-pingTicker.C:
+				// comment line to keep the connection alive through proxies
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return nil
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
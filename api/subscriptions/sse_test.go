@@ -0,0 +1,126 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/cmd/thor/solo"
+	"github.com/vechain/thor/v2/thor"
+)
+
+func TestEventStream(t *testing.T) {
+	repo, blocks, txPool := initChain(t)
+	router := mux.NewRouter()
+	sub := New(repo, []string{}, 5, txPool, solo.NewBFTEngine(repo))
+	defer sub.Close()
+	sub.Mount(router, "/subscriptions")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	stream := func(path string, lastEventID string) (*http.Response, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Accept", "text/event-stream")
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res, cancel
+	}
+	readLine := func(r *bufio.Reader) string {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimRight(line, "\n")
+	}
+
+	t.Run("block", func(t *testing.T) {
+		res, cancel := stream("/subscriptions/block?pos="+blocks[0].Header().ID().String(), "")
+		defer cancel()
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+		r := bufio.NewReader(res.Body)
+		assert.Equal(t, "id: "+blocks[0].Header().ID().String(), readLine(r))
+		assert.Equal(t, "", readLine(r))
+
+		data := readLine(r)
+		assert.True(t, strings.HasPrefix(data, "data: "))
+		var blockMsg BlockMessage
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &blockMsg); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, blocks[1].Header().ID(), blockMsg.ID)
+		assert.Equal(t, "", readLine(r))
+
+		assert.Equal(t, "id: "+blocks[1].Header().ID().String(), readLine(r))
+	})
+
+	t.Run("resumeWithLastEventID", func(t *testing.T) {
+		// Last-Event-ID takes precedence over pos
+		res, cancel := stream("/subscriptions/transfer?pos="+blocks[0].Header().ID().String(), blocks[1].Header().ID().String())
+		defer cancel()
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		r := bufio.NewReader(res.Body)
+		assert.Equal(t, "id: "+blocks[1].Header().ID().String(), readLine(r))
+	})
+
+	t.Run("badLastEventID", func(t *testing.T) {
+		res, cancel := stream("/subscriptions/event", "0xbad")
+		defer cancel()
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		body, _ := io.ReadAll(res.Body)
+		assert.Contains(t, string(body), "pos")
+	})
+}
+
+func TestPositionTracker(t *testing.T) {
+	repo, blocks, _ := initChain(t)
+
+	reader := newBlockReader(repo, blocks[0].Header().ID())
+	tracker := trackPosition(reader, blocks[0].Header().ID())
+	assert.Equal(t, blocks[0].Header().ID(), tracker.pos)
+
+	msgs, _, err := reader.Read()
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, blocks[1].Header().ID(), tracker.pos)
+
+	// no new blocks, position unchanged
+	msgs, _, err = reader.Read()
+	assert.NoError(t, err)
+	assert.Empty(t, msgs)
+	assert.Equal(t, blocks[1].Header().ID(), tracker.pos)
+
+	assert.Panics(t, func() { trackPosition(&fakeReader{}, thor.Bytes32{}) })
+}
+
+type fakeReader struct{}
+
+func (r *fakeReader) Read() ([]interface{}, bool, error) { return nil, false, nil }
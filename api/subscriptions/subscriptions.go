@@ -10,10 +10,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/bft"
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/log"
@@ -27,8 +29,10 @@ const txQueueSize = 20
 type Subscriptions struct {
 	backtraceLimit uint32
 	repo           *chain.Repository
+	bft            bft.Committer
 	upgrader       *websocket.Upgrader
 	pendingTx      *pendingTx
+	blockFeed      *blockFeed
 	done           chan struct{}
 	wg             sync.WaitGroup
 }
@@ -48,10 +52,11 @@ const (
 	pingPeriod = (pongWait * 7) / 10
 )
 
-func New(repo *chain.Repository, allowedOrigins []string, backtraceLimit uint32, txpool *txpool.TxPool) *Subscriptions {
+func New(repo *chain.Repository, allowedOrigins []string, backtraceLimit uint32, txpool *txpool.TxPool, bft bft.Committer) *Subscriptions {
 	sub := &Subscriptions{
 		backtraceLimit: backtraceLimit,
 		repo:           repo,
+		bft:            bft,
 		upgrader: &websocket.Upgrader{
 			EnableCompression: true,
 			CheckOrigin: func(r *http.Request) bool {
@@ -68,31 +73,29 @@ func New(repo *chain.Repository, allowedOrigins []string, backtraceLimit uint32,
 			},
 		},
 		pendingTx: newPendingTx(txpool),
+		blockFeed: newBlockFeed(repo),
 		done:      make(chan struct{}),
 	}
 
-	sub.wg.Add(1)
+	sub.wg.Add(2)
 	go func() {
 		defer sub.wg.Done()
 
 		sub.pendingTx.DispatchLoop(sub.done)
 	}()
+	go func() {
+		defer sub.wg.Done()
+
+		sub.blockFeed.DispatchLoop(sub.done)
+	}()
 	return sub
 }
 
-func (s *Subscriptions) handleBlockReader(_ http.ResponseWriter, req *http.Request) (*blockReader, error) {
-	position, err := s.parsePosition(req.URL.Query().Get("pos"))
-	if err != nil {
-		return nil, err
-	}
+func (s *Subscriptions) handleBlockReader(_ *http.Request, position thor.Bytes32) (*blockReader, error) {
 	return newBlockReader(s.repo, position), nil
 }
 
-func (s *Subscriptions) handleEventReader(w http.ResponseWriter, req *http.Request) (*eventReader, error) {
-	position, err := s.parsePosition(req.URL.Query().Get("pos"))
-	if err != nil {
-		return nil, err
-	}
+func (s *Subscriptions) handleEventReader(req *http.Request, position thor.Bytes32) (*eventReader, error) {
 	address, err := parseAddress(req.URL.Query().Get("addr"))
 	if err != nil {
 		return nil, utils.BadRequest(errors.WithMessage(err, "addr"))
@@ -128,11 +131,7 @@ func (s *Subscriptions) handleEventReader(w http.ResponseWriter, req *http.Reque
 	return newEventReader(s.repo, position, eventFilter), nil
 }
 
-func (s *Subscriptions) handleTransferReader(_ http.ResponseWriter, req *http.Request) (*transferReader, error) {
-	position, err := s.parsePosition(req.URL.Query().Get("pos"))
-	if err != nil {
-		return nil, err
-	}
+func (s *Subscriptions) handleTransferReader(req *http.Request, position thor.Bytes32) (*transferReader, error) {
 	txOrigin, err := parseAddress(req.URL.Query().Get("txOrigin"))
 	if err != nil {
 		return nil, utils.BadRequest(errors.WithMessage(err, "txOrigin"))
@@ -153,19 +152,11 @@ func (s *Subscriptions) handleTransferReader(_ http.ResponseWriter, req *http.Re
 	return newTransferReader(s.repo, position, transferFilter), nil
 }
 
-func (s *Subscriptions) handleBeatReader(w http.ResponseWriter, req *http.Request) (*beatReader, error) {
-	position, err := s.parsePosition(req.URL.Query().Get("pos"))
-	if err != nil {
-		return nil, err
-	}
+func (s *Subscriptions) handleBeatReader(_ *http.Request, position thor.Bytes32) (*beatReader, error) {
 	return newBeatReader(s.repo, position), nil
 }
 
-func (s *Subscriptions) handleBeat2Reader(w http.ResponseWriter, req *http.Request) (*beat2Reader, error) {
-	position, err := s.parsePosition(req.URL.Query().Get("pos"))
-	if err != nil {
-		return nil, err
-	}
+func (s *Subscriptions) handleBeat2Reader(_ *http.Request, position thor.Bytes32) (*beat2Reader, error) {
 	return newBeat2Reader(s.repo, position), nil
 }
 
@@ -174,34 +165,68 @@ func (s *Subscriptions) handleSubject(w http.ResponseWriter, req *http.Request)
 	defer s.wg.Done()
 
 	var (
-		reader msgReader
-		err    error
+		subject     = mux.Vars(req)["subject"]
+		query       = req.URL.Query()
+		posStr      = query.Get("pos")
+		eventStream = isEventStream(req)
+		reader      msgReader
+		position    thor.Bytes32
+		err         error
 	)
-	switch mux.Vars(req)["subject"] {
+	finalized := query.Get("finalized")
+	if finalized != "" && finalized != "false" && finalized != "true" {
+		return utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "finalized"))
+	}
+	if eventStream {
+		if lastEventID := req.Header.Get("Last-Event-ID"); lastEventID != "" {
+			posStr = lastEventID
+		}
+	}
+	if finalized == "true" {
+		if subject != "block" && subject != "event" && subject != "transfer" {
+			return utils.BadRequest(errors.New("finalized: not supported by subject"))
+		}
+		position, err = s.parseFinalizedPosition(posStr)
+	} else {
+		position, err = s.parsePosition(posStr)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch subject {
 	case "block":
-		if reader, err = s.handleBlockReader(w, req); err != nil {
+		if reader, err = s.handleBlockReader(req, position); err != nil {
 			return err
 		}
 	case "event":
-		if reader, err = s.handleEventReader(w, req); err != nil {
+		if reader, err = s.handleEventReader(req, position); err != nil {
 			return err
 		}
 	case "transfer":
-		if reader, err = s.handleTransferReader(w, req); err != nil {
+		if reader, err = s.handleTransferReader(req, position); err != nil {
 			return err
 		}
 	case "beat":
-		if reader, err = s.handleBeatReader(w, req); err != nil {
+		if reader, err = s.handleBeatReader(req, position); err != nil {
 			return err
 		}
 	case "beat2":
-		if reader, err = s.handleBeat2Reader(w, req); err != nil {
+		if reader, err = s.handleBeat2Reader(req, position); err != nil {
 			return err
 		}
 	default:
 		return utils.HTTPError(errors.New("not found"), http.StatusNotFound)
 	}
 
+	if finalized == "true" {
+		reader = s.newFinalizedReader(reader, position)
+	}
+
+	if eventStream {
+		return s.serveEventStream(w, req, reader, position)
+	}
+
 	conn, closed, err := s.setupConn(w, req)
 	// since the conn is hijacked here, no error should be returned in lines below
 	if err != nil {
@@ -214,7 +239,49 @@ func (s *Subscriptions) handleSubject(w http.ResponseWriter, req *http.Request)
 	return nil
 }
 
+// parsePendingTxFilter parses the filter and the expanded option of pending txs.
+// A nil filter is returned if neither of them is given.
+func parsePendingTxFilter(req *http.Request) (filter *PendingTxFilter, expanded bool, err error) {
+	query := req.URL.Query()
+	if query.Get("origin") == "" && query.Get("to") == "" && query.Get("delegator") == "" &&
+		query.Get("selector") == "" && query.Get("expanded") == "" {
+		return nil, false, nil
+	}
+
+	filter = &PendingTxFilter{}
+	if filter.Origin, err = parseAddress(query.Get("origin")); err != nil {
+		return nil, false, utils.BadRequest(errors.WithMessage(err, "origin"))
+	}
+	if filter.To, err = parseAddress(query.Get("to")); err != nil {
+		return nil, false, utils.BadRequest(errors.WithMessage(err, "to"))
+	}
+	if filter.Delegator, err = parseAddress(query.Get("delegator")); err != nil {
+		return nil, false, utils.BadRequest(errors.WithMessage(err, "delegator"))
+	}
+	if selector := query.Get("selector"); selector != "" {
+		if filter.Selector, err = hexutil.Decode(selector); err != nil {
+			return nil, false, utils.BadRequest(errors.WithMessage(err, "selector"))
+		}
+		if len(filter.Selector) != 4 {
+			return nil, false, utils.BadRequest(errors.WithMessage(errors.New("should be 4 bytes"), "selector"))
+		}
+	}
+	switch query.Get("expanded") {
+	case "", "false":
+	case "true":
+		expanded = true
+	default:
+		return nil, false, utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "expanded"))
+	}
+	return filter, expanded, nil
+}
+
 func (s *Subscriptions) handlePendingTransactions(w http.ResponseWriter, req *http.Request) error {
+	filter, expanded, err := parsePendingTxFilter(req)
+	if err != nil {
+		return err
+	}
+
 	s.wg.Add(1)
 	defer s.wg.Done()
 
@@ -229,6 +296,31 @@ func (s *Subscriptions) handlePendingTransactions(w http.ResponseWriter, req *ht
 	pingTicker := time.NewTicker(pingPeriod)
 	defer pingTicker.Stop()
 
+	// pipes status changes of matched txs, if filtered or expanded
+	if filter != nil {
+		evCh := make(chan *txpool.TxEvent, txQueueSize)
+		s.pendingTx.SubscribeEvent(evCh)
+		defer s.pendingTx.UnsubscribeEvent(evCh)
+
+		for {
+			select {
+			case ev := <-evCh:
+				if !filter.Match(ev.Tx) {
+					continue
+				}
+				if err = conn.WriteJSON(convertPendingTx(ev, expanded)); err != nil {
+					return nil
+				}
+			case <-s.done:
+				return nil
+			case <-closed:
+				return nil
+			case <-pingTicker.C:
+				conn.WriteMessage(websocket.PingMessage, nil)
+			}
+		}
+	}
+
 	txCh := make(chan *tx.Transaction, txQueueSize)
 	s.pendingTx.Subscribe(txCh)
 	defer func() {
@@ -385,6 +477,14 @@ func (s *Subscriptions) Mount(root *mux.Router, pathPrefix string) {
 		Methods(http.MethodGet).
 		Name("subscriptions_pending_tx").
 		HandlerFunc(utils.WrapHandlerFunc(s.handlePendingTransactions))
+	sub.Path("/tx/{id}").
+		Methods(http.MethodGet).
+		Name("subscriptions_tx_status").
+		HandlerFunc(utils.WrapHandlerFunc(s.handleTxStatus))
+	sub.Path("/ws").
+		Methods(http.MethodGet).
+		Name("subscriptions_multiplexed").
+		HandlerFunc(utils.WrapHandlerFunc(s.handleMultiplexed))
 	sub.Path("/{subject:beat|beat2|block|event|transfer}").
 		Methods(http.MethodGet).
 		Name("subscriptions_subject").
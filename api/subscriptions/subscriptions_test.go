@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/cmd/thor/solo"
 	"github.com/vechain/thor/v2/genesis"
 	"github.com/vechain/thor/v2/muxdb"
 	"github.com/vechain/thor/v2/packer"
@@ -222,7 +223,7 @@ func initSubscriptionsServer(t *testing.T) {
 	txPool = pool
 	blocks = generatedBlocks
 	router := mux.NewRouter()
-	sub = New(repo, []string{}, 5, txPool)
+	sub = New(repo, []string{}, 5, txPool, solo.NewBFTEngine(repo))
 	sub.Mount(router, "/subscriptions")
 	ts = httptest.NewServer(router)
 }
@@ -233,7 +234,7 @@ func TestSubscriptionsBacktrace(t *testing.T) {
 	txPool = pool
 	blocks = generatedBlocks
 	router := mux.NewRouter()
-	sub = New(repo, []string{}, 5, txPool)
+	sub = New(repo, []string{}, 5, txPool, solo.NewBFTEngine(repo))
 	sub.Mount(router, "/subscriptions")
 	ts = httptest.NewServer(router)
 	defer ts.Close()
@@ -0,0 +1,286 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/bft"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+// unknownTxBlocks is the number of blocks to wait for an unknown tx to show up in the pool or the chain.
+const unknownTxBlocks = 30
+
+// txStatusTracker tracks the status of a tx by the pool events and the block stream.
+type txStatusTracker struct {
+	repo      *chain.Repository
+	bft       bft.Committer
+	id        thor.Bytes32
+	tx        *tx.Transaction // nil if the tx is not seen yet
+	inclusion *block.Header   // the block including the tx, nil if not included
+	unseen    int             // number of blocks received before the tx is seen
+	done      bool            // the tx is finalized, expired, dropped or unknown
+}
+
+func newTxStatusTracker(repo *chain.Repository, bft bft.Committer, id thor.Bytes32) *txStatusTracker {
+	return &txStatusTracker{repo: repo, bft: bft, id: id}
+}
+
+// Init returns the current status of the tx, looked up from the best chain and the pool.
+func (t *txStatusTracker) Init(txPool *txpool.TxPool) ([]interface{}, error) {
+	meta, err := t.repo.NewBestChain().GetTransactionMeta(t.id)
+	if err != nil {
+		if !t.repo.IsNotFound(err) {
+			return nil, err
+		}
+		if info := txPool.GetInfo(t.id); info != nil {
+			t.tx = info.Tx
+			status := TxStatusPooled
+			if info.Executable {
+				status = TxStatusExecutable
+			}
+			return []interface{}{&TxStatusMessage{ID: t.id, Status: status}}, nil
+		}
+		return nil, nil
+	}
+
+	blk, err := t.repo.GetBlock(meta.BlockID)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := t.include(blk.Header(), blk.Transactions()[meta.Index], int(meta.Index))
+	if err != nil {
+		return nil, err
+	}
+	msgs := []interface{}{msg}
+	if msg, err := t.checkFinalized(); err != nil {
+		return nil, err
+	} else if msg != nil {
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// OnTxEvent handles the pool event of the tx.
+func (t *txStatusTracker) OnTxEvent(ev *txpool.TxEvent) (*TxStatusMessage, error) {
+	// the pool status is not interested once the tx is included
+	if ev.Tx.ID() != t.id || t.inclusion != nil {
+		return nil, nil
+	}
+	t.tx = ev.Tx
+
+	// washed out since included, which is notified by the block stream
+	if ev.Included {
+		return nil, nil
+	}
+	if !ev.Evicted {
+		if ev.Executable != nil && *ev.Executable {
+			return &TxStatusMessage{ID: t.id, Status: TxStatusExecutable}, nil
+		}
+		return &TxStatusMessage{ID: t.id, Status: TxStatusPooled}, nil
+	}
+
+	best := t.repo.BestBlockSummary().Header
+	// the best chain may include the tx after it's evicted
+	if has, err := t.repo.NewChain(best.ID()).HasTransaction(t.id, t.tx.BlockRef().Number()); err != nil {
+		return nil, err
+	} else if has {
+		return nil, nil
+	}
+
+	t.done = true
+	if t.tx.IsExpired(best.Number()) {
+		return &TxStatusMessage{ID: t.id, Status: TxStatusExpired}, nil
+	}
+	return &TxStatusMessage{ID: t.id, Status: TxStatusDropped, Reason: ev.Reason}, nil
+}
+
+// OnBlocks handles new blocks of the block stream.
+func (t *txStatusTracker) OnBlocks(blocks []*chain.ExtendedBlock) ([]interface{}, error) {
+	var msgs []interface{}
+	for _, b := range blocks {
+		header := b.Header()
+		if b.Obsolete {
+			if t.inclusion != nil && t.inclusion.ID() == header.ID() {
+				t.inclusion = nil
+				msgs = append(msgs, &TxStatusMessage{ID: t.id, Status: TxStatusReorged, Meta: convertTxStatusMeta(header)})
+			}
+			continue
+		}
+
+		if t.inclusion == nil {
+			for i, trx := range b.Transactions() {
+				if trx.ID() == t.id {
+					msg, err := t.include(header, trx, i)
+					if err != nil {
+						return nil, err
+					}
+					msgs = append(msgs, msg)
+					break
+				}
+			}
+		}
+
+		if t.inclusion == nil && t.tx != nil && t.tx.IsExpired(header.Number()) {
+			t.done = true
+			return append(msgs, &TxStatusMessage{ID: t.id, Status: TxStatusExpired}), nil
+		}
+
+		// the expiration of a tx never seen is unknown
+		if t.tx == nil {
+			t.unseen++
+			if t.unseen >= unknownTxBlocks {
+				t.done = true
+				return append(msgs, &TxStatusMessage{ID: t.id, Status: TxStatusUnknown}), nil
+			}
+		}
+	}
+
+	if msg, err := t.checkFinalized(); err != nil {
+		return nil, err
+	} else if msg != nil {
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func (t *txStatusTracker) include(header *block.Header, trx *tx.Transaction, index int) (*TxStatusMessage, error) {
+	receipts, err := t.repo.GetBlockReceipts(header.ID())
+	if err != nil {
+		return nil, err
+	}
+	t.tx = trx
+	t.inclusion = header
+	return &TxStatusMessage{
+		ID:      t.id,
+		Status:  TxStatusIncluded,
+		Meta:    convertTxStatusMeta(header),
+		Receipt: convertTxStatusReceipt(receipts[index]),
+	}, nil
+}
+
+// checkFinalized returns the finalized message if the block including the tx is finalized.
+func (t *txStatusTracker) checkFinalized() (*TxStatusMessage, error) {
+	if t.inclusion == nil {
+		return nil, nil
+	}
+	finalized := t.bft.Finalized()
+	if block.Number(finalized) < t.inclusion.Number() {
+		return nil, nil
+	}
+	id, err := t.repo.NewChain(finalized).GetBlockID(t.inclusion.Number())
+	if err != nil {
+		return nil, err
+	}
+	if id != t.inclusion.ID() {
+		return nil, nil
+	}
+	t.done = true
+	return &TxStatusMessage{ID: t.id, Status: TxStatusFinalized, Meta: convertTxStatusMeta(t.inclusion)}, nil
+}
+
+func (s *Subscriptions) handleTxStatus(w http.ResponseWriter, req *http.Request) error {
+	id, err := thor.ParseBytes32(mux.Vars(req)["id"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "id"))
+	}
+
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	conn, closed, err := s.setupConn(w, req)
+	// since the conn is hijacked here, no error should be returned in lines below
+	if err != nil {
+		logger.Debug("upgrade to websocket", "err", err)
+		return nil
+	}
+
+	// subscribe before looking up the current status, so that no change is missed
+	evCh := make(chan *txpool.TxEvent, txQueueSize)
+	s.pendingTx.SubscribeEvent(evCh)
+	defer s.pendingTx.UnsubscribeEvent(evCh)
+
+	blockCh := make(chan []*chain.ExtendedBlock, blockQueueSize)
+	s.blockFeed.Subscribe(blockCh)
+	defer s.blockFeed.Unsubscribe(blockCh)
+
+	tracker := newTxStatusTracker(s.repo, s.bft, id)
+	err = s.pipeTxStatus(conn, tracker, evCh, blockCh, closed)
+	s.closeConn(conn, err)
+	return nil
+}
+
+func (s *Subscriptions) pipeTxStatus(
+	conn *websocket.Conn,
+	tracker *txStatusTracker,
+	evCh <-chan *txpool.TxEvent,
+	blockCh <-chan []*chain.ExtendedBlock,
+	closed <-chan struct{},
+) error {
+	write := func(msgs ...interface{}) error {
+		for _, msg := range msgs {
+			if err := conn.WriteJSON(msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	msgs, err := tracker.Init(s.pendingTx.txPool)
+	if err != nil {
+		return err
+	}
+	if err := write(msgs...); err != nil {
+		return err
+	}
+
+	pingTicker := time.NewTicker(pingPeriod)
+	defer pingTicker.Stop()
+
+	for !tracker.done {
+		select {
+		case ev := <-evCh:
+			msg, err := tracker.OnTxEvent(ev)
+			if err != nil {
+				return err
+			}
+			if msg != nil {
+				if err := write(msg); err != nil {
+					return err
+				}
+			}
+		case blocks, ok := <-blockCh:
+			if !ok {
+				// closed by the feed
+				return errors.New("subscriber lagged behind")
+			}
+			msgs, err := tracker.OnBlocks(blocks)
+			if err != nil {
+				return err
+			}
+			if err := write(msgs...); err != nil {
+				return err
+			}
+		case <-s.done:
+			return nil
+		case <-closed:
+			return nil
+		case <-pingTicker.C:
+			conn.WriteMessage(websocket.PingMessage, nil)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+func TestTxStatusTracker(t *testing.T) {
+	repo, blocks, txPool := initChain(t)
+	included := blocks[1].Transactions()[0]
+
+	t.Run("includedAndFinalized", func(t *testing.T) {
+		committer := &mockCommitter{finalized: blocks[0].Header().ID()}
+		tracker := newTxStatusTracker(repo, committer, included.ID())
+
+		msgs, err := tracker.Init(txPool)
+		assert.NoError(t, err)
+		assert.Len(t, msgs, 1)
+		msg := msgs[0].(*TxStatusMessage)
+		assert.Equal(t, TxStatusIncluded, msg.Status)
+		assert.Equal(t, blocks[1].Header().ID(), msg.Meta.BlockID)
+		assert.NotNil(t, msg.Receipt)
+		assert.False(t, tracker.done)
+
+		// washed out since included
+		ev, err := tracker.OnTxEvent(&txpool.TxEvent{Tx: included, Included: true})
+		assert.NoError(t, err)
+		assert.Nil(t, ev)
+
+		committer.finalized = blocks[1].Header().ID()
+		msgs, err = tracker.OnBlocks(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{&TxStatusMessage{ID: included.ID(), Status: TxStatusFinalized, Meta: convertTxStatusMeta(blocks[1].Header())}}, msgs)
+		assert.True(t, tracker.done)
+	})
+
+	t.Run("reorged", func(t *testing.T) {
+		tracker := newTxStatusTracker(repo, &mockCommitter{finalized: blocks[0].Header().ID()}, included.ID())
+
+		msgs, err := tracker.OnBlocks([]*chain.ExtendedBlock{{Block: blocks[1]}})
+		assert.NoError(t, err)
+		assert.Len(t, msgs, 1)
+		assert.Equal(t, TxStatusIncluded, msgs[0].(*TxStatusMessage).Status)
+
+		msgs, err = tracker.OnBlocks([]*chain.ExtendedBlock{{Block: blocks[1], Obsolete: true}})
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{&TxStatusMessage{ID: included.ID(), Status: TxStatusReorged, Meta: convertTxStatusMeta(blocks[1].Header())}}, msgs)
+		assert.False(t, tracker.done)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		trx := createTx(t, repo, 1)
+		tracker := newTxStatusTracker(repo, &mockCommitter{}, trx.ID())
+
+		// other txs are ignored
+		msg, err := tracker.OnTxEvent(&txpool.TxEvent{Tx: included})
+		assert.NoError(t, err)
+		assert.Nil(t, msg)
+
+		msg, err = tracker.OnTxEvent(&txpool.TxEvent{Tx: trx})
+		assert.NoError(t, err)
+		assert.Equal(t, &TxStatusMessage{ID: trx.ID(), Status: TxStatusPooled}, msg)
+
+		executable := true
+		msg, err = tracker.OnTxEvent(&txpool.TxEvent{Tx: trx, Executable: &executable})
+		assert.NoError(t, err)
+		assert.Equal(t, &TxStatusMessage{ID: trx.ID(), Status: TxStatusExecutable}, msg)
+
+		// the block after expiration
+		var parentID thor.Bytes32
+		binary.BigEndian.PutUint32(parentID[:], trx.BlockRef().Number()+trx.Expiration())
+		blk := new(block.Builder).ParentID(parentID).Build()
+
+		msgs, err := tracker.OnBlocks([]*chain.ExtendedBlock{{Block: blk}})
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{&TxStatusMessage{ID: trx.ID(), Status: TxStatusExpired}}, msgs)
+		assert.True(t, tracker.done)
+	})
+
+	t.Run("dropped", func(t *testing.T) {
+		trx := createTx(t, repo, 2)
+		tracker := newTxStatusTracker(repo, &mockCommitter{}, trx.ID())
+
+		msgs, err := tracker.Init(txPool)
+		assert.NoError(t, err)
+		assert.Empty(t, msgs)
+
+		msg, err := tracker.OnTxEvent(&txpool.TxEvent{Tx: trx, Evicted: true, Reason: "pool is full"})
+		assert.NoError(t, err)
+		assert.Equal(t, &TxStatusMessage{ID: trx.ID(), Status: TxStatusDropped, Reason: "pool is full"}, msg)
+		assert.True(t, tracker.done)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		trx := createTx(t, repo, 3)
+		tracker := newTxStatusTracker(repo, &mockCommitter{}, trx.ID())
+
+		msgs, err := tracker.Init(txPool)
+		assert.NoError(t, err)
+		assert.Empty(t, msgs)
+
+		blk := new(block.Builder).ParentID(blocks[1].Header().ID()).Build()
+		for i := 1; i < unknownTxBlocks; i++ {
+			msgs, err = tracker.OnBlocks([]*chain.ExtendedBlock{{Block: blk}})
+			assert.NoError(t, err)
+			assert.Empty(t, msgs)
+		}
+		assert.False(t, tracker.done)
+
+		msgs, err = tracker.OnBlocks([]*chain.ExtendedBlock{{Block: blk}})
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{&TxStatusMessage{ID: trx.ID(), Status: TxStatusUnknown}}, msgs)
+		assert.True(t, tracker.done)
+	})
+}
+
+func TestTxStatusSubscription(t *testing.T) {
+	repo, blocks, txPool := initChain(t)
+	router := mux.NewRouter()
+	sub := New(repo, []string{}, 5, txPool, &mockCommitter{finalized: blocks[1].Header().ID()})
+	defer sub.Close()
+	sub.Mount(router, "/subscriptions")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/subscriptions/tx/0xbad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	included := blocks[1].Transactions()[0]
+	u := url.URL{Scheme: "ws", Host: strings.TrimPrefix(ts.URL, "http://"), Path: "/subscriptions/tx/" + included.ID().String()}
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	for _, status := range []string{TxStatusIncluded, TxStatusFinalized} {
+		var msg TxStatusMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, included.ID(), msg.ID)
+		assert.Equal(t, status, msg.Status)
+	}
+
+	// closed once finalized
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway))
+}
@@ -6,12 +6,16 @@
 package subscriptions
 
 import (
+	"bytes"
+	"encoding/json"
+
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thor/v2/txpool"
 )
 
 // BlockMessage block piped by websocket
@@ -140,12 +144,12 @@ func convertEvent(header *block.Header, tx *tx.Transaction, clauseIndex uint32,
 
 // EventFilter contains options for contract event filtering.
 type EventFilter struct {
-	Address *thor.Address // restricts matches to events created by specific contracts
-	Topic0  *thor.Bytes32
-	Topic1  *thor.Bytes32
-	Topic2  *thor.Bytes32
-	Topic3  *thor.Bytes32
-	Topic4  *thor.Bytes32
+	Address *thor.Address `json:"address"` // restricts matches to events created by specific contracts
+	Topic0  *thor.Bytes32 `json:"topic0"`
+	Topic1  *thor.Bytes32 `json:"topic1"`
+	Topic2  *thor.Bytes32 `json:"topic2"`
+	Topic3  *thor.Bytes32 `json:"topic3"`
+	Topic4  *thor.Bytes32 `json:"topic4"`
 }
 
 // Match returs whether event matches filter
@@ -176,9 +180,9 @@ func (ef *EventFilter) Match(event *tx.Event) bool {
 
 // TransferFilter contains options for contract transfer filtering.
 type TransferFilter struct {
-	TxOrigin  *thor.Address // who send transaction
-	Sender    *thor.Address // who transferred tokens
-	Recipient *thor.Address // who received tokens
+	TxOrigin  *thor.Address `json:"txOrigin"`  // who send transaction
+	Sender    *thor.Address `json:"sender"`    // who transferred tokens
+	Recipient *thor.Address `json:"recipient"` // who received tokens
 }
 
 // Match returs whether transfer matches filter
@@ -220,6 +224,212 @@ type Beat2Message struct {
 	Obsolete    bool         `json:"obsolete"`
 }
 
+// FinalizedMessage marks that all msgs up to the finalized block are sent, in finalized mode.
+type FinalizedMessage struct {
+	Finalized FinalizedBlock `json:"finalized"`
+}
+
+type FinalizedBlock struct {
+	ID     thor.Bytes32 `json:"id"`
+	Number uint32       `json:"number"`
+}
+
 type PendingTxIDMessage struct {
 	ID thor.Bytes32 `json:"id"`
 }
+
+// statuses of PendingTxMessage
+const (
+	PendingTxStatusPending    = "pending"
+	PendingTxStatusExecutable = "executable"
+	PendingTxStatusEvicted    = "evicted"
+	PendingTxStatusIncluded   = "included"
+)
+
+// PendingTxMessage status change of a pooled tx, piped by websocket.
+type PendingTxMessage struct {
+	ID     thor.Bytes32 `json:"id"`
+	Status string       `json:"status"`
+	Reason string       `json:"reason,omitempty"` // why the tx is evicted
+	Tx     *Transaction `json:"tx,omitempty"`     // only present if expanded
+}
+
+// Clause for json marshal
+type Clause struct {
+	To    *thor.Address        `json:"to"`
+	Value math.HexOrDecimal256 `json:"value"`
+	Data  string               `json:"data"`
+}
+
+// Transaction decoded body of a pooled tx.
+type Transaction struct {
+	ID           thor.Bytes32        `json:"id"`
+	ChainTag     byte                `json:"chainTag"`
+	BlockRef     string              `json:"blockRef"`
+	Expiration   uint32              `json:"expiration"`
+	Clauses      []Clause            `json:"clauses"`
+	GasPriceCoef uint8               `json:"gasPriceCoef"`
+	Gas          uint64              `json:"gas"`
+	Origin       thor.Address        `json:"origin"`
+	Delegator    *thor.Address       `json:"delegator"`
+	Nonce        math.HexOrDecimal64 `json:"nonce"`
+	DependsOn    *thor.Bytes32       `json:"dependsOn"`
+	Size         uint32              `json:"size"`
+}
+
+func convertPendingTx(ev *txpool.TxEvent, expanded bool) *PendingTxMessage {
+	msg := &PendingTxMessage{
+		ID:     ev.Tx.ID(),
+		Status: PendingTxStatusPending,
+	}
+	if ev.Included {
+		msg.Status = PendingTxStatusIncluded
+	} else if ev.Evicted {
+		msg.Status = PendingTxStatusEvicted
+		msg.Reason = ev.Reason
+	} else if ev.Executable != nil && *ev.Executable {
+		msg.Status = PendingTxStatusExecutable
+	}
+	if !expanded {
+		return msg
+	}
+
+	origin, _ := ev.Tx.Origin()
+	delegator, _ := ev.Tx.Delegator()
+	clauses := make([]Clause, 0, len(ev.Tx.Clauses()))
+	for _, c := range ev.Tx.Clauses() {
+		clauses = append(clauses, Clause{
+			To:    c.To(),
+			Value: math.HexOrDecimal256(*c.Value()),
+			Data:  hexutil.Encode(c.Data()),
+		})
+	}
+	br := ev.Tx.BlockRef()
+	msg.Tx = &Transaction{
+		ID:           ev.Tx.ID(),
+		ChainTag:     ev.Tx.ChainTag(),
+		BlockRef:     hexutil.Encode(br[:]),
+		Expiration:   ev.Tx.Expiration(),
+		Clauses:      clauses,
+		GasPriceCoef: ev.Tx.GasPriceCoef(),
+		Gas:          ev.Tx.Gas(),
+		Origin:       origin,
+		Delegator:    delegator,
+		Nonce:        math.HexOrDecimal64(ev.Tx.Nonce()),
+		DependsOn:    ev.Tx.DependsOn(),
+		Size:         uint32(ev.Tx.Size()),
+	}
+	return msg
+}
+
+// PendingTxFilter contains options for pending tx filtering.
+type PendingTxFilter struct {
+	Origin    *thor.Address `json:"origin"`
+	Delegator *thor.Address `json:"delegator"`
+	To        *thor.Address `json:"to"`       // matches if any clause is sent to the address
+	Selector  hexutil.Bytes `json:"selector"` // matches if any clause data starts with the function selector
+}
+
+// Match returns whether tx matches filter. To and Selector must be matched by the same clause.
+func (pf *PendingTxFilter) Match(trx *tx.Transaction) bool {
+	if pf.Origin != nil {
+		if origin, err := trx.Origin(); err != nil || origin != *pf.Origin {
+			return false
+		}
+	}
+	if pf.Delegator != nil {
+		if delegator, err := trx.Delegator(); err != nil || delegator == nil || *delegator != *pf.Delegator {
+			return false
+		}
+	}
+	if pf.To == nil && len(pf.Selector) == 0 {
+		return true
+	}
+	for _, c := range trx.Clauses() {
+		if pf.To != nil && (c.To() == nil || *c.To() != *pf.To) {
+			continue
+		}
+		if len(pf.Selector) > 0 && !bytes.HasPrefix(c.Data(), pf.Selector) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// statuses of TxStatusMessage
+const (
+	TxStatusPooled     = "pooled"
+	TxStatusExecutable = "executable"
+	TxStatusIncluded   = "included"
+	TxStatusReorged    = "reorged"
+	TxStatusFinalized  = "finalized"
+	TxStatusExpired    = "expired"
+	TxStatusDropped    = "dropped"
+	TxStatusUnknown    = "unknown"
+)
+
+// TxStatusMessage status change of the subscribed tx.
+type TxStatusMessage struct {
+	ID      thor.Bytes32     `json:"id"`
+	Status  string           `json:"status"`
+	Reason  string           `json:"reason,omitempty"`  // why the tx is dropped
+	Meta    *TxStatusMeta    `json:"meta,omitempty"`    // the block including the tx, if included, reorged or finalized
+	Receipt *TxStatusReceipt `json:"receipt,omitempty"` // only present if included
+}
+
+// TxStatusMeta the block including the tx.
+type TxStatusMeta struct {
+	BlockID        thor.Bytes32 `json:"blockID"`
+	BlockNumber    uint32       `json:"blockNumber"`
+	BlockTimestamp uint64       `json:"blockTimestamp"`
+}
+
+// TxStatusReceipt receipt of the included tx, outputs are omitted.
+type TxStatusReceipt struct {
+	GasUsed  uint64                `json:"gasUsed"`
+	GasPayer thor.Address          `json:"gasPayer"`
+	Paid     *math.HexOrDecimal256 `json:"paid"`
+	Reward   *math.HexOrDecimal256 `json:"reward"`
+	Reverted bool                  `json:"reverted"`
+}
+
+func convertTxStatusMeta(header *block.Header) *TxStatusMeta {
+	return &TxStatusMeta{
+		BlockID:        header.ID(),
+		BlockNumber:    header.Number(),
+		BlockTimestamp: header.Timestamp(),
+	}
+}
+
+func convertTxStatusReceipt(receipt *tx.Receipt) *TxStatusReceipt {
+	return &TxStatusReceipt{
+		GasUsed:  receipt.GasUsed,
+		GasPayer: receipt.GasPayer,
+		Paid:     (*math.HexOrDecimal256)(receipt.Paid),
+		Reward:   (*math.HexOrDecimal256)(receipt.Reward),
+		Reverted: receipt.Reverted,
+	}
+}
+
+// MuxRequest request sent by client over the multiplexed websocket.
+type MuxRequest struct {
+	ID           uint64          `json:"id"`           // echoed in the response
+	Method       string          `json:"method"`       // subscribe or unsubscribe
+	Subject      string          `json:"subject"`      // subject to subscribe
+	Filter       json.RawMessage `json:"filter"`       // optional filter of event or transfer subject
+	Subscription string          `json:"subscription"` // subscription to unsubscribe
+}
+
+// MuxResponse response of MuxRequest.
+type MuxResponse struct {
+	ID           uint64 `json:"id"`
+	Subscription string `json:"subscription,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// MuxMessage message of a subscription piped by the multiplexed websocket.
+type MuxMessage struct {
+	Subscription string      `json:"subscription"`
+	Data         interface{} `json:"data"`
+}
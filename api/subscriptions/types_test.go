@@ -21,6 +21,7 @@ import (
 	"github.com/vechain/thor/v2/state"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thor/v2/txpool"
 )
 
 func TestConvertBlockWithBadSignature(t *testing.T) {
@@ -334,3 +335,63 @@ func TestTransferFilter_Match(t *testing.T) {
 	}
 	assert.False(t, filter.Match(transfer, origin))
 }
+
+func TestPendingTxFilter_Match(t *testing.T) {
+	to := thor.BytesToAddress([]byte("to"))
+	other := thor.BytesToAddress([]byte("other"))
+	selector := []byte{0xa9, 0x05, 0x9c, 0xbb}
+
+	trx := new(tx.Builder).
+		Clause(tx.NewClause(&other)).
+		Clause(tx.NewClause(&to).WithData(append(selector, 0x1))).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trx = trx.WithSignature(sig)
+	origin := genesis.DevAccounts()[0].Address
+
+	tests := map[string]struct {
+		filter *PendingTxFilter
+		match  bool
+	}{
+		"empty":               {&PendingTxFilter{}, true},
+		"origin":              {&PendingTxFilter{Origin: &origin}, true},
+		"otherOrigin":         {&PendingTxFilter{Origin: &other}, false},
+		"delegator":           {&PendingTxFilter{Delegator: &origin}, false},
+		"to":                  {&PendingTxFilter{To: &other}, true},
+		"selector":            {&PendingTxFilter{Selector: selector}, true},
+		"toAndSelector":       {&PendingTxFilter{To: &to, Selector: selector}, true},
+		"selectorOfAnotherTo": {&PendingTxFilter{To: &other, Selector: selector}, false},
+		"otherSelector":       {&PendingTxFilter{Selector: []byte{1, 2, 3, 4}}, false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.match, tt.filter.Match(trx))
+		})
+	}
+}
+
+func TestConvertPendingTx(t *testing.T) {
+	repo, _, _ := initChain(t)
+	trx := createTx(t, repo, 0)
+	executable := true
+
+	msg := convertPendingTx(&txpool.TxEvent{Tx: trx}, false)
+	assert.Equal(t, &PendingTxMessage{ID: trx.ID(), Status: PendingTxStatusPending}, msg)
+
+	msg = convertPendingTx(&txpool.TxEvent{Tx: trx, Executable: &executable}, false)
+	assert.Equal(t, PendingTxStatusExecutable, msg.Status)
+
+	msg = convertPendingTx(&txpool.TxEvent{Tx: trx, Evicted: true, Reason: "expired"}, true)
+	assert.Equal(t, PendingTxStatusEvicted, msg.Status)
+	assert.Equal(t, "expired", msg.Reason)
+	assert.Equal(t, trx.ID(), msg.Tx.ID)
+	assert.Equal(t, genesis.DevAccounts()[0].Address, msg.Tx.Origin)
+	assert.Len(t, msg.Tx.Clauses, 1)
+	assert.Equal(t, trx.Clauses()[0].To(), msg.Tx.Clauses[0].To)
+
+	msg = convertPendingTx(&txpool.TxEvent{Tx: trx, Included: true}, false)
+	assert.Equal(t, &PendingTxMessage{ID: trx.ID(), Status: PendingTxStatusIncluded}, msg)
+}
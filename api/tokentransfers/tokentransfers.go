@@ -0,0 +1,86 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tokentransfers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/api/events"
+	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/logdb"
+)
+
+type TokenTransfers struct {
+	repo  *chain.Repository
+	db    logdb.LogStore
+	limit uint64
+}
+
+func New(repo *chain.Repository, db logdb.LogStore, logsLimit uint64) *TokenTransfers {
+	return &TokenTransfers{
+		repo,
+		db,
+		logsLimit,
+	}
+}
+
+// filter queries token transfers with option, the cursor pointing to the last transfer is also returned.
+func (t *TokenTransfers) filter(ctx context.Context, header http.Header, filter *TokenTransferFilter) ([]*FilteredTokenTransfer, *logdb.Cursor, error) {
+	rng, err := events.ConvertRange(t.repo.NewBestChain(), filter.Range)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tf := &logdb.TokenTransferFilter{
+		CriteriaSet: filter.CriteriaSet,
+		Range:       rng,
+		Options:     filter.Options,
+		Order:       filter.Order,
+	}
+	if status := t.db.PruneStatus(); status.TokenTransfersPruned(tf) {
+		if tf.Range, err = events.ClampPruned(header, status, tf.Range); err != nil {
+			return nil, nil, err
+		}
+	}
+	transfers, err := t.db.FilterTokenTransfers(ctx, tf)
+	if err != nil {
+		return nil, nil, err
+	}
+	results := make([]*FilteredTokenTransfer, len(transfers))
+	for i, transfer := range transfers {
+		results[i] = convertTokenTransfer(transfer)
+	}
+	var cursor *logdb.Cursor
+	if len(transfers) > 0 {
+		last := transfers[len(transfers)-1]
+		cursor = logdb.NewCursor(last.BlockNumber, last.Index)
+	}
+	return results, cursor, nil
+}
+
+func (t *TokenTransfers) handleFilterTokenTransfers(w http.ResponseWriter, req *http.Request) error {
+	var filter TokenTransferFilter
+	if err := utils.ParseJSON(req.Body, &filter); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	return events.WriteFiltered(w, filter.Options, t.limit, func(options *logdb.Options) ([]*FilteredTokenTransfer, *logdb.Cursor, error) {
+		filter.Options = options
+		return t.filter(req.Context(), w.Header(), &filter)
+	})
+}
+
+func (t *TokenTransfers) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("").
+		Methods(http.MethodPost).
+		Name("logs_filter_token_transfer").
+		HandlerFunc(utils.WrapHandlerFunc(t.handleFilterTokenTransfers))
+}
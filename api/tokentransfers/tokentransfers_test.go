@@ -0,0 +1,168 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tokentransfers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/v2/api/events"
+	"github.com/vechain/thor/v2/api/tokentransfers"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/builtin"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/test/logsapi"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+const defaultLogLimit uint64 = 1000
+
+var (
+	token = thor.BytesToAddress([]byte("token"))
+	topic = thor.MustParseBytes32("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	alice = thor.BytesToAddress([]byte("alice"))
+	bob   = thor.BytesToAddress([]byte("bob"))
+)
+
+func TestTokenTransfers(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ts := newServer(t, db)
+
+	res, err := http.Post(ts.URL+"/token-transfers", "application/x-www-form-urlencoded", bytes.NewReader([]byte{0x00, 0x01, 0x02}))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	// alice sends VTHO to bob, and bob sends the token to alice in blocks #1..#3
+	insertBlocks(t, db, 3)
+
+	var all []*tokentransfers.FilteredTokenTransfer
+	body, statusCode := logsapi.Post(t, ts.URL+"/token-transfers", tokentransfers.TokenTransferFilter{})
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(body, &all); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, all, 6)
+	for i, tr := range all {
+		assert.Equal(t, uint32(i/2+1), tr.Meta.BlockNumber)
+		if i%2 == 0 {
+			assert.Equal(t, builtin.Energy.Address, tr.Token)
+			assert.Equal(t, alice, tr.Sender)
+			assert.Equal(t, bob, tr.Recipient)
+			assert.Equal(t, (*math.HexOrDecimal256)(big.NewInt(100)), tr.Amount)
+		} else {
+			assert.Equal(t, token, tr.Token)
+			assert.Equal(t, bob, tr.Sender)
+			assert.Equal(t, alice, tr.Recipient)
+			assert.Equal(t, (*math.HexOrDecimal256)(big.NewInt(200)), tr.Amount)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter tokentransfers.TokenTransferFilter
+		want   []*tokentransfers.FilteredTokenTransfer
+	}{
+		{"by token", tokentransfers.TokenTransferFilter{CriteriaSet: []*logdb.TokenTransferCriteria{{Token: &builtin.Energy.Address}}}, []*tokentransfers.FilteredTokenTransfer{all[0], all[2], all[4]}},
+		{"by recipient and token", tokentransfers.TokenTransferFilter{CriteriaSet: []*logdb.TokenTransferCriteria{{Recipient: &alice, Token: &token}}, Range: &events.Range{Unit: events.BlockRangeType, From: 2, To: 3}}, []*tokentransfers.FilteredTokenTransfer{all[3], all[5]}},
+		{"by sender of other token", tokentransfers.TokenTransferFilter{CriteriaSet: []*logdb.TokenTransferCriteria{{Sender: &alice, Token: &token}}}, []*tokentransfers.FilteredTokenTransfer{}},
+		{"desc", tokentransfers.TokenTransferFilter{Order: logdb.DESC, Options: &logdb.Options{Limit: 1}}, all[5:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, statusCode := logsapi.Post(t, ts.URL+"/token-transfers", tt.filter)
+			assert.Equal(t, http.StatusOK, statusCode)
+			var got []*tokentransfers.FilteredTokenTransfer
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestPruned checks that transfers of kept tokens are not clamped to the blocks not pruned.
+func TestPruned(t *testing.T) {
+	// a file db, not to prune the shared in-memory one
+	db, err := logdb.New(filepath.Join(t.TempDir(), "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ts := newServer(t, db)
+	insertBlocks(t, db, 3)
+
+	if err := db.Prune(context.Background(), 3, []thor.Address{token}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*tokentransfers.FilteredTokenTransfer
+	body, statusCode := logsapi.Post(t, ts.URL+"/token-transfers", tokentransfers.TokenTransferFilter{})
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, got, 2)
+
+	body, statusCode = logsapi.Post(t, ts.URL+"/token-transfers", tokentransfers.TokenTransferFilter{CriteriaSet: []*logdb.TokenTransferCriteria{{Token: &token}}})
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, got, 3)
+}
+
+func transferEvent(token, from, to thor.Address, amount int64) *tx.Event {
+	return &tx.Event{
+		Address: token,
+		Topics:  []thor.Bytes32{topic, thor.BytesToBytes32(from[:]), thor.BytesToBytes32(to[:])},
+		Data:    thor.BytesToBytes32(big.NewInt(amount).Bytes()).Bytes(),
+	}
+}
+
+// insertBlocks writes n blocks, each with VTHO sent from alice to bob and the token from bob to alice.
+func insertBlocks(t *testing.T, db logdb.LogStore, n int) {
+	db.RecordTokenTransfers(true)
+	b := new(block.Builder).Build()
+	w := db.NewWriter()
+	for i := 0; i < n; i++ {
+		builder := new(block.Builder)
+		if i > 0 {
+			builder.ParentID(b.Header().ID())
+		}
+		b = builder.Build()
+		receipt := &tx.Receipt{Outputs: []*tx.Output{{Events: tx.Events{
+			transferEvent(builtin.Energy.Address, alice, bob, 100),
+			transferEvent(token, bob, alice, 200),
+		}}}}
+		if err := w.Write(b, tx.Receipts{receipt}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newServer(t *testing.T, db logdb.LogStore) *httptest.Server {
+	return logsapi.NewServer(t, func(repo *chain.Repository, router *mux.Router) {
+		tokentransfers.New(repo, db, defaultLogLimit).Mount(router, "/token-transfers")
+	})
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tokentransfers
+
+import (
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/v2/api/events"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/thor"
+)
+
+type LogMeta struct {
+	BlockID        thor.Bytes32 `json:"blockID"`
+	BlockNumber    uint32       `json:"blockNumber"`
+	BlockTimestamp uint64       `json:"blockTimestamp"`
+	TxID           thor.Bytes32 `json:"txID"`
+	TxOrigin       thor.Address `json:"txOrigin"`
+	ClauseIndex    uint32       `json:"clauseIndex"`
+}
+
+// FilteredTokenTransfer is a fungible token transfer, e.g. of VTHO.
+type FilteredTokenTransfer struct {
+	Token     thor.Address          `json:"token"`
+	Sender    thor.Address          `json:"sender"`
+	Recipient thor.Address          `json:"recipient"`
+	Amount    *math.HexOrDecimal256 `json:"amount"`
+	Meta      LogMeta               `json:"meta"`
+}
+
+func convertTokenTransfer(transfer *logdb.TokenTransfer) *FilteredTokenTransfer {
+	v := math.HexOrDecimal256(*transfer.Amount)
+	return &FilteredTokenTransfer{
+		Token:     transfer.Token,
+		Sender:    transfer.Sender,
+		Recipient: transfer.Recipient,
+		Amount:    &v,
+		Meta: LogMeta{
+			BlockID:        transfer.BlockID,
+			BlockNumber:    transfer.BlockNumber,
+			BlockTimestamp: transfer.BlockTime,
+			TxID:           transfer.TxID,
+			TxOrigin:       transfer.TxOrigin,
+			ClauseIndex:    transfer.ClauseIndex,
+		},
+	}
+}
+
+type TokenTransferFilter struct {
+	CriteriaSet []*logdb.TokenTransferCriteria
+	Range       *events.Range
+	Options     *logdb.Options
+	Order       logdb.Order //default asc
+}
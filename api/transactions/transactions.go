@@ -15,13 +15,18 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/bft"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/log"
 	"github.com/vechain/thor/v2/schedule"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
 	"github.com/vechain/thor/v2/txpool"
 )
 
+// maxBatchSize max number of txs allowed in one batch submission.
+const maxBatchSize = 100
+
 var (
 	logger       = log.WithContext("pkg", "solo")
 	baseGasPrice = big.NewInt(1e13)
@@ -31,17 +36,19 @@ type Transactions struct {
 	repo     *chain.Repository
 	pool     *txpool.TxPool
 	schedule *schedule.Schedule
+	bft      bft.Committer
 }
 
-func New(repo *chain.Repository, pool *txpool.TxPool, schedule *schedule.Schedule) *Transactions {
+func New(repo *chain.Repository, pool *txpool.TxPool, schedule *schedule.Schedule, bft bft.Committer) *Transactions {
 	return &Transactions{
 		repo,
 		pool,
 		schedule,
+		bft,
 	}
 }
 
-func (t *Transactions) getRawTransaction(txID thor.Bytes32, head thor.Bytes32, allowPending bool) (*rawTransaction, error) {
+func (t *Transactions) getRawTransaction(txID thor.Bytes32, head thor.Bytes32, allowPending bool) (*RawTransaction, error) {
 	chain := t.repo.NewChain(head)
 	tx, meta, err := chain.GetTransaction(txID)
 	if err != nil {
@@ -52,7 +59,7 @@ func (t *Transactions) getRawTransaction(txID thor.Bytes32, head thor.Bytes32, a
 					if err != nil {
 						return nil, err
 					}
-					return &rawTransaction{
+					return &RawTransaction{
 						RawTx: RawTx{hexutil.Encode(raw)},
 					}, nil
 				}
@@ -70,7 +77,7 @@ func (t *Transactions) getRawTransaction(txID thor.Bytes32, head thor.Bytes32, a
 	if err != nil {
 		return nil, err
 	}
-	return &rawTransaction{
+	return &RawTransaction{
 		RawTx: RawTx{hexutil.Encode(raw)},
 		Meta: &TxMeta{
 			BlockID:        summary.Header.ID(),
@@ -144,10 +151,99 @@ func (t *Transactions) handleSendTransaction(w http.ResponseWriter, req *http.Re
 		}
 		return err
 	}
-	return utils.WriteJSON(w, map[string]string{
-		"id": tx.ID().String(),
-	})
+	return utils.WriteJSON(w, &SendTxResult{ID: tx.ID()})
+}
+
+func (t *Transactions) handleSendTransactions(w http.ResponseWriter, req *http.Request) error {
+	atomic := req.URL.Query().Get("atomic")
+	if atomic != "" && atomic != "false" && atomic != "true" {
+		return utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "atomic"))
+	}
+
+	var rawTxs []*RawTx
+	if err := utils.ParseJSON(req.Body, &rawTxs); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if len(rawTxs) == 0 {
+		return utils.BadRequest(errors.New("body: empty batch"))
+	}
+	if len(rawTxs) > maxBatchSize {
+		return utils.BadRequest(fmt.Errorf("body: batch size exceeds the maximum allowed value of %d", maxBatchSize))
+	}
+
+	results := make([]*BatchResult, len(rawTxs))
+	txs := make(tx.Transactions, len(rawTxs))
+	valid := make(tx.Transactions, 0, len(rawTxs))
+	for i, rawTx := range rawTxs {
+		if rawTx == nil {
+			results[i] = &BatchResult{Error: &BatchError{BatchErrBadTx, "raw: missing tx"}}
+			continue
+		}
+		tx, err := rawTx.decode()
+		if err != nil {
+			results[i] = &BatchResult{Error: &BatchError{BatchErrBadTx, errors.WithMessage(err, "raw").Error()}}
+			continue
+		}
+		txs[i] = tx
+		valid = append(valid, tx)
+	}
+
+	if atomic == "true" {
+		return t.sendTransactionsAtomically(w, txs, results)
+	}
+
+	// txs are added in order, so that a tx can depend on the previous ones in the same batch
+	for i, tx := range txs {
+		if tx == nil {
+			continue
+		}
+		if err := t.pool.AddLocal(tx); err != nil {
+			code := BatchErrInternal
+			if txpool.IsBadTx(err) {
+				code = BatchErrBadTx
+			} else if txpool.IsTxRejected(err) {
+				code = BatchErrTxRejected
+			}
+			results[i] = &BatchResult{Error: &BatchError{code, err.Error()}}
+			continue
+		}
+		id := tx.ID()
+		results[i] = &BatchResult{ID: &id}
+	}
+	return utils.WriteJSON(w, results)
+}
+
+// sendTransactionsAtomically adds the whole batch or nothing. Unlike the non-atomic mode, the
+// first tx that fails fails the request.
+func (t *Transactions) sendTransactionsAtomically(w http.ResponseWriter, txs tx.Transactions, results []*BatchResult) error {
+	for i, result := range results {
+		if result != nil {
+			return utils.BadRequest(fmt.Errorf("body[%d]: %s", i, result.Error.Message))
+		}
+	}
+
+	i, err := t.pool.AddLocalAtomically(txs)
+	if err != nil {
+		badTx, rejected := txpool.IsBadTx(err), txpool.IsTxRejected(err)
+		if i >= 0 {
+			err = errors.WithMessage(err, fmt.Sprintf("body[%d]", i))
+		}
+		if badTx {
+			return utils.BadRequest(err)
+		}
+		if rejected {
+			return utils.Forbidden(err)
+		}
+		return err
+	}
+
+	for i, tx := range txs {
+		id := tx.ID()
+		results[i] = &BatchResult{ID: &id}
+	}
+	return utils.WriteJSON(w, results)
 }
+
 func (t *Transactions) handleScheduleTransaction(w http.ResponseWriter, req *http.Request) error {
 	var rawTx *RawScheduledTx
 	if err := utils.ParseJSON(req.Body, &rawTx); err != nil {
@@ -162,9 +258,7 @@ func (t *Transactions) handleScheduleTransaction(w http.ResponseWriter, req *htt
 	t.schedule.Push(tx, *time)
 	logger.Info(fmt.Sprintf("received a schedule, total (%v)", t.schedule.Len()))
 
-	return utils.WriteJSON(w, map[string]string{
-		"id": tx.ID().String(),
-	})
+	return utils.WriteJSON(w, &SendTxResult{ID: tx.ID()})
 }
 
 func (t *Transactions) handleGetTransactionByID(w http.ResponseWriter, req *http.Request) error {
@@ -198,12 +292,22 @@ func (t *Transactions) handleGetTransactionByID(w http.ResponseWriter, req *http
 		if err != nil {
 			return err
 		}
+		if tx != nil && tx.Meta != nil {
+			if err := t.setImmutable(w, tx.Meta.BlockID); err != nil {
+				return err
+			}
+		}
 		return utils.WriteJSON(w, tx)
 	}
 	tx, err := t.getTransactionByID(txID, head, pending == "true")
 	if err != nil {
 		return err
 	}
+	if tx != nil && tx.Meta != nil {
+		if err := t.setImmutable(w, tx.Meta.BlockID); err != nil {
+			return err
+		}
+	}
 	return utils.WriteJSON(w, tx)
 }
 
@@ -229,9 +333,26 @@ func (t *Transactions) handleGetTransactionReceiptByID(w http.ResponseWriter, re
 	if err != nil {
 		return err
 	}
+	if receipt != nil {
+		if err := t.setImmutable(w, receipt.Meta.BlockID); err != nil {
+			return err
+		}
+	}
 	return utils.WriteJSON(w, receipt)
 }
 
+// setImmutable marks the response immutable if the tx is included by a finalized block.
+func (t *Transactions) setImmutable(w http.ResponseWriter, blockID thor.Bytes32) error {
+	finalized, err := utils.IsFinalized(blockID, t.repo, t.bft)
+	if err != nil {
+		return err
+	}
+	if finalized {
+		utils.SetImmutable(w)
+	}
+	return nil
+}
+
 func (t *Transactions) parseHead(head string) (thor.Bytes32, error) {
 	if head == "" {
 		return t.repo.BestBlockSummary().Header.ID(), nil
@@ -250,6 +371,10 @@ func (t *Transactions) Mount(root *mux.Router, pathPrefix string) {
 		Methods(http.MethodPost).
 		Name("transactions_send_tx").
 		HandlerFunc(utils.WrapHandlerFunc(t.handleSendTransaction))
+	sub.Path("/batch").
+		Methods(http.MethodPost).
+		Name("transactions_send_txs").
+		HandlerFunc(utils.WrapHandlerFunc(t.handleSendTransactions))
 	sub.Path("/schedule").
 		Methods(http.MethodPost).
 		Name("transactions_schedule_tx").
@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/v2/api/transactions"
 	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/cmd/thor/solo"
 	"github.com/vechain/thor/v2/genesis"
 	"github.com/vechain/thor/v2/muxdb"
 	"github.com/vechain/thor/v2/packer"
@@ -47,6 +48,11 @@ func TestTransaction(t *testing.T) {
 		"sendTx":              sendTx,
 		"sendTxWithBadFormat": sendTxWithBadFormat,
 		"sendTxThatCannotBeAcceptedInLocalMempool": sendTxThatCannotBeAcceptedInLocalMempool,
+		"sendTxsBatch":                    sendTxsBatch,
+		"sendTxsBatchWithBadFormat":       sendTxsBatchWithBadFormat,
+		"sendTxsBatchOverQuota":           sendTxsBatchOverQuota,
+		"sendTxsBatchAtomicallyOverQuota": sendTxsBatchAtomicallyOverQuota,
+		"sendTxsBatchAtomicallyRollback":  sendTxsBatchAtomicallyRollback,
 	} {
 		t.Run(name, tt)
 	}
@@ -238,6 +244,133 @@ func sendTxThatCannotBeAcceptedInLocalMempool(t *testing.T) {
 	assert.Contains(t, string(res), "bad tx: chain tag mismatch")
 }
 
+func sendTxsBatch(t *testing.T) {
+	tx1 := newSignedTx(t, 1, nil, genesis.DevAccounts()[1])
+	id1 := tx1.ID()
+	tx2 := newSignedTx(t, 2, &id1, genesis.DevAccounts()[1])
+
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/transactions/batch", []transactions.RawTx{encodeRawTx(t, tx1), encodeRawTx(t, tx2)}, 200)
+	var results []*transactions.BatchResult
+	if err := json.Unmarshal(res, &results); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*transactions.BatchResult{{ID: &id1}, {ID: ptr(tx2.ID())}}, results)
+}
+
+func sendTxsBatchWithBadFormat(t *testing.T) {
+	trx := newSignedTx(t, 1, nil, genesis.DevAccounts()[2])
+
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/transactions/batch", []transactions.RawTx{{Raw: "badRawTx"}, encodeRawTx(t, trx)}, 200)
+	var results []*transactions.BatchResult
+	if err := json.Unmarshal(res, &results); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(results))
+	assert.Nil(t, results[0].ID)
+	assert.Equal(t, transactions.BatchErrBadTx, results[0].Error.Code)
+	assert.Contains(t, results[0].Error.Message, hexutil.ErrMissingPrefix.Error())
+	assert.Equal(t, trx.ID(), *results[1].ID)
+	assert.Nil(t, results[1].Error)
+
+	res = httpPostAndCheckResponseStatus(t, ts.URL+"/transactions/batch", []transactions.RawTx{}, 400)
+	assert.Equal(t, "body: empty batch", strings.TrimSpace(string(res)))
+
+	res = httpPostAndCheckResponseStatus(t, ts.URL+"/transactions/batch?atomic=yes", []transactions.RawTx{encodeRawTx(t, trx)}, 400)
+	assert.Equal(t, "atomic: should be boolean", strings.TrimSpace(string(res)))
+}
+
+func sendTxsBatchOverQuota(t *testing.T) {
+	rawTxs := make([]transactions.RawTx, 17)
+	for i := range rawTxs {
+		rawTxs[i] = encodeRawTx(t, newSignedTx(t, uint64(i), nil, genesis.DevAccounts()[3]))
+	}
+
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/transactions/batch", rawTxs, 200)
+	var results []*transactions.BatchResult
+	if err := json.Unmarshal(res, &results); err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results[:16] {
+		assert.NotNil(t, result.ID)
+	}
+	assert.Nil(t, results[16].ID)
+	assert.Equal(t, &transactions.BatchError{Code: transactions.BatchErrTxRejected, Message: "tx rejected: account quota exceeded"}, results[16].Error)
+}
+
+func sendTxsBatchAtomicallyOverQuota(t *testing.T) {
+	txs := make(tx.Transactions, 17)
+	rawTxs := make([]transactions.RawTx, len(txs))
+	for i := range txs {
+		txs[i] = newSignedTx(t, uint64(i), nil, genesis.DevAccounts()[4])
+		rawTxs[i] = encodeRawTx(t, txs[i])
+	}
+
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/transactions/batch?atomic=true", rawTxs, 403)
+	assert.Equal(t, "tx rejected: account quota exceeded", strings.TrimSpace(string(res)))
+
+	// none of the txs should be added
+	for _, trx := range txs {
+		res := httpGetAndCheckResponseStatus(t, ts.URL+"/transactions/"+trx.ID().String()+"?pending=true", 200)
+		assert.Equal(t, "null\n", string(res))
+	}
+
+	// fits in the quota
+	res = httpPostAndCheckResponseStatus(t, ts.URL+"/transactions/batch?atomic=true", rawTxs[:16], 200)
+	var results []*transactions.BatchResult
+	if err := json.Unmarshal(res, &results); err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range results {
+		assert.Equal(t, txs[i].ID(), *result.ID)
+	}
+}
+
+func sendTxsBatchAtomicallyRollback(t *testing.T) {
+	trx := newSignedTx(t, 1, nil, genesis.DevAccounts()[5])
+	rlpTx, err := rlp.EncodeToBytes(new(tx.Builder).Build())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := httpPostAndCheckResponseStatus(t, ts.URL+"/transactions/batch?atomic=true", []transactions.RawTx{encodeRawTx(t, trx), {Raw: "badRawTx"}}, 400)
+	assert.Contains(t, string(res), "body[1]: raw: ")
+
+	res = httpPostAndCheckResponseStatus(t, ts.URL+"/transactions/batch?atomic=true", []transactions.RawTx{encodeRawTx(t, trx), {Raw: hexutil.Encode(rlpTx)}}, 400)
+	assert.Equal(t, "body[1]: bad tx: chain tag mismatch", strings.TrimSpace(string(res)))
+
+	// the first tx is rolled back
+	res = httpGetAndCheckResponseStatus(t, ts.URL+"/transactions/"+trx.ID().String()+"?pending=true", 200)
+	assert.Equal(t, "null\n", string(res))
+}
+
+func newSignedTx(t *testing.T, nonce uint64, dependsOn *thor.Bytes32, from genesis.DevAccount) *tx.Transaction {
+	trx := new(tx.Builder).
+		BlockRef(tx.NewBlockRef(0)).
+		ChainTag(repo.ChainTag()).
+		Expiration(10).
+		Gas(21000).
+		Nonce(nonce).
+		DependsOn(dependsOn).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), from.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trx.WithSignature(sig)
+}
+
+func encodeRawTx(t *testing.T, trx *tx.Transaction) transactions.RawTx {
+	rlpTx, err := rlp.EncodeToBytes(trx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return transactions.RawTx{Raw: hexutil.Encode(rlpTx)}
+}
+
+func ptr(id thor.Bytes32) *thor.Bytes32 {
+	return &id
+}
+
 func handleGetTransactionByIDWithBadQueryParams(t *testing.T) {
 	badQueryParams := []string{
 		"?pending=badPending",
@@ -349,7 +482,7 @@ func initTransactionServer(t *testing.T) {
 		t.Fatal(e)
 	}
 
-	transactions.New(repo, mempool).Mount(router, "/transactions")
+	transactions.New(repo, mempool, nil, solo.NewBFTEngine(repo)).Mount(router, "/transactions")
 
 	ts = httptest.NewServer(router)
 }
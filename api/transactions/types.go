@@ -99,7 +99,32 @@ func (rtx *RawTx) decode() (*tx.Transaction, error) {
 	return tx, nil
 }
 
-type rawTransaction struct {
+// batch result error codes
+const (
+	BatchErrBadTx      = "bad_tx"
+	BatchErrTxRejected = "tx_rejected"
+	BatchErrInternal   = "internal"
+)
+
+// SendTxResult result of sending or scheduling a tx.
+type SendTxResult struct {
+	ID thor.Bytes32 `json:"id"`
+}
+
+// BatchError the reason why a tx in a batch was not added.
+type BatchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// BatchResult result of adding a tx in a batch.
+type BatchResult struct {
+	ID    *thor.Bytes32 `json:"id"`
+	Error *BatchError   `json:"error"`
+}
+
+// RawTransaction the RLP encoded transaction, with its meta if included.
+type RawTransaction struct {
 	RawTx
 	Meta *TxMeta `json:"meta"`
 }
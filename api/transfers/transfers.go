@@ -20,11 +20,11 @@ import (
 
 type Transfers struct {
 	repo  *chain.Repository
-	db    *logdb.LogDB
+	db    logdb.LogStore
 	limit uint64
 }
 
-func New(repo *chain.Repository, db *logdb.LogDB, logsLimit uint64) *Transfers {
+func New(repo *chain.Repository, db logdb.LogStore, logsLimit uint64) *Transfers {
 	return &Transfers{
 		repo,
 		db,
@@ -32,27 +32,38 @@ func New(repo *chain.Repository, db *logdb.LogDB, logsLimit uint64) *Transfers {
 	}
 }
 
-// Filter query logs with option
-func (t *Transfers) filter(ctx context.Context, filter *TransferFilter) ([]*FilteredTransfer, error) {
+// Filter query logs with option, the cursor pointing to the last transfer is also returned
+func (t *Transfers) filter(ctx context.Context, header http.Header, filter *TransferFilter) ([]*FilteredTransfer, *logdb.Cursor, error) {
 	rng, err := events.ConvertRange(t.repo.NewBestChain(), filter.Range)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	transfers, err := t.db.FilterTransfers(ctx, &logdb.TransferFilter{
+	tf := &logdb.TransferFilter{
 		CriteriaSet: filter.CriteriaSet,
 		Range:       rng,
 		Options:     filter.Options,
 		Order:       filter.Order,
-	})
+	}
+	if status := t.db.PruneStatus(); status.TransfersPruned(tf) {
+		if tf.Range, err = events.ClampPruned(header, status, tf.Range); err != nil {
+			return nil, nil, err
+		}
+	}
+	transfers, err := t.db.FilterTransfers(ctx, tf)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	tLogs := make([]*FilteredTransfer, len(transfers))
 	for i, trans := range transfers {
 		tLogs[i] = convertTransfer(trans)
 	}
-	return tLogs, nil
+	var cursor *logdb.Cursor
+	if len(transfers) > 0 {
+		last := transfers[len(transfers)-1]
+		cursor = logdb.NewCursor(last.BlockNumber, last.Index)
+	}
+	return tLogs, cursor, nil
 }
 
 func (t *Transfers) handleFilterTransferLogs(w http.ResponseWriter, req *http.Request) error {
@@ -60,29 +71,61 @@ func (t *Transfers) handleFilterTransferLogs(w http.ResponseWriter, req *http.Re
 	if err := utils.ParseJSON(req.Body, &filter); err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "body"))
 	}
+	return events.WriteFiltered(w, filter.Options, t.limit, func(options *logdb.Options) ([]*FilteredTransfer, *logdb.Cursor, error) {
+		filter.Options = options
+		return t.filter(req.Context(), w.Header(), &filter)
+	})
+}
+
+func (t *Transfers) handleSummary(w http.ResponseWriter, req *http.Request) error {
+	var filter TransferSummaryFilter
+	if err := utils.ParseJSON(req.Body, &filter); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
 	if filter.Options != nil && filter.Options.Limit > t.limit {
 		return utils.Forbidden(fmt.Errorf("options.limit exceeds the maximum allowed value of %d", t.limit))
 	}
+	if filter.Options != nil && filter.Options.Cursor != nil {
+		return utils.BadRequest(errors.New("options.cursor is not supported"))
+	}
 	if filter.Options == nil {
-		// if filter.Options is nil, set to the default limit +1
-		// to detect whether there are more logs than the default limit
+		// set to the default limit +1 to detect whether there are more buckets than the default limit
 		filter.Options = &logdb.Options{
 			Offset: 0,
 			Limit:  t.limit + 1,
 		}
 	}
 
-	tLogs, err := t.filter(req.Context(), &filter)
+	rng, err := events.ConvertRange(t.repo.NewBestChain(), filter.Range)
+	if err != nil {
+		return err
+	}
+	tf := &logdb.TransferFilter{
+		CriteriaSet: filter.CriteriaSet,
+		Range:       rng,
+		Options:     filter.Options,
+		Order:       filter.Order,
+	}
+	if status := t.db.PruneStatus(); status.TransfersPruned(tf) {
+		if tf.Range, err = events.ClampPruned(w.Header(), status, tf.Range); err != nil {
+			return err
+		}
+	}
+	summaries, err := t.db.SummarizeTransfers(req.Context(), tf, filter.Bucket)
 	if err != nil {
 		return err
 	}
 
 	// ensure the result size is less than the configured limit
-	if len(tLogs) > int(t.limit) {
-		return utils.Forbidden(fmt.Errorf("the number of filtered logs exceeds the maximum allowed value of %d, please use pagination", t.limit))
+	if len(summaries) > int(t.limit) {
+		return utils.Forbidden(fmt.Errorf("the number of buckets exceeds the maximum allowed value of %d, please use pagination", t.limit))
 	}
 
-	return utils.WriteJSON(w, tLogs)
+	results := make([]*TransferSummary, len(summaries))
+	for i, s := range summaries {
+		results[i] = convertTransferSummary(s)
+	}
+	return utils.WriteJSON(w, results)
 }
 
 func (t *Transfers) Mount(root *mux.Router, pathPrefix string) {
@@ -92,4 +135,8 @@ func (t *Transfers) Mount(root *mux.Router, pathPrefix string) {
 		Methods(http.MethodPost).
 		Name("logs_filter_transfer").
 		HandlerFunc(utils.WrapHandlerFunc(t.handleFilterTransferLogs))
+	sub.Path("/summary").
+		Methods(http.MethodPost).
+		Name("logs_summarize_transfer").
+		HandlerFunc(utils.WrapHandlerFunc(t.handleSummary))
 }
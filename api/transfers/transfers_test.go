@@ -20,6 +20,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/v2/api/events"
 	"github.com/vechain/thor/v2/api/transfers"
+	"github.com/vechain/thor/v2/api/utils"
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/genesis"
@@ -94,7 +95,108 @@ func TestOption(t *testing.T) {
 	assert.Equal(t, "the number of filtered logs exceeds the maximum allowed value of 5, please use pagination", strings.Trim(string(res), "\n"))
 }
 
+func TestCursor(t *testing.T) {
+	db := createDb(t)
+	initTransferServer(t, db, defaultLogLimit)
+	defer ts.Close()
+	insertBlocks(t, db, 5)
+
+	res, _ := httpPost(t, ts.URL+"/transfers", transfers.TransferFilter{Order: logdb.DESC})
+	var allLogs []*transfers.FilteredTransfer
+	if err := json.Unmarshal(res, &allLogs); err != nil {
+		t.Fatal(err)
+	}
+
+	filter := transfers.TransferFilter{
+		Options: &logdb.Options{Limit: 2},
+		Order:   logdb.DESC,
+	}
+
+	var pagedLogs []*transfers.FilteredTransfer
+	for {
+		res, cursor := httpPostWithCursor(t, ts.URL+"/transfers", filter)
+		var tLogs []*transfers.FilteredTransfer
+		if err := json.Unmarshal(res, &tLogs); err != nil {
+			t.Fatal(err)
+		}
+		if len(tLogs) == 0 {
+			assert.Empty(t, cursor)
+			break
+		}
+		pagedLogs = append(pagedLogs, tLogs...)
+
+		filter.Options.Cursor = new(logdb.Cursor)
+		if err := filter.Options.Cursor.UnmarshalText([]byte(cursor)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.Equal(t, allLogs, pagedLogs)
+
+	filter.Options.Offset = 1
+	res, statusCode := httpPost(t, ts.URL+"/transfers", filter)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "options.offset must be 0 when options.cursor is set", strings.TrimSpace(string(res)))
+}
+
 // Test functions
+func TestSummary(t *testing.T) {
+	db := createDb(t)
+	initTransferServer(t, db, defaultLogLimit)
+	defer ts.Close()
+	insertBlocks(t, db, 5)
+
+	res, _ := httpPost(t, ts.URL+"/transfers", transfers.TransferFilter{})
+	var allLogs []*transfers.FilteredTransfer
+	if err := json.Unmarshal(res, &allLogs); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		amount  = new(big.Int)
+		senders = make(map[thor.Address]bool)
+	)
+	for _, log := range allLogs {
+		amount.Add(amount, (*big.Int)(log.Amount))
+		senders[log.Sender] = true
+	}
+
+	// the blocks inserted have zero timestamps, so the same in a single bucket
+	for _, bucket := range []uint64{0, 60} {
+		res, statusCode := httpPost(t, ts.URL+"/transfers/summary", transfers.TransferSummaryFilter{Bucket: bucket})
+		assert.Equal(t, http.StatusOK, statusCode)
+		var summaries []*transfers.TransferSummary
+		if err := json.Unmarshal(res, &summaries); err != nil {
+			t.Fatal(err)
+		}
+		if assert.Len(t, summaries, 1) {
+			assert.Equal(t, uint64(0), summaries[0].Time)
+			assert.Equal(t, uint64(len(allLogs)), summaries[0].Count)
+			assert.Equal(t, amount, (*big.Int)(summaries[0].Amount))
+			assert.Equal(t, uint64(len(senders)), summaries[0].Senders)
+		}
+	}
+
+	res, statusCode := httpPost(t, ts.URL+"/transfers/summary", transfers.TransferSummaryFilter{
+		CriteriaSet: []*logdb.TransferCriteria{{Sender: &allLogs[0].Sender}},
+	})
+	assert.Equal(t, http.StatusOK, statusCode)
+	var summaries []*transfers.TransferSummary
+	if err := json.Unmarshal(res, &summaries); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, summaries, 1) {
+		assert.Equal(t, uint64(1), summaries[0].Count)
+		assert.Equal(t, allLogs[0].Amount, summaries[0].Amount)
+	}
+
+	res, statusCode = httpPost(t, ts.URL+"/transfers/summary", transfers.TransferSummaryFilter{Options: &logdb.Options{Limit: defaultLogLimit + 1}})
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.Equal(t, "options.limit exceeds the maximum allowed value of 1000", strings.TrimSpace(string(res)))
+
+	res, statusCode = httpPost(t, ts.URL+"/transfers/summary", transfers.TransferSummaryFilter{Options: &logdb.Options{Limit: 1, Cursor: logdb.NewCursor(1, 0)}})
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "options.cursor is not supported", strings.TrimSpace(string(res)))
+}
+
 func testTransferBadRequest(t *testing.T) {
 	badBody := []byte{0x00, 0x01, 0x02}
 
@@ -225,3 +327,21 @@ func httpPost(t *testing.T, url string, body interface{}) ([]byte, int) {
 	}
 	return r, res.StatusCode
 }
+
+func httpPostWithCursor(t *testing.T, url string, body interface{}) ([]byte, string) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.Post(url, "application/x-www-form-urlencoded", bytes.NewReader(data)) // nolint: gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	return r, res.Header.Get(utils.NextCursorHeader)
+}
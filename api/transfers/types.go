@@ -45,6 +45,34 @@ func convertTransfer(transfer *logdb.Transfer) *FilteredTransfer {
 	}
 }
 
+// TransferSummaryFilter filters transfers to summarize, grouped by buckets of block time if bucket is not zero.
+// The order and options apply to buckets.
+type TransferSummaryFilter struct {
+	CriteriaSet []*logdb.TransferCriteria `json:"criteriaSet"`
+	Range       *events.Range             `json:"range"`
+	Bucket      uint64                    `json:"bucket"` // in seconds
+	Options     *logdb.Options            `json:"options"`
+	Order       logdb.Order               `json:"order"`
+}
+
+// TransferSummary summarizes transfers within a bucket of block time.
+type TransferSummary struct {
+	Time    uint64                `json:"time"` // start of the bucket, 0 if not grouped
+	Count   uint64                `json:"count"`
+	Amount  *math.HexOrDecimal256 `json:"amount"`
+	Senders uint64                `json:"senders"` // the number of distinct senders
+}
+
+func convertTransferSummary(summary *logdb.TransferSummary) *TransferSummary {
+	v := math.HexOrDecimal256(*summary.Amount)
+	return &TransferSummary{
+		Time:    summary.BucketTime,
+		Count:   summary.Count,
+		Amount:  &v,
+		Senders: summary.Senders,
+	}
+}
+
 type TransferFilter struct {
 	CriteriaSet []*logdb.TransferCriteria
 	Range       *events.Range
@@ -72,6 +72,26 @@ const (
 	JSONContentType = "application/json; charset=utf-8"
 )
 
+// ImmutableCacheControl the Cache-Control header of responses which never change, e.g. finalized chain data.
+const ImmutableCacheControl = "public, max-age=31536000, immutable"
+
+// SetImmutable marks the response as immutable, its ETag and conditional requests are handled by the server.
+func SetImmutable(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", ImmutableCacheControl)
+}
+
+// IsImmutable returns whether the response header is marked as immutable.
+func IsImmutable(h http.Header) bool {
+	return h.Get("Cache-Control") == ImmutableCacheControl
+}
+
+// NextCursorHeader response header carrying the cursor to continue paging after the last returned log.
+const NextCursorHeader = "x-next-cursor"
+
+// PrunedBeforeHeader response header set if the queried range is clamped to the given block number,
+// since logs before it are pruned.
+const PrunedBeforeHeader = "x-pruned-before"
+
 // ParseJSON parse a JSON object using strict mode.
 func ParseJSON(r io.Reader, v interface{}) error {
 	decoder := json.NewDecoder(r)
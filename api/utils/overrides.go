@@ -0,0 +1,126 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/xenv"
+)
+
+// AccountOverride fields of an account to be replaced before simulating a call.
+// Nil fields are left untouched, storage slots not listed keep their values.
+type AccountOverride struct {
+	Balance *math.HexOrDecimal256   `json:"balance"`
+	Energy  *math.HexOrDecimal256   `json:"energy"`
+	Code    *string                 `json:"code"`
+	Storage map[string]thor.Bytes32 `json:"storage"`
+}
+
+// StateOverrides account overrides keyed by address.
+type StateOverrides map[string]*AccountOverride
+
+// Apply writes the overrides into the given state.
+// The state is expected to be a throwaway one which is never committed.
+func (o StateOverrides) Apply(st *state.State, blockTime uint64) error {
+	for key, ov := range o {
+		addr, err := thor.ParseAddress(key)
+		if err != nil {
+			return BadRequest(errors.WithMessage(err, "stateOverrides"))
+		}
+		if ov == nil {
+			continue
+		}
+		if ov.Balance != nil {
+			if (*big.Int)(ov.Balance).Sign() < 0 {
+				return BadRequest(errors.New("stateOverrides: negative balance"))
+			}
+			if err := st.SetBalance(addr, (*big.Int)(ov.Balance)); err != nil {
+				return err
+			}
+		}
+		if ov.Energy != nil {
+			if (*big.Int)(ov.Energy).Sign() < 0 {
+				return BadRequest(errors.New("stateOverrides: negative energy"))
+			}
+			if err := st.SetEnergy(addr, (*big.Int)(ov.Energy), blockTime); err != nil {
+				return err
+			}
+		}
+		if ov.Code != nil {
+			code, err := hexutil.Decode(*ov.Code)
+			if err != nil {
+				return BadRequest(errors.WithMessage(err, "stateOverrides: code"))
+			}
+			if err := st.SetCode(addr, code); err != nil {
+				return err
+			}
+		}
+		for k, v := range ov.Storage {
+			slot, err := thor.ParseBytes32(k)
+			if err != nil {
+				return BadRequest(errors.WithMessage(err, "stateOverrides: storage"))
+			}
+			st.SetStorage(addr, slot, v)
+		}
+	}
+	return nil
+}
+
+// BlockOverrides fields of the block context to be replaced before simulating a call.
+type BlockOverrides struct {
+	Number      *uint32       `json:"number"`
+	Timestamp   *uint64       `json:"timestamp"`
+	GasLimit    *uint64       `json:"gasLimit"`
+	Beneficiary *thor.Address `json:"beneficiary"`
+}
+
+// Apply replaces the fields of the block context, nil receiver is a no-op.
+// The number can't exceed the one next to the context, since later blocks have no hash to be read by BLOCKHASH.
+func (o *BlockOverrides) Apply(ctx *xenv.BlockContext) error {
+	if o == nil {
+		return nil
+	}
+	if o.Number != nil {
+		if uint64(*o.Number) > uint64(ctx.Number)+1 {
+			return BadRequest(errors.Errorf("blockOverrides: number exceeds %d", uint64(ctx.Number)+1))
+		}
+		ctx.Number = *o.Number
+	}
+	if o.Timestamp != nil {
+		ctx.Time = *o.Timestamp
+	}
+	if o.GasLimit != nil {
+		ctx.GasLimit = *o.GasLimit
+	}
+	if o.Beneficiary != nil {
+		ctx.Beneficiary = *o.Beneficiary
+	}
+	return nil
+}
+
+// NewCallChain returns the chain which a call in the block context of the header reads block hashes from.
+// It ends at the parent of the header, or at the header itself if the number is overridden to the next one,
+// which is rejected if the header is not a stored one, e.g. the header of the "next" revision.
+func NewCallChain(repo *chain.Repository, header *block.Header, ctx *xenv.BlockContext) (*chain.Chain, error) {
+	if ctx.Number <= header.Number() {
+		return repo.NewChain(header.ParentID()), nil
+	}
+	if _, err := repo.GetBlockSummary(header.ID()); err != nil {
+		if repo.IsNotFound(err) {
+			return nil, BadRequest(errors.Errorf("blockOverrides: number exceeds %d", header.Number()))
+		}
+		return nil, err
+	}
+	return repo.NewChain(header.ID()), nil
+}
@@ -32,6 +32,15 @@ func (rev *Revision) IsNext() bool {
 	return rev.val == revNext
 }
 
+// IsFixed returns whether the revision is a block ID or number, which never points to another block once finalized.
+func (rev *Revision) IsFixed() bool {
+	switch rev.val.(type) {
+	case thor.Bytes32, uint32:
+		return true
+	}
+	return false
+}
+
 // ParseRevision parses a query parameter into a block number or block ID.
 func ParseRevision(revision string, allowNext bool) (*Revision, error) {
 	if revision == "" || revision == "best" {
@@ -155,3 +164,17 @@ func GetSummaryAndState(rev *Revision, repo *chain.Repository, bft bft.Committer
 	st := stater.NewState(sum.Header.StateRoot(), sum.Header.Number(), sum.Conflicts, sum.SteadyNum)
 	return sum, st, nil
 }
+
+// IsFinalized returns whether the block is on the finalized chain.
+func IsFinalized(blockID thor.Bytes32, repo *chain.Repository, bft bft.Committer) (bool, error) {
+	finalized := bft.Finalized()
+	num := block.Number(blockID)
+	if block.Number(finalized) < num {
+		return false, nil
+	}
+	id, err := repo.NewChain(finalized).GetBlockID(num)
+	if err != nil {
+		return false, err
+	}
+	return id == blockID, nil
+}
@@ -100,6 +100,50 @@ func TestAllowNext(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestIsFixed(t *testing.T) {
+	for rev, fixed := range map[string]bool{
+		"":          false,
+		"best":      false,
+		"finalized": false,
+		"justified": false,
+		"next":      false,
+		"1":         true,
+		"0x00000000c05a20fbca2bf6ae3affba6af4a74b800b585bf7a4988aba7aea69f6": true,
+	} {
+		revision, err := ParseRevision(rev, true)
+		assert.NoError(t, err)
+		assert.Equal(t, fixed, revision.IsFixed(), rev)
+	}
+}
+
+func TestIsFinalized(t *testing.T) {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	b, _, _, err := genesis.NewDevnet().Build(stater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, _ := chain.NewRepository(db, b)
+	bft := solo.NewBFTEngine(repo)
+
+	finalized, err := IsFinalized(b.Header().ID(), repo, bft)
+	assert.NoError(t, err)
+	assert.True(t, finalized)
+
+	// same number, but not the finalized one
+	var other thor.Bytes32
+	other[31] = 1
+	finalized, err = IsFinalized(other, repo, bft)
+	assert.NoError(t, err)
+	assert.False(t, finalized)
+
+	// above the finalized block
+	other[3] = 1
+	finalized, err = IsFinalized(other, repo, bft)
+	assert.NoError(t, err)
+	assert.False(t, finalized)
+}
+
 func TestGetSummary(t *testing.T) {
 	db := muxdb.NewMem()
 	stater := state.NewStater(db)
@@ -6,6 +6,8 @@
 package main
 
 import (
+	"runtime"
+
 	"github.com/vechain/thor/v2/log"
 	cli "gopkg.in/urfave/cli.v1"
 )
@@ -69,6 +71,46 @@ var (
 		Value: 1000,
 		Usage: "limit the number of logs returned by /logs API",
 	}
+	apiLogsMaxSetSizeFlag = cli.Uint64Flag{
+		Name:  "api-logs-max-set-size",
+		Value: 256,
+		Usage: "limit the number of addresses or topics in a set of /logs/event criteria",
+	}
+	apiReadyMaxHeadAgeFlag = cli.Uint64Flag{
+		Name:  "api-ready-max-head-age",
+		Value: 60,
+		Usage: "max age of the best block in seconds for /health/ready, 0 to disable",
+	}
+	apiReadyMinPeersFlag = cli.Uint64Flag{
+		Name:  "api-ready-min-peers",
+		Usage: "min number of connected peers for /health/ready, 0 to disable",
+	}
+	apiReadyMaxLogsLagFlag = cli.Uint64Flag{
+		Name:  "api-ready-max-logs-lag",
+		Value: 10,
+		Usage: "max number of blocks the logs database lags behind the best block for /health/ready, 0 to disable",
+	}
+	apiKeysFileFlag = cli.StringFlag{
+		Name:  "api-keys-file",
+		Usage: "path to the JSON file of API keys, reloaded once modified",
+	}
+	apiKeysRequiredFlag = cli.BoolFlag{
+		Name:  "api-keys-required",
+		Usage: "reject API requests without a valid API key",
+	}
+	apiRateLimitFlag = cli.Float64Flag{
+		Name:  "api-rate-limit",
+		Usage: "requests per second allowed for each API key or IP, 0 for unlimited",
+	}
+	apiRateLimitExpensiveFlag = cli.Float64Flag{
+		Name:  "api-rate-limit-expensive",
+		Usage: "requests per second allowed for each API key or IP on /debug, /logs and /accounts calls, 0 for unlimited",
+	}
+	apiCacheSizeFlag = cli.Uint64Flag{
+		Name:  "api-cache-size",
+		Value: 32,
+		Usage: "megabytes of ram allocated to the cache of finalized API responses, 0 to disable",
+	}
 	enableAPILogsFlag = cli.BoolFlag{
 		Name:  "enable-api-logs",
 		Usage: "enables API requests logging",
@@ -127,6 +169,31 @@ var (
 		Name:  "skip-logs",
 		Usage: "skip writing event|transfer logs (/logs API will be disabled)",
 	}
+	logDBBackendFlag = cli.StringFlag{
+		Name:  "logdb-backend",
+		Value: "sqlite",
+		Usage: "storage backend of event|transfer logs (sqlite|kv)",
+	}
+	logsRetentionBlocksFlag = cli.Uint64Flag{
+		Name:  "logs-retention-blocks",
+		Usage: "keep event|transfer logs of the latest blocks and prune older ones (0 to keep all)",
+	}
+	logsRetentionDaysFlag = cli.Uint64Flag{
+		Name:  "logs-retention-days",
+		Usage: "keep event|transfer logs of the latest days and prune older ones (0 to keep all)",
+	}
+	logsKeepAddressesFlag = cli.StringFlag{
+		Name:  "logs-keep-addresses",
+		Usage: "comma separated addresses, of which event|transfer logs are kept indefinitely",
+	}
+	logsFailuresFlag = cli.BoolFlag{
+		Name:  "logs-failures",
+		Usage: "record clauses of reverted txs in log db (/logs/failure API)",
+	}
+	logsTokenTransfersFlag = cli.BoolFlag{
+		Name:  "logs-token-transfers",
+		Usage: "record fungible token transfers, including VTHO, in log db (/logs/token-transfer API)",
+	}
 	verifyLogsFlag = cli.BoolFlag{
 		Name:   "verify-logs",
 		Usage:  "verify log db at startup",
@@ -200,4 +267,39 @@ var (
 		Name:  "genesis",
 		Usage: "path to genesis file, if not set, the default devnet genesis will be used",
 	}
+
+	// logdb rebuild only flags
+	rebuildReadersFlag = cli.IntFlag{
+		Name:  "readers",
+		Value: runtime.NumCPU(),
+		Usage: "number of concurrent readers of chain data",
+	}
+	rebuildVerifyFlag = cli.BoolFlag{
+		Name:  "verify",
+		Usage: "verify log db after rebuilt",
+	}
+
+	// logdb export only flags
+	exportFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "the first block to export (default: continue from the last export)",
+	}
+	exportToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "the last block to export (default: the newest block with logs)",
+	}
+	exportFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Value: "csv",
+		Usage: "format of output files (csv|parquet|ndjson)",
+	}
+	exportOutFlag = cli.StringFlag{
+		Name:  "out",
+		Usage: "output directory",
+	}
+	exportPartitionFlag = cli.Uint64Flag{
+		Name:  "partition",
+		Value: 100000,
+		Usage: "number of blocks per output file",
+	}
 )
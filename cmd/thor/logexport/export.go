@@ -0,0 +1,249 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logexport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/log"
+	"github.com/vechain/thor/v2/logdb"
+)
+
+var logger = log.WithContext("pkg", "logexport")
+
+const (
+	// stateFileName is the name of the file in the output dir, saving positions of the last exported logs.
+	stateFileName = "export-state.json"
+	// pageSize is the number of logs queried at a time.
+	pageSize = 10000
+)
+
+// Options of the export.
+type Options struct {
+	From      *uint32 // the first block to export, or nil to continue from the last exported logs
+	To        uint32  // the last block to export
+	Format    Format
+	Partition uint32 // the number of blocks per output file
+}
+
+// Position is the position of a log, in the order of logs.
+type Position struct {
+	BlockNumber uint32 `json:"blockNumber"`
+	Index       uint32 `json:"index"`
+}
+
+// State is the state of incremental exports, saved in the output dir.
+type State struct {
+	Event    *Position `json:"event,omitempty"`    // the last exported event
+	Transfer *Position `json:"transfer,omitempty"` // the last exported transfer
+}
+
+// LoadState loads the state of the output dir, or the empty state if nothing is exported.
+func LoadState(dir string) (*State, error) {
+	var state State
+	data, err := os.ReadFile(filepath.Join(dir, stateFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, errors.Wrap(err, "decode state")
+	}
+	return &state, nil
+}
+
+// saveState saves the state by replacing the file, so it's never partially written.
+func saveState(dir string, state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, stateFileName+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, stateFileName))
+}
+
+// Export exports events and transfers of the block range into files partitioned by block range, under sub dirs
+// 'event' and 'transfer' of dir. The state is saved after each file written, so an interrupted or incremental
+// export continues right after the last exported logs.
+func Export(ctx context.Context, logDB logdb.LogStore, dir string, opts Options) error {
+	if opts.Partition == 0 {
+		return errors.New("zero partition")
+	}
+	state, err := LoadState(dir)
+	if err != nil {
+		return err
+	}
+
+	from := func(last *Position) (uint32, *logdb.Cursor) {
+		if opts.From != nil {
+			return *opts.From, nil
+		}
+		if last == nil {
+			return 0, nil
+		}
+		return last.BlockNumber, logdb.NewCursor(last.BlockNumber, last.Index)
+	}
+
+	eventFrom, eventCursor := from(state.Event)
+	if status := logDB.PruneStatus(); eventFrom <= opts.To && status.EventsPruned(&logdb.EventFilter{Range: &logdb.Range{From: eventFrom, To: opts.To}}) {
+		return fmt.Errorf("logs before block %d are pruned", status.BlockNum)
+	}
+	transferFrom, transferCursor := from(state.Transfer)
+	if status := logDB.PruneStatus(); transferFrom <= opts.To && status.TransfersPruned(&logdb.TransferFilter{Range: &logdb.Range{From: transferFrom, To: opts.To}}) {
+		return fmt.Errorf("logs before block %d are pruned", status.BlockNum)
+	}
+
+	if err := exportTable(ctx, dir, "event", eventColumns, opts, eventFrom, eventCursor,
+		func(rng *logdb.Range, cursor *logdb.Cursor) ([]*logdb.Event, error) {
+			return logDB.FilterEvents(ctx, &logdb.EventFilter{Range: rng, Options: &logdb.Options{Limit: pageSize, Cursor: cursor}})
+		},
+		func(ev *logdb.Event) (eventRow, *Position) {
+			return newEventRow(ev), &Position{ev.BlockNumber, ev.Index}
+		},
+		func(last *Position) error {
+			state.Event = last
+			return saveState(dir, state)
+		},
+	); err != nil {
+		return errors.Wrap(err, "export events")
+	}
+
+	if err := exportTable(ctx, dir, "transfer", transferColumns, opts, transferFrom, transferCursor,
+		func(rng *logdb.Range, cursor *logdb.Cursor) ([]*logdb.Transfer, error) {
+			return logDB.FilterTransfers(ctx, &logdb.TransferFilter{Range: rng, Options: &logdb.Options{Limit: pageSize, Cursor: cursor}})
+		},
+		func(tr *logdb.Transfer) (transferRow, *Position) {
+			return newTransferRow(tr), &Position{tr.BlockNumber, tr.Index}
+		},
+		func(last *Position) error {
+			state.Transfer = last
+			return saveState(dir, state)
+		},
+	); err != nil {
+		return errors.Wrap(err, "export transfers")
+	}
+	return nil
+}
+
+// exportTable exports logs of the table from the block, and after the cursor if not nil, to the last block of opts.
+func exportTable[L any, R row](
+	ctx context.Context,
+	dir string,
+	table string,
+	columns []string,
+	opts Options,
+	from uint32,
+	cursor *logdb.Cursor,
+	filter func(rng *logdb.Range, cursor *logdb.Cursor) ([]L, error),
+	convert func(L) (R, *Position),
+	save func(last *Position) error,
+) error {
+	if from > opts.To {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Join(dir, table), 0755); err != nil {
+		return err
+	}
+
+	for start := uint64(from); start <= uint64(opts.To); {
+		end := min(uint64(opts.To), (start/uint64(opts.Partition)+1)*uint64(opts.Partition)-1)
+		rng := &logdb.Range{From: uint32(start), To: uint32(end)}
+
+		last, n, err := exportPartition(
+			filepath.Join(dir, table, fmt.Sprintf("%010d-%010d.%v", start, end, opts.Format)),
+			opts.Format,
+			columns,
+			func(cursor *logdb.Cursor) ([]L, error) {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				return filter(rng, cursor)
+			},
+			cursor,
+			convert,
+		)
+		if err != nil {
+			return err
+		}
+		if last != nil {
+			if err := save(last); err != nil {
+				return errors.Wrap(err, "save state")
+			}
+			cursor = logdb.NewCursor(last.BlockNumber, last.Index)
+			logger.Info("logs exported", "table", table, "from", start, "to", end, "count", n)
+		}
+		start = end + 1
+	}
+	return nil
+}
+
+// exportPartition writes logs of a partition to the file, which is created only if there are logs.
+// It returns the position of the last written log, and the number of logs.
+func exportPartition[L any, R row](
+	path string,
+	format Format,
+	columns []string,
+	filter func(cursor *logdb.Cursor) ([]L, error),
+	cursor *logdb.Cursor,
+	convert func(L) (R, *Position),
+) (last *Position, n int, err error) {
+	var (
+		file *os.File
+		w    rowWriter[R]
+	)
+	defer func() {
+		if file != nil {
+			if err == nil {
+				if err = w.Close(); err == nil {
+					err = file.Sync()
+				}
+			}
+			if err1 := file.Close(); err == nil {
+				err = err1
+			}
+		}
+	}()
+
+	for {
+		logs, err := filter(cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(logs) == 0 {
+			return last, n, nil
+		}
+		if file == nil {
+			if file, err = os.Create(path); err != nil {
+				return nil, 0, err
+			}
+			if w, err = newRowWriter[R](file, format, columns); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		rows := make([]R, 0, len(logs))
+		for _, l := range logs {
+			var r R
+			r, last = convert(l)
+			rows = append(rows, r)
+		}
+		if err := w.Write(rows); err != nil {
+			return nil, 0, err
+		}
+		n += len(logs)
+		cursor = logdb.NewCursor(last.BlockNumber, last.Index)
+	}
+}
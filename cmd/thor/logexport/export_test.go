@@ -0,0 +1,155 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logexport
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+// writeBlocks writes blocks after the parent, each with an event and a transfer, and returns the last block.
+func writeBlocks(t *testing.T, logDB logdb.LogStore, parent *block.Block, n int) *block.Block {
+	w := logDB.NewWriter()
+	b := parent
+	for i := 0; i < n; i++ {
+		builder := new(block.Builder).Transaction(new(tx.Builder).Build())
+		if b != nil {
+			builder.ParentID(b.Header().ID())
+		}
+		b = builder.Build()
+		require.NoError(t, w.Write(b, tx.Receipts{{Outputs: []*tx.Output{{
+			Events:    tx.Events{{Address: thor.BytesToAddress([]byte("contract")), Topics: []thor.Bytes32{thor.BytesToBytes32([]byte("topic"))}}},
+			Transfers: tx.Transfers{{Sender: thor.BytesToAddress([]byte("sender")), Amount: big.NewInt(int64(i))}},
+		}}}}))
+	}
+	require.NoError(t, w.Commit())
+	return b
+}
+
+func readCSV(t *testing.T, path string) [][]string {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestExport(t *testing.T) {
+	var (
+		logDB = logdb.NewKVMem()
+		dir   = t.TempDir()
+		ctx   = context.Background()
+	)
+	defer logDB.Close()
+
+	// blocks #1..#25
+	last := writeBlocks(t, logDB, nil, 25)
+	require.NoError(t, Export(ctx, logDB, dir, Options{To: 25, Format: CSV, Partition: 10}))
+
+	for table, columns := range map[string][]string{"event": eventColumns, "transfer": transferColumns} {
+		for name, n := range map[string]int{
+			"0000000000-0000000009.csv": 9,
+			"0000000010-0000000019.csv": 10,
+			"0000000020-0000000025.csv": 6,
+		} {
+			records := readCSV(t, filepath.Join(dir, table, name))
+			assert.Equal(t, columns, records[0])
+			assert.Len(t, records, n+1, "%v/%v", table, name)
+		}
+	}
+	records := readCSV(t, filepath.Join(dir, "event", "0000000000-0000000009.csv"))
+	assert.Equal(t, []string{"1", "0"}, records[1][:2])
+	assert.Equal(t, thor.BytesToAddress([]byte("contract")).String(), records[1][7])
+	assert.Equal(t, thor.BytesToBytes32([]byte("topic")).String(), records[1][8])
+	assert.Equal(t, "", records[1][9])
+
+	state, err := LoadState(dir)
+	require.NoError(t, err)
+	assert.Equal(t, &State{Event: &Position{25, 0}, Transfer: &Position{25, 0}}, state)
+
+	// incremental export continues after the last exported logs
+	writeBlocks(t, logDB, last, 5)
+	require.NoError(t, Export(ctx, logDB, dir, Options{To: 30, Format: CSV, Partition: 10}))
+	assert.Len(t, readCSV(t, filepath.Join(dir, "event", "0000000025-0000000029.csv")), 4+1)
+	assert.Len(t, readCSV(t, filepath.Join(dir, "event", "0000000030-0000000030.csv")), 1+1)
+
+	state, err = LoadState(dir)
+	require.NoError(t, err)
+	assert.Equal(t, &State{Event: &Position{30, 0}, Transfer: &Position{30, 0}}, state)
+
+	// nothing new
+	require.NoError(t, Export(ctx, logDB, dir, Options{To: 30, Format: CSV, Partition: 10}))
+	entries, err := os.ReadDir(filepath.Join(dir, "event"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 5)
+}
+
+func TestExportFormats(t *testing.T) {
+	var (
+		logDB = logdb.NewKVMem()
+		ctx   = context.Background()
+		from  = uint32(0)
+	)
+	defer logDB.Close()
+	writeBlocks(t, logDB, nil, 3)
+
+	t.Run("ndjson", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, Export(ctx, logDB, dir, Options{From: &from, To: 3, Format: NDJSON, Partition: 100}))
+
+		f, err := os.Open(filepath.Join(dir, "transfer", "0000000000-0000000003.ndjson"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		var rows []transferRow
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var row transferRow
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &row))
+			rows = append(rows, row)
+		}
+		require.NoError(t, scanner.Err())
+		require.Len(t, rows, 3)
+		assert.Equal(t, uint32(3), rows[2].BlockNumber)
+		assert.Equal(t, "2", rows[2].Amount)
+	})
+
+	t.Run("parquet", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, Export(ctx, logDB, dir, Options{From: &from, To: 3, Format: Parquet, Partition: 100}))
+
+		rows, err := parquet.ReadFile[eventRow](filepath.Join(dir, "event", "0000000000-0000000003.parquet"))
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, uint32(2), rows[1].BlockNumber)
+		assert.Equal(t, thor.BytesToBytes32([]byte("topic")).String(), *rows[1].Topic0)
+		assert.Nil(t, rows[1].Topic1)
+	})
+}
+
+func TestExportPruned(t *testing.T) {
+	logDB := logdb.NewKVMem()
+	defer logDB.Close()
+	writeBlocks(t, logDB, nil, 3)
+	require.NoError(t, logDB.Prune(context.Background(), 2, nil))
+
+	assert.EqualError(t, Export(context.Background(), logDB, t.TempDir(), Options{To: 3, Format: CSV, Partition: 10}), "logs before block 2 are pruned")
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/parquet-go/parquet-go"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// Format is the format of output files.
+type Format string
+
+const (
+	CSV     Format = "csv"
+	Parquet Format = "parquet"
+	NDJSON  Format = "ndjson"
+)
+
+// ParseFormat parses the format name.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case CSV, Parquet, NDJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported format '%v'", s)
+	}
+}
+
+// row is a row of an exported table.
+type row interface {
+	csvRecord() []string
+}
+
+// eventRow is the exported form of an event.
+type eventRow struct {
+	BlockNumber uint32  `json:"block_number" parquet:"block_number"`
+	LogIndex    uint32  `json:"log_index" parquet:"log_index"`
+	BlockID     string  `json:"block_id" parquet:"block_id"`
+	BlockTime   uint64  `json:"block_time" parquet:"block_time"`
+	TxID        string  `json:"tx_id" parquet:"tx_id"`
+	TxOrigin    string  `json:"tx_origin" parquet:"tx_origin"`
+	ClauseIndex uint32  `json:"clause_index" parquet:"clause_index"`
+	Address     string  `json:"address" parquet:"address"`
+	Topic0      *string `json:"topic0" parquet:"topic0,optional"`
+	Topic1      *string `json:"topic1" parquet:"topic1,optional"`
+	Topic2      *string `json:"topic2" parquet:"topic2,optional"`
+	Topic3      *string `json:"topic3" parquet:"topic3,optional"`
+	Topic4      *string `json:"topic4" parquet:"topic4,optional"`
+	Data        string  `json:"data" parquet:"data"`
+}
+
+var eventColumns = []string{
+	"block_number", "log_index", "block_id", "block_time", "tx_id", "tx_origin", "clause_index",
+	"address", "topic0", "topic1", "topic2", "topic3", "topic4", "data",
+}
+
+func newEventRow(ev *logdb.Event) eventRow {
+	topic := func(t *thor.Bytes32) *string {
+		if t == nil {
+			return nil
+		}
+		s := t.String()
+		return &s
+	}
+	return eventRow{
+		BlockNumber: ev.BlockNumber,
+		LogIndex:    ev.Index,
+		BlockID:     ev.BlockID.String(),
+		BlockTime:   ev.BlockTime,
+		TxID:        ev.TxID.String(),
+		TxOrigin:    ev.TxOrigin.String(),
+		ClauseIndex: ev.ClauseIndex,
+		Address:     ev.Address.String(),
+		Topic0:      topic(ev.Topics[0]),
+		Topic1:      topic(ev.Topics[1]),
+		Topic2:      topic(ev.Topics[2]),
+		Topic3:      topic(ev.Topics[3]),
+		Topic4:      topic(ev.Topics[4]),
+		Data:        hexutil.Encode(ev.Data),
+	}
+}
+
+func (r eventRow) csvRecord() []string {
+	topic := func(t *string) string {
+		if t == nil {
+			return ""
+		}
+		return *t
+	}
+	return []string{
+		strconv.FormatUint(uint64(r.BlockNumber), 10),
+		strconv.FormatUint(uint64(r.LogIndex), 10),
+		r.BlockID,
+		strconv.FormatUint(r.BlockTime, 10),
+		r.TxID,
+		r.TxOrigin,
+		strconv.FormatUint(uint64(r.ClauseIndex), 10),
+		r.Address,
+		topic(r.Topic0),
+		topic(r.Topic1),
+		topic(r.Topic2),
+		topic(r.Topic3),
+		topic(r.Topic4),
+		r.Data,
+	}
+}
+
+// transferRow is the exported form of a transfer.
+type transferRow struct {
+	BlockNumber uint32 `json:"block_number" parquet:"block_number"`
+	LogIndex    uint32 `json:"log_index" parquet:"log_index"`
+	BlockID     string `json:"block_id" parquet:"block_id"`
+	BlockTime   uint64 `json:"block_time" parquet:"block_time"`
+	TxID        string `json:"tx_id" parquet:"tx_id"`
+	TxOrigin    string `json:"tx_origin" parquet:"tx_origin"`
+	ClauseIndex uint32 `json:"clause_index" parquet:"clause_index"`
+	Sender      string `json:"sender" parquet:"sender"`
+	Recipient   string `json:"recipient" parquet:"recipient"`
+	Amount      string `json:"amount" parquet:"amount"` // in decimal, which may exceed 64 bits
+}
+
+var transferColumns = []string{
+	"block_number", "log_index", "block_id", "block_time", "tx_id", "tx_origin", "clause_index",
+	"sender", "recipient", "amount",
+}
+
+func newTransferRow(tr *logdb.Transfer) transferRow {
+	return transferRow{
+		BlockNumber: tr.BlockNumber,
+		LogIndex:    tr.Index,
+		BlockID:     tr.BlockID.String(),
+		BlockTime:   tr.BlockTime,
+		TxID:        tr.TxID.String(),
+		TxOrigin:    tr.TxOrigin.String(),
+		ClauseIndex: tr.ClauseIndex,
+		Sender:      tr.Sender.String(),
+		Recipient:   tr.Recipient.String(),
+		Amount:      tr.Amount.String(),
+	}
+}
+
+func (r transferRow) csvRecord() []string {
+	return []string{
+		strconv.FormatUint(uint64(r.BlockNumber), 10),
+		strconv.FormatUint(uint64(r.LogIndex), 10),
+		r.BlockID,
+		strconv.FormatUint(r.BlockTime, 10),
+		r.TxID,
+		r.TxOrigin,
+		strconv.FormatUint(uint64(r.ClauseIndex), 10),
+		r.Sender,
+		r.Recipient,
+		r.Amount,
+	}
+}
+
+// rowWriter writes rows in a format.
+type rowWriter[R row] interface {
+	Write(rows []R) error
+	// Close flushes buffered rows, the underlying writer is not closed.
+	Close() error
+}
+
+func newRowWriter[R row](w io.Writer, format Format, columns []string) (rowWriter[R], error) {
+	switch format {
+	case CSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return nil, err
+		}
+		return &csvWriter[R]{w: cw}, nil
+	case NDJSON:
+		return &ndjsonWriter[R]{enc: json.NewEncoder(w)}, nil
+	case Parquet:
+		return &parquetWriter[R]{w: parquet.NewGenericWriter[R](w)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format '%v'", format)
+	}
+}
+
+type csvWriter[R row] struct {
+	w *csv.Writer
+}
+
+func (w *csvWriter[R]) Write(rows []R) error {
+	for _, r := range rows {
+		if err := w.w.Write(r.csvRecord()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *csvWriter[R]) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type ndjsonWriter[R row] struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonWriter[R]) Write(rows []R) error {
+	for _, r := range rows {
+		if err := w.enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *ndjsonWriter[R]) Close() error {
+	return nil
+}
+
+type parquetWriter[R row] struct {
+	w *parquet.GenericWriter[R]
+}
+
+func (w *parquetWriter[R]) Write(rows []R) error {
+	_, err := w.w.Write(rows)
+	return err
+}
+
+func (w *parquetWriter[R]) Close() error {
+	return w.w.Close()
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logpruner
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/co"
+	"github.com/vechain/thor/v2/log"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/thor"
+)
+
+var logger = log.WithContext("pkg", "logpruner")
+
+// period is the number of blocks between pruning.
+const period = 1000
+
+// Policy is the retention policy of logs. Logs are kept if within either of the limits.
+type Policy struct {
+	Blocks uint32         // keep logs of the latest blocks, 0 to ignore
+	Days   uint32         // keep logs of the latest days, 0 to ignore
+	Keep   []thor.Address // keep events emitted by and transfers from or to the addresses indefinitely
+}
+
+// Enabled returns whether any of the limits is set.
+func (p *Policy) Enabled() bool {
+	return p.Blocks > 0 || p.Days > 0
+}
+
+// Pruner is a background task to prune logs out of the retention policy.
+type Pruner struct {
+	logDB  logdb.LogStore
+	repo   *chain.Repository
+	policy Policy
+	ctx    context.Context
+	cancel func()
+	goes   co.Goes
+}
+
+// New creates and starts the pruner.
+func New(logDB logdb.LogStore, repo *chain.Repository, policy Policy) *Pruner {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pruner{
+		logDB:  logDB,
+		repo:   repo,
+		policy: policy,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	p.goes.Go(func() {
+		if err := p.loop(); err != nil {
+			if err != context.Canceled && errors.Cause(err) != context.Canceled {
+				logger.Warn("log pruner interrupted", "error", err)
+			}
+		}
+	})
+	return p
+}
+
+// Stop stops the pruner.
+func (p *Pruner) Stop() {
+	p.cancel()
+	p.goes.Wait()
+}
+
+// loop is the main loop.
+func (p *Pruner) loop() error {
+	logger.Info("log pruner started")
+
+	var (
+		ticker = p.repo.NewTicker()
+		last   *uint32 // the best block number when last pruned
+	)
+	for {
+		best := p.repo.BestBlockSummary().Header
+		if last == nil || best.Number() >= *last+period {
+			target, err := p.target(best)
+			if err != nil {
+				return errors.Wrap(err, "target")
+			}
+			if status := p.logDB.PruneStatus(); target > status.BlockNum || status.Deleted < status.BlockNum {
+				startTime := time.Now()
+				if err := p.logDB.Prune(p.ctx, target, p.policy.Keep); err != nil {
+					return errors.Wrap(err, "prune")
+				}
+				logger.Info("logs pruned", "before", p.logDB.PruneStatus().BlockNum, "elapsed", time.Since(startTime).Round(time.Millisecond))
+			}
+			n := best.Number()
+			last = &n
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return p.ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
+
+// target returns the block number, logs before which are out of the retention.
+func (p *Pruner) target(best *block.Header) (uint32, error) {
+	var target uint32
+	if p.policy.Blocks > 0 && best.Number() >= p.policy.Blocks {
+		target = best.Number() - p.policy.Blocks + 1
+	}
+	if p.policy.Days > 0 {
+		ts := best.Timestamp() - min(best.Timestamp(), uint64(p.policy.Days)*24*3600)
+		header, err := p.repo.NewBestChain().FindBlockHeaderByTimestamp(ts, 1)
+		if err != nil {
+			return 0, err
+		}
+		// kept if within either of the limits
+		if p.policy.Blocks == 0 || header.Number() < target {
+			target = header.Number()
+		}
+	}
+	return target, nil
+}
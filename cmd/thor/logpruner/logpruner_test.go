@@ -0,0 +1,105 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logpruner
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+// newChain creates a chain of n blocks after genesis, one per hour, with an event in each block written to the log db.
+func newChain(t *testing.T, n int, logDB logdb.LogStore) *chain.Repository {
+	db := muxdb.NewMem()
+	b0, _, _, err := genesis.NewDevnet().Build(state.NewStater(db))
+	require.NoError(t, err)
+	repo, err := chain.NewRepository(db, b0)
+	require.NoError(t, err)
+
+	key, _ := crypto.GenerateKey()
+	w := logDB.NewWriter()
+	parent := b0
+	for i := 1; i <= n; i++ {
+		trx := new(tx.Builder).ChainTag(repo.ChainTag()).Build()
+		sig, _ := crypto.Sign(trx.SigningHash().Bytes(), key)
+		trx = trx.WithSignature(sig)
+
+		b := new(block.Builder).
+			ParentID(parent.Header().ID()).
+			Timestamp(b0.Header().Timestamp() + uint64(i)*3600).
+			TotalScore(uint64(i)).
+			Transaction(trx).
+			Build()
+		sig, _ = crypto.Sign(b.Header().SigningHash().Bytes(), key)
+		b = b.WithSignature(sig)
+
+		receipts := tx.Receipts{{Outputs: []*tx.Output{{
+			Events:    tx.Events{{Address: thor.BytesToAddress([]byte("contract"))}},
+			Transfers: tx.Transfers{{Sender: thor.BytesToAddress([]byte("sender")), Amount: big.NewInt(1)}},
+		}}}}
+		require.NoError(t, repo.AddBlock(b, receipts, 0))
+		require.NoError(t, w.Write(b, receipts))
+		parent = b
+	}
+	require.NoError(t, w.Commit())
+	require.NoError(t, repo.SetBestBlockID(parent.Header().ID()))
+	return repo
+}
+
+func TestTarget(t *testing.T) {
+	repo := newChain(t, 100, logdb.NewKVMem())
+	best := repo.BestBlockSummary().Header
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   uint32
+	}{
+		{"blocks", Policy{Blocks: 10}, 91},
+		{"days", Policy{Days: 1}, 76},
+		{"either blocks or days", Policy{Blocks: 10, Days: 1}, 76},
+		{"either days or blocks", Policy{Blocks: 30, Days: 1}, 71},
+		{"more blocks than the chain", Policy{Blocks: 200}, 0},
+		{"more days than the chain", Policy{Days: 10}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Pruner{repo: repo, policy: tt.policy}
+			target, err := p.target(best)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, target)
+		})
+	}
+}
+
+func TestPruner(t *testing.T) {
+	logDB := logdb.NewKVMem()
+	repo := newChain(t, 100, logDB)
+
+	p := New(logDB, repo, Policy{Blocks: 10})
+	defer p.Stop()
+
+	assert.Eventually(t, func() bool {
+		return logDB.PruneStatus().Deleted == 91
+	}, 5*time.Second, 10*time.Millisecond)
+
+	events, err := logDB.FilterEvents(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, events, 10)
+}
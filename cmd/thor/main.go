@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -21,6 +22,9 @@ import (
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/v2/api"
 	"github.com/vechain/thor/v2/bft"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/cmd/thor/logexport"
 	"github.com/vechain/thor/v2/cmd/thor/node"
 	"github.com/vechain/thor/v2/cmd/thor/optimizer"
 	"github.com/vechain/thor/v2/cmd/thor/solo"
@@ -32,6 +36,7 @@ import (
 	"github.com/vechain/thor/v2/schedule"
 	"github.com/vechain/thor/v2/state"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
 	"github.com/vechain/thor/v2/txpool"
 	"gopkg.in/urfave/cli.v1"
 
@@ -83,6 +88,15 @@ func main() {
 			apiAllowCustomTracerFlag,
 			enableAPILogsFlag,
 			apiLogsLimitFlag,
+			apiLogsMaxSetSizeFlag,
+			apiReadyMaxHeadAgeFlag,
+			apiReadyMinPeersFlag,
+			apiReadyMaxLogsLagFlag,
+			apiKeysFileFlag,
+			apiKeysRequiredFlag,
+			apiRateLimitFlag,
+			apiRateLimitExpensiveFlag,
+			apiCacheSizeFlag,
 			verbosityFlag,
 			jsonLogsFlag,
 			maxPeersFlag,
@@ -91,6 +105,12 @@ func main() {
 			bootNodeFlag,
 			allowedPeersFlag,
 			skipLogsFlag,
+			logDBBackendFlag,
+			logsRetentionBlocksFlag,
+			logsRetentionDaysFlag,
+			logsKeepAddressesFlag,
+			logsFailuresFlag,
+			logsTokenTransfersFlag,
 			pprofFlag,
 			verifyLogsFlag,
 			disablePrunerFlag,
@@ -118,6 +138,14 @@ func main() {
 					apiAllowCustomTracerFlag,
 					enableAPILogsFlag,
 					apiLogsLimitFlag,
+					apiLogsMaxSetSizeFlag,
+					apiReadyMaxHeadAgeFlag,
+					apiReadyMaxLogsLagFlag,
+					apiKeysFileFlag,
+					apiKeysRequiredFlag,
+					apiRateLimitFlag,
+					apiRateLimitExpensiveFlag,
+					apiCacheSizeFlag,
 					onDemandFlag,
 					blockInterval,
 					persistFlag,
@@ -127,6 +155,12 @@ func main() {
 					pprofFlag,
 					verifyLogsFlag,
 					skipLogsFlag,
+					logDBBackendFlag,
+					logsRetentionBlocksFlag,
+					logsRetentionDaysFlag,
+					logsKeepAddressesFlag,
+					logsFailuresFlag,
+					logsTokenTransfersFlag,
 					txPoolLimitFlag,
 					txPoolLimitPerAccountFlag,
 					disablePrunerFlag,
@@ -148,6 +182,44 @@ func main() {
 				},
 				Action: masterKeyAction,
 			},
+			{
+				Name:  "logdb",
+				Usage: "log db management",
+				Subcommands: []cli.Command{
+					{
+						Name:  "rebuild",
+						Usage: "rebuild log db from chain data, resuming the interrupted rebuild if any",
+						Flags: []cli.Flag{
+							networkFlag,
+							dataDirFlag,
+							cacheFlag,
+							disablePrunerFlag,
+							logDBBackendFlag,
+							logsFailuresFlag,
+							logsTokenTransfersFlag,
+							rebuildReadersFlag,
+							rebuildVerifyFlag,
+						},
+						Action: rebuildLogDBAction,
+					},
+					{
+						Name:  "export",
+						Usage: "export event and transfer logs to files partitioned by block range",
+						Flags: []cli.Flag{
+							networkFlag,
+							dataDirFlag,
+							disablePrunerFlag,
+							logDBBackendFlag,
+							exportFromFlag,
+							exportToFlag,
+							exportFormatFlag,
+							exportOutFlag,
+							exportPartitionFlag,
+						},
+						Action: exportLogDBAction,
+					},
+				},
+			},
 		},
 	}
 
@@ -223,7 +295,7 @@ func defaultAction(ctx *cli.Context) error {
 	}
 	defer func() { log.Info("closing main database..."); mainDB.Close() }()
 
-	logDB, err := openLogDB(instanceDir)
+	logDB, err := openLogDB(ctx, instanceDir)
 	if err != nil {
 		return err
 	}
@@ -266,6 +338,27 @@ func defaultAction(ctx *cli.Context) error {
 		return errors.Wrap(err, "init bft engine")
 	}
 
+	optimizer := optimizer.New(mainDB, repo, !ctx.Bool(disablePrunerFlag.Name))
+	defer func() { log.Info("stopping optimizer..."); optimizer.Stop() }()
+
+	if !skipLogs {
+		logPruner, err := startLogPruner(ctx, logDB, repo)
+		if err != nil {
+			return err
+		}
+		if logPruner != nil {
+			defer func() { log.Info("stopping log pruner..."); logPruner.Stop() }()
+		}
+	}
+
+	rateLimiter, err := newRateLimiter(ctx)
+	if err != nil {
+		return err
+	}
+	if rateLimiter != nil {
+		defer rateLimiter.Close()
+	}
+
 	apiHandler, apiCloser := api.New(
 		repo,
 		state.NewStater(mainDB),
@@ -274,17 +367,25 @@ func defaultAction(ctx *cli.Context) error {
 		logDB,
 		bftEngine,
 		p2pCommunicator.Communicator(),
+		optimizer,
 		forkConfig,
+		fullVersion(),
 		ctx.String(apiCorsFlag.Name),
 		uint32(ctx.Uint64(apiBacktraceLimitFlag.Name)),
 		ctx.Uint64(apiCallGasLimitFlag.Name),
 		ctx.Bool(pprofFlag.Name),
 		skipLogs,
+		ctx.Bool(logsFailuresFlag.Name),
+		ctx.Bool(logsTokenTransfersFlag.Name),
 		ctx.Bool(apiAllowCustomTracerFlag.Name),
 		ctx.Bool(enableAPILogsFlag.Name),
 		ctx.Bool(enableMetricsFlag.Name),
 		ctx.Uint64(apiLogsLimitFlag.Name),
+		ctx.Uint64(apiLogsMaxSetSizeFlag.Name),
 		parseTracerList(strings.TrimSpace(ctx.String(allowedTracersFlag.Name))),
+		readinessOptions(ctx),
+		rateLimiter,
+		int(ctx.Uint64(apiCacheSizeFlag.Name))*1024*1024,
 		false,
 	)
 	defer func() { log.Info("closing API..."); apiCloser() }()
@@ -302,9 +403,6 @@ func defaultAction(ctx *cli.Context) error {
 	}
 	defer p2pCommunicator.Stop()
 
-	optimizer := optimizer.New(mainDB, repo, !ctx.Bool(disablePrunerFlag.Name))
-	defer func() { log.Info("stopping optimizer..."); optimizer.Stop() }()
-
 	return node.New(
 		master,
 		repo,
@@ -369,7 +467,7 @@ func soloAction(ctx *cli.Context) error {
 	}
 
 	var mainDB *muxdb.MuxDB
-	var logDB *logdb.LogDB
+	var logDB logdb.LogStore
 	var instanceDir string
 
 	if ctx.Bool(persistFlag.Name) {
@@ -381,14 +479,16 @@ func soloAction(ctx *cli.Context) error {
 		}
 		defer func() { log.Info("closing main database..."); mainDB.Close() }()
 
-		if logDB, err = openLogDB(instanceDir); err != nil {
+		if logDB, err = openLogDB(ctx, instanceDir); err != nil {
 			return err
 		}
 		defer func() { log.Info("closing log database..."); logDB.Close() }()
 	} else {
 		instanceDir = "Memory"
 		mainDB = openMemMainDB()
-		logDB = openMemLogDB()
+		if logDB, err = openMemLogDB(ctx); err != nil {
+			return err
+		}
 	}
 
 	repo, err := initChainRepository(gene, mainDB, logDB)
@@ -420,6 +520,27 @@ func soloAction(ctx *cli.Context) error {
 
 	bftEngine := solo.NewBFTEngine(repo)
 	schedule := initSchedule()
+	optimizer := optimizer.New(mainDB, repo, !ctx.Bool(disablePrunerFlag.Name))
+	defer func() { log.Info("stopping optimizer..."); optimizer.Stop() }()
+
+	if !skipLogs {
+		logPruner, err := startLogPruner(ctx, logDB, repo)
+		if err != nil {
+			return err
+		}
+		if logPruner != nil {
+			defer func() { log.Info("stopping log pruner..."); logPruner.Stop() }()
+		}
+	}
+
+	rateLimiter, err := newRateLimiter(ctx)
+	if err != nil {
+		return err
+	}
+	if rateLimiter != nil {
+		defer rateLimiter.Close()
+	}
+
 	apiHandler, apiCloser := api.New(
 		repo,
 		state.NewStater(mainDB),
@@ -428,17 +549,25 @@ func soloAction(ctx *cli.Context) error {
 		logDB,
 		bftEngine,
 		&solo.Communicator{},
+		optimizer,
 		forkConfig,
+		fullVersion(),
 		ctx.String(apiCorsFlag.Name),
 		uint32(ctx.Uint64(apiBacktraceLimitFlag.Name)),
 		ctx.Uint64(apiCallGasLimitFlag.Name),
 		ctx.Bool(pprofFlag.Name),
 		skipLogs,
+		ctx.Bool(logsFailuresFlag.Name),
+		ctx.Bool(logsTokenTransfersFlag.Name),
 		ctx.Bool(apiAllowCustomTracerFlag.Name),
 		ctx.Bool(enableAPILogsFlag.Name),
 		ctx.Bool(enableMetricsFlag.Name),
 		ctx.Uint64(apiLogsLimitFlag.Name),
+		ctx.Uint64(apiLogsMaxSetSizeFlag.Name),
 		parseTracerList(strings.TrimSpace(ctx.String(allowedTracersFlag.Name))),
+		readinessOptions(ctx),
+		rateLimiter,
+		int(ctx.Uint64(apiCacheSizeFlag.Name))*1024*1024,
 		true,
 	)
 	defer func() { log.Info("closing API..."); apiCloser() }()
@@ -459,9 +588,6 @@ func soloAction(ctx *cli.Context) error {
 
 	printStartupMessage2(gene, apiURL, "", metricsURL, adminURL)
 
-	optimizer := optimizer.New(mainDB, repo, !ctx.Bool(disablePrunerFlag.Name))
-	defer func() { log.Info("stopping optimizer..."); optimizer.Stop() }()
-
 	return solo.New(repo,
 		state.NewStater(mainDB),
 		logDB,
@@ -562,3 +688,120 @@ func masterKeyAction(ctx *cli.Context) error {
 	}
 	return nil
 }
+
+func rebuildLogDBAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+
+	readers := ctx.Int(rebuildReadersFlag.Name)
+	if readers < 1 {
+		return fmt.Errorf("invalid value %d for flag %v", readers, rebuildReadersFlag.Name)
+	}
+
+	gene, _, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := makeInstanceDir(ctx, gene)
+	if err != nil {
+		return err
+	}
+
+	mainDB, err := openMainDB(ctx, instanceDir)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Info("closing main database..."); mainDB.Close() }()
+
+	logDB, err := openLogDB(ctx, instanceDir)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Info("closing log database..."); logDB.Close() }()
+
+	genesisBlock, genesisEvents, genesisTransfers, err := gene.Build(state.NewStater(mainDB))
+	if err != nil {
+		return errors.Wrap(err, "build genesis block")
+	}
+	repo, err := chain.NewRepository(mainDB, genesisBlock)
+	if err != nil {
+		return errors.Wrap(err, "initialize block chain")
+	}
+	genesisLogs := logdb.NewBlockLogs(genesisBlock, tx.Receipts{{
+		Outputs: []*tx.Output{
+			{Events: genesisEvents, Transfers: genesisTransfers},
+		},
+	}})
+
+	if err := rebuildLogDB(exitSignal, repo, logDB, genesisLogs, readers); err != nil {
+		return err
+	}
+	if ctx.Bool(rebuildVerifyFlag.Name) {
+		return verifyLogDB(exitSignal, repo.BestBlockSummary().Header.Number(), repo, logDB)
+	}
+	return nil
+}
+
+func exportLogDBAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+
+	outDir := ctx.String(exportOutFlag.Name)
+	if outDir == "" {
+		return fmt.Errorf("flag %v not specified", exportOutFlag.Name)
+	}
+	format, err := logexport.ParseFormat(ctx.String(exportFormatFlag.Name))
+	if err != nil {
+		return err
+	}
+	partition := ctx.Uint64(exportPartitionFlag.Name)
+	if partition == 0 || partition > math.MaxUint32 {
+		return fmt.Errorf("invalid value %d for flag %v", partition, exportPartitionFlag.Name)
+	}
+	opts := logexport.Options{Format: format, Partition: uint32(partition)}
+	if ctx.IsSet(exportFromFlag.Name) {
+		from := ctx.Uint64(exportFromFlag.Name)
+		if from > math.MaxUint32 {
+			return fmt.Errorf("invalid value %d for flag %v", from, exportFromFlag.Name)
+		}
+		opts.From = new(uint32)
+		*opts.From = uint32(from)
+	}
+
+	gene, _, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := makeInstanceDir(ctx, gene)
+	if err != nil {
+		return err
+	}
+	logDB, err := openLogDB(ctx, instanceDir)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Info("closing log database..."); logDB.Close() }()
+
+	if rebuilding, err := logDB.Rebuilding(); err != nil {
+		return err
+	} else if rebuilding {
+		return errLogDBRebuilding
+	}
+
+	if ctx.IsSet(exportToFlag.Name) {
+		to := ctx.Uint64(exportToFlag.Name)
+		if to > math.MaxUint32 {
+			return fmt.Errorf("invalid value %d for flag %v", to, exportToFlag.Name)
+		}
+		opts.To = uint32(to)
+	} else {
+		newestID, err := logDB.NewestBlockID()
+		if err != nil {
+			return err
+		}
+		opts.To = block.Number(newestID)
+	}
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return errors.Wrapf(err, "create output dir [%v]", outDir)
+	}
+	return logexport.Export(exitSignal, logDB, outDir, opts)
+}
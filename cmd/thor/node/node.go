@@ -51,7 +51,7 @@ type Node struct {
 	master         *Master
 	repo           *chain.Repository
 	bft            *bft.Engine
-	logDB          *logdb.LogDB
+	logDB          logdb.LogStore
 	txPool         *txpool.TxPool
 	txStashPath    string
 	comm           *comm.Communicator
@@ -71,7 +71,7 @@ func New(
 	repo *chain.Repository,
 	bft *bft.Engine,
 	stater *state.Stater,
-	logDB *logdb.LogDB,
+	logDB logdb.LogStore,
 	txPool *txpool.TxPool,
 	txStashPath string,
 	comm *comm.Communicator,
@@ -258,8 +258,8 @@ func (n *Node) txStashLoop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case txEv := <-txCh:
-			// skip executables
-			if txEv.Executable != nil && *txEv.Executable {
+			// skip executables, evicted and included
+			if (txEv.Executable != nil && *txEv.Executable) || txEv.Evicted || txEv.Included {
 				continue
 			}
 			// only stash non-executable txs
@@ -424,7 +424,7 @@ func (n *Node) processBlock(newBlock *block.Block, stats *blockStats) (bool, err
 }
 
 func (n *Node) writeLogs(newBlock *block.Block, newReceipts tx.Receipts, oldBestBlockID thor.Bytes32) (err error) {
-	var w *logdb.Writer
+	var w logdb.Writer
 	if int64(newBlock.Header().Timestamp()) < time.Now().Unix()-24*3600 {
 		// turn off log sync to quickly catch up
 		w = n.logDB.NewWriterSyncOff()
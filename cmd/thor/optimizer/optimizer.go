@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/rlp"
@@ -32,11 +33,13 @@ const (
 
 // Optimizer is a background task to optimize tries.
 type Optimizer struct {
-	db     *muxdb.MuxDB
-	repo   *chain.Repository
-	ctx    context.Context
-	cancel func()
-	goes   co.Goes
+	db        *muxdb.MuxDB
+	repo      *chain.Repository
+	ctx       context.Context
+	cancel    func()
+	goes      co.Goes
+	base      atomic.Uint32
+	pruneBase atomic.Uint32
 }
 
 // New creates and starts the optimizer.
@@ -64,6 +67,11 @@ func (p *Optimizer) Stop() {
 	p.goes.Wait()
 }
 
+// Progress returns the block numbers up to which tries are optimized and pruned.
+func (p *Optimizer) Progress() (base, pruneBase uint32) {
+	return p.base.Load(), p.pruneBase.Load()
+}
+
 // loop is the main loop.
 func (p *Optimizer) loop(prune bool) error {
 	logger.Info("optimizer started")
@@ -82,6 +90,8 @@ func (p *Optimizer) loop(prune bool) error {
 	if err := status.Load(propsStore); err != nil {
 		return errors.Wrap(err, "load status")
 	}
+	p.base.Store(status.Base)
+	p.pruneBase.Store(status.PruneBase)
 
 	for {
 		// select target
@@ -119,6 +129,8 @@ func (p *Optimizer) loop(prune bool) error {
 		if err := status.Save(propsStore); err != nil {
 			return errors.Wrap(err, "save status")
 		}
+		p.base.Store(status.Base)
+		p.pruneBase.Store(status.PruneBase)
 	}
 }
 
@@ -14,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -113,6 +114,25 @@ func TestNewOptimizer(t *testing.T) {
 	op.Stop()
 }
 
+func TestOptimizerProgress(t *testing.T) {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	gene := genesis.NewDevnet()
+	b0, _, _, _ := gene.Build(stater)
+	repo, _ := chain.NewRepository(db, b0)
+
+	assert.Nil(t, (&status{Base: 2000, PruneBase: 1000}).Save(db.NewStore(propsStoreName)))
+
+	op := New(db, repo, true)
+	defer op.Stop()
+
+	// the saved status is loaded
+	assert.Eventually(t, func() bool {
+		base, pruneBase := op.Progress()
+		return base == 2000 && pruneBase == 1000
+	}, time.Second, 10*time.Millisecond)
+}
+
 func newTempFileDB() (*muxdb.MuxDB, func() error, error) {
 	dir := os.TempDir()
 
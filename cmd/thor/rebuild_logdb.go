@@ -0,0 +1,154 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/co"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/thor"
+	"gopkg.in/cheggaaa/pb.v1"
+)
+
+// rebuildSegmentSize is the number of blocks read by a reader at a time, and written in a batch.
+const rebuildSegmentSize = 256
+
+// rebuildSegment is a range of blocks, of which logs are read by a reader.
+type rebuildSegment struct {
+	from, to uint32
+	logs     []*logdb.BlockLogs
+	err      error
+	done     chan struct{}
+}
+
+// rebuildLogDB rebuilds the log db from chain data, with blocks read by concurrent readers and logs written in order.
+// Logs of the genesis block are not in chain data, so given by genesisLogs.
+func rebuildLogDB(ctx context.Context, repo *chain.Repository, logDB logdb.LogStore, genesisLogs *logdb.BlockLogs, readers int) error {
+	rb, err := logDB.Rebuild()
+	if err != nil {
+		return errors.Wrap(err, "start rebuild")
+	}
+
+	var (
+		best    = repo.BestBlockSummary().Header
+		bestNum = best.Number()
+		next    = rb.Next()
+	)
+	if next == 0 {
+		fmt.Println(">> Rebuilding log db <<")
+	} else {
+		fmt.Println(">> Resuming log db rebuild <<")
+	}
+
+	if next <= bestNum {
+		pb := pb.New64(int64(bestNum) + 1).
+			Set64(int64(next)).
+			SetMaxWidth(90).
+			Start()
+		defer func() { pb.NotPrint = true }()
+
+		var (
+			goes     co.Goes
+			cancel   func()
+			jobs     = make(chan *rebuildSegment, readers)
+			segments = make(chan *rebuildSegment, readers*2) // in order of blocks
+		)
+		ctx, cancel = context.WithCancel(ctx)
+		defer goes.Wait()
+		defer cancel()
+
+		goes.Go(func() {
+			defer close(jobs)
+			defer close(segments)
+			for from := uint64(next); from <= uint64(bestNum); from += rebuildSegmentSize {
+				seg := &rebuildSegment{
+					from: uint32(from),
+					to:   uint32(min(from+rebuildSegmentSize-1, uint64(bestNum))),
+					done: make(chan struct{}),
+				}
+				select {
+				case jobs <- seg:
+				case <-ctx.Done():
+					return
+				}
+				select {
+				case segments <- seg:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+		for i := 0; i < readers; i++ {
+			goes.Go(func() {
+				readRebuildSegments(ctx, repo, best.ID(), genesisLogs, jobs)
+			})
+		}
+
+		for seg := range segments {
+			select {
+			case <-seg.done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			if seg.err != nil {
+				return seg.err
+			}
+			if err := rb.Write(seg.logs); err != nil {
+				return errors.Wrap(err, "write logs")
+			}
+			pb.Add64(int64(len(seg.logs)))
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		pb.Finish()
+	}
+
+	fmt.Println(">> Creating log db indexes <<")
+	return rb.Finish()
+}
+
+// readRebuildSegments reads logs of blocks in segments taken from jobs.
+func readRebuildSegments(ctx context.Context, repo *chain.Repository, headID thor.Bytes32, genesisLogs *logdb.BlockLogs, jobs <-chan *rebuildSegment) {
+	var (
+		chain = repo.NewChain(headID)
+		n     int
+	)
+	for seg := range jobs {
+		seg.logs, seg.err = func() ([]*logdb.BlockLogs, error) {
+			logs := make([]*logdb.BlockLogs, 0, seg.to-seg.from+1)
+			for i := seg.from; i <= seg.to; i++ {
+				if i == 0 {
+					logs = append(logs, genesisLogs)
+					continue
+				}
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				b, err := chain.GetBlock(i)
+				if err != nil {
+					return nil, err
+				}
+				receipts, err := repo.GetBlockReceipts(b.Header().ID())
+				if err != nil {
+					return nil, err
+				}
+				logs = append(logs, logdb.NewBlockLogs(b, receipts))
+			}
+			return logs, nil
+		}()
+		close(seg.done)
+
+		// recreate the chain to avoid the internal trie holds too many nodes.
+		if n++; n%40 == 0 {
+			chain = repo.NewChain(headID)
+		}
+	}
+}
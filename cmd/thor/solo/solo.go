@@ -46,7 +46,7 @@ type Solo struct {
 	txPool        *txpool.TxPool
 	schedule      *schedule.Schedule
 	packer        *packer.Packer
-	logDB         *logdb.LogDB
+	logDB         logdb.LogStore
 	gasLimit      uint64
 	bandwidth     bandwidth.Bandwidth
 	blockInterval uint64
@@ -58,7 +58,7 @@ type Solo struct {
 func New(
 	repo *chain.Repository,
 	stater *state.Stater,
-	logDB *logdb.LogDB,
+	logDB logdb.LogStore,
 	txPool *txpool.TxPool,
 	schedule *schedule.Schedule,
 	gasLimit uint64,
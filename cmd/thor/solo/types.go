@@ -20,6 +20,17 @@ func (comm *Communicator) PeersStats() []*comm.PeerStats {
 	return nil
 }
 
+// Synced returns a closed channel since solo is always synced.
+func (comm *Communicator) Synced() <-chan struct{} {
+	return syncedCh
+}
+
+var syncedCh = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
 // BFTEngine is a fake bft engine for solo.
 type BFTEngine struct {
 	finalized thor.Bytes32
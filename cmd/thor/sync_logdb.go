@@ -22,7 +22,15 @@ import (
 	"gopkg.in/cheggaaa/pb.v1"
 )
 
-func syncLogDB(ctx context.Context, repo *chain.Repository, logDB *logdb.LogDB, verify bool) error {
+var errLogDBRebuilding = errors.New("log db rebuild unfinished, resume it by 'thor logdb rebuild'")
+
+func syncLogDB(ctx context.Context, repo *chain.Repository, logDB logdb.LogStore, verify bool) error {
+	if rebuilding, err := logDB.Rebuilding(); err != nil {
+		return errors.Wrap(err, "check log db rebuild")
+	} else if rebuilding {
+		return errLogDBRebuilding
+	}
+
 	startPos, err := seekLogDBSyncPosition(repo, logDB)
 	if err != nil {
 		return errors.Wrap(err, "seek log db sync position")
@@ -107,7 +115,7 @@ func syncLogDB(ctx context.Context, repo *chain.Repository, logDB *logdb.LogDB,
 	return pumpErr
 }
 
-func seekLogDBSyncPosition(repo *chain.Repository, logDB *logdb.LogDB) (uint32, error) {
+func seekLogDBSyncPosition(repo *chain.Repository, logDB logdb.LogStore) (uint32, error) {
 	best := repo.BestBlockSummary().Header
 	if best.Number() == 0 {
 		return 0, nil
@@ -154,10 +162,16 @@ func seekLogDBSyncPosition(repo *chain.Repository, logDB *logdb.LogDB) (uint32,
 	return block.Number(header.ID()) + 1, nil
 }
 
-func verifyLogDB(ctx context.Context, endBlockNum uint32, repo *chain.Repository, logDB *logdb.LogDB) error {
+func verifyLogDB(ctx context.Context, endBlockNum uint32, repo *chain.Repository, logDB logdb.LogStore) error {
+	// logs of pruned blocks are incomplete, so the verification starts from the pruning point
+	startBlockNum := max(logDB.PruneStatus().BlockNum, 1)
+	if startBlockNum > endBlockNum {
+		return nil
+	}
+
 	fmt.Println(">> Verifying log db <<")
 	pb := pb.New64(int64(endBlockNum)).
-		Set64(0).
+		Set64(int64(startBlockNum - 1)).
 		SetMaxWidth(90).
 		Start()
 	defer func() { pb.NotPrint = true }()
@@ -168,7 +182,7 @@ func verifyLogDB(ctx context.Context, endBlockNum uint32, repo *chain.Repository
 		best        = repo.BestBlockSummary()
 		evLogs      []*logdb.Event
 		trLogs      []*logdb.Transfer
-		logLimit    = uint32(0)
+		logLimit    = startBlockNum - 1
 		splitEvLogs = func(id thor.Bytes32) (logs []*logdb.Event) {
 			if len(evLogs) == 0 {
 				return
@@ -216,7 +230,7 @@ func verifyLogDB(ctx context.Context, endBlockNum uint32, repo *chain.Repository
 	defer goes.Wait()
 	goes.Go(func() {
 		defer close(ch)
-		pumpErr = pumpBlockAndReceipts(ctx, repo, best.Header.ID(), 1, endBlockNum, ch)
+		pumpErr = pumpBlockAndReceipts(ctx, repo, best.Header.ID(), startBlockNum, endBlockNum, ch)
 	})
 
 	defer cancel()
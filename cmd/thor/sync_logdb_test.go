@@ -0,0 +1,65 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+func TestVerifyPrunedLogDB(t *testing.T) {
+	db := muxdb.NewMem()
+	gene, _, _, err := genesis.NewDevnet().Build(state.NewStater(db))
+	require.NoError(t, err)
+	repo, err := chain.NewRepository(db, gene)
+	require.NoError(t, err)
+
+	logDB, err := logdb.NewMem()
+	require.NoError(t, err)
+	defer logDB.Close()
+
+	// blocks with a transfer each
+	w := logDB.NewWriter()
+	parentID := gene.Header().ID()
+	for i := 0; i < 5; i++ {
+		trx := new(tx.Builder).ChainTag(repo.ChainTag()).Nonce(uint64(i)).Build()
+		sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+		require.NoError(t, err)
+		trx = trx.WithSignature(sig)
+
+		blk := new(block.Builder).ParentID(parentID).Transaction(trx).Build()
+		receipts := tx.Receipts{{Outputs: []*tx.Output{{Transfers: tx.Transfers{{
+			Sender:    genesis.DevAccounts()[0].Address,
+			Recipient: thor.BytesToAddress([]byte("recipient")),
+			Amount:    big.NewInt(1),
+		}}}}}}
+		require.NoError(t, repo.AddBlock(blk, receipts, 0))
+		require.NoError(t, w.Write(blk, receipts))
+		parentID = blk.Header().ID()
+	}
+	require.NoError(t, w.Commit())
+	require.NoError(t, repo.SetBestBlockID(parentID))
+
+	assert.NoError(t, verifyLogDB(context.Background(), 5, repo, logDB))
+
+	// pruned blocks are not verified
+	require.NoError(t, logDB.Prune(context.Background(), 3, nil))
+	assert.NoError(t, verifyLogDB(context.Background(), 5, repo, logDB))
+	assert.NoError(t, verifyLogDB(context.Background(), 2, repo, logDB))
+}
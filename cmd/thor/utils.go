@@ -37,8 +37,11 @@ import (
 	"github.com/mattn/go-isatty"
 	"github.com/mattn/go-tty"
 	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/api"
 	"github.com/vechain/thor/v2/api/doc"
+	"github.com/vechain/thor/v2/api/health"
 	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/cmd/thor/logpruner"
 	"github.com/vechain/thor/v2/cmd/thor/node"
 	"github.com/vechain/thor/v2/cmd/thor/p2p"
 	"github.com/vechain/thor/v2/co"
@@ -387,16 +390,57 @@ func suggestFDCache() int {
 	return n
 }
 
-func openLogDB(dir string) (*logdb.LogDB, error) {
-	path := filepath.Join(dir, "logs.db")
-	db, err := logdb.New(path)
+func openLogDB(ctx *cli.Context, dir string) (logdb.LogStore, error) {
+	var (
+		path string
+		db   logdb.LogStore
+		err  error
+	)
+	switch backend := ctx.String(logDBBackendFlag.Name); backend {
+	case "sqlite":
+		path = filepath.Join(dir, "logs.db")
+		db, err = logdb.New(path)
+	case "kv":
+		path = filepath.Join(dir, "logs.kvdb")
+		db, err = logdb.NewKV(path)
+	default:
+		return nil, fmt.Errorf("unrecognized value '%v' for flag %v", backend, logDBBackendFlag.Name)
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "open log database [%v]", path)
 	}
+	db.RecordFailures(ctx.Bool(logsFailuresFlag.Name))
+	db.RecordTokenTransfers(ctx.Bool(logsTokenTransfersFlag.Name))
 	return db, nil
 }
 
-func initChainRepository(gene *genesis.Genesis, mainDB *muxdb.MuxDB, logDB *logdb.LogDB) (*chain.Repository, error) {
+// startLogPruner starts the pruner of logs if the retention policy is set.
+func startLogPruner(ctx *cli.Context, logDB logdb.LogStore, repo *chain.Repository) (*logpruner.Pruner, error) {
+	blocks, days := ctx.Uint64(logsRetentionBlocksFlag.Name), ctx.Uint64(logsRetentionDaysFlag.Name)
+	if blocks > math.MaxUint32 {
+		return nil, fmt.Errorf("invalid value %d for flag %v", blocks, logsRetentionBlocksFlag.Name)
+	}
+	if days > math.MaxUint32 {
+		return nil, fmt.Errorf("invalid value %d for flag %v", days, logsRetentionDaysFlag.Name)
+	}
+	policy := logpruner.Policy{Blocks: uint32(blocks), Days: uint32(days)}
+	for _, value := range strings.Split(ctx.String(logsKeepAddressesFlag.Name), ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		addr, err := thor.ParseAddress(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse flag %v", logsKeepAddressesFlag.Name)
+		}
+		policy.Keep = append(policy.Keep, addr)
+	}
+	if !policy.Enabled() {
+		return nil, nil
+	}
+	return logpruner.New(logDB, repo, policy), nil
+}
+
+func initChainRepository(gene *genesis.Genesis, mainDB *muxdb.MuxDB, logDB logdb.LogStore) (*chain.Repository, error) {
 	genesisBlock, genesisEvents, genesisTransfers, err := gene.Build(state.NewStater(mainDB))
 	if err != nil {
 		return nil, errors.Wrap(err, "build genesis block")
@@ -668,12 +712,19 @@ func openMemMainDB() *muxdb.MuxDB {
 	return muxdb.NewMem()
 }
 
-func openMemLogDB() *logdb.LogDB {
-	db, err := logdb.NewMem()
-	if err != nil {
-		panic(errors.Wrap(err, "open log database"))
+func openMemLogDB(ctx *cli.Context) (logdb.LogStore, error) {
+	switch backend := ctx.String(logDBBackendFlag.Name); backend {
+	case "sqlite":
+		db, err := logdb.NewMem()
+		if err != nil {
+			return nil, errors.Wrap(err, "open log database")
+		}
+		return db, nil
+	case "kv":
+		return logdb.NewKVMem(), nil
+	default:
+		return nil, fmt.Errorf("unrecognized value '%v' for flag %v", backend, logDBBackendFlag.Name)
 	}
-	return db
 }
 
 func parseNodeList(list string) ([]*discover.Node, error) {
@@ -724,3 +775,29 @@ func parseTracerList(list string) []string {
 
 	return tracers
 }
+
+func readinessOptions(ctx *cli.Context) health.Options {
+	return health.Options{
+		MaxHeadAge:  time.Duration(ctx.Uint64(apiReadyMaxHeadAgeFlag.Name)) * time.Second,
+		MinPeers:    int(ctx.Uint64(apiReadyMinPeersFlag.Name)),
+		MaxLogDBLag: uint32(ctx.Uint64(apiReadyMaxLogsLagFlag.Name)),
+	}
+}
+
+// newRateLimiter creates the API rate limiter, returns nil if neither API keys nor rate limits are configured.
+func newRateLimiter(ctx *cli.Context) (*api.RateLimiter, error) {
+	opts := api.RateLimitOptions{
+		KeysFile:      strings.TrimSpace(ctx.String(apiKeysFileFlag.Name)),
+		KeyRequired:   ctx.Bool(apiKeysRequiredFlag.Name),
+		Rate:          ctx.Float64(apiRateLimitFlag.Name),
+		ExpensiveRate: ctx.Float64(apiRateLimitExpensiveFlag.Name),
+	}
+	if opts == (api.RateLimitOptions{}) {
+		return nil, nil
+	}
+	limiter, err := api.NewRateLimiter(opts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "init api rate limiter")
+	}
+	return limiter, nil
+}
@@ -0,0 +1,1484 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/kv"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+const kvStoreName = "logdb"
+
+// key spaces of the kv log db.
+const (
+	kvEventSpace          = byte(0)  // seq => event
+	kvTransferSpace       = byte(1)  // seq => transfer
+	kvBlockSpace          = byte(2)  // block number => ID of the block with logs
+	kvAddressSpace        = byte(3)  // event address + seq => nil
+	kvTopicSpace          = byte(4)  // topic position + event topic + seq => nil
+	kvTxOriginSpace       = byte(5)  // transfer tx origin + seq => nil
+	kvSenderSpace         = byte(6)  // transfer sender + seq => nil
+	kvRecipientSpace      = byte(7)  // transfer recipient + seq => nil
+	kvEventTxIDSpace      = byte(8)  // event tx ID + seq => nil
+	kvEventTxOriginSpace  = byte(9)  // event tx origin + seq => nil
+	kvDeploymentSpace     = byte(10) // seq => deployment
+	kvContractSpace       = byte(11) // deployment address + seq => nil
+	kvDeployerSpace       = byte(12) // deployment tx origin + seq => nil
+	kvFailureSpace        = byte(13) // seq => failure
+	kvFailureToSpace      = byte(14) // failure clause to + seq => nil
+	kvFailureOriginSpace  = byte(15) // failure tx origin + seq => nil
+	kvTokenTransferSpace  = byte(16) // seq => token transfer
+	kvTokenSpace          = byte(17) // token transfer token + seq => nil
+	kvTokenSenderSpace    = byte(18) // token transfer sender + seq => nil
+	kvTokenRecipientSpace = byte(19) // token transfer recipient + seq => nil
+	kvTokenOriginSpace    = byte(20) // token transfer tx origin + seq => nil
+
+	kvPropSpace = byte(0xff) // name => value
+)
+
+// kvVersion is the version of the key spaces, stamped on the store when created.
+const kvVersion = 1
+
+var (
+	kvVersionKey       = append([]byte{kvPropSpace}, "version"...)
+	kvPruneStatusKey   = append([]byte{kvPropSpace}, "pruneStatus"...)
+	kvRebuildStatusKey = append([]byte{kvPropSpace}, "rebuildStatus"...)
+)
+
+// kvEvent is the stored form of an event, the block number and index are in the key.
+type kvEvent struct {
+	BlockID     thor.Bytes32
+	BlockTime   uint64
+	TxID        thor.Bytes32
+	TxOrigin    thor.Address
+	ClauseIndex uint32
+	Address     thor.Address
+	Topics      []thor.Bytes32
+	Data        []byte
+}
+
+// kvTransfer is the stored form of a transfer, the block number and index are in the key.
+type kvTransfer struct {
+	BlockID     thor.Bytes32
+	BlockTime   uint64
+	TxID        thor.Bytes32
+	TxOrigin    thor.Address
+	ClauseIndex uint32
+	Sender      thor.Address
+	Recipient   thor.Address
+	Amount      *big.Int
+}
+
+// kvDeployment is the stored form of a deployment, the block number and index are in the key.
+type kvDeployment struct {
+	BlockID     thor.Bytes32
+	BlockTime   uint64
+	TxID        thor.Bytes32
+	TxOrigin    thor.Address
+	ClauseIndex uint32
+	Address     thor.Address
+}
+
+// kvFailure is the stored form of a failure, the block number and index are in the key.
+type kvFailure struct {
+	BlockID     thor.Bytes32
+	BlockTime   uint64
+	TxID        thor.Bytes32
+	TxOrigin    thor.Address
+	ClauseIndex uint32
+	To          []byte // empty for contract creation
+	Selector    []byte // empty if absent
+}
+
+// kvTokenTransfer is the stored form of a token transfer, the block number and index are in the key.
+type kvTokenTransfer struct {
+	BlockID     thor.Bytes32
+	BlockTime   uint64
+	TxID        thor.Bytes32
+	TxOrigin    thor.Address
+	ClauseIndex uint32
+	Token       thor.Address
+	Sender      thor.Address
+	Recipient   thor.Address
+	Amount      *big.Int
+}
+
+type kvLogDB struct {
+	db          *muxdb.MuxDB
+	store       kv.Store
+	written     atomic.Pointer[thor.Bytes32] // the last block written by committed writers
+	pruneStatus atomic.Pointer[PruneStatus]
+	recording   recording
+}
+
+// NewKV create or open the log db built on the kv engine at given path.
+func NewKV(path string) (LogStore, error) {
+	db, err := muxdb.Open(path, &muxdb.Options{
+		OpenFilesCacheCapacity: 256,
+		ReadCacheMB:            64,
+		WriteBufferMB:          32,
+	})
+	if err != nil {
+		return nil, err
+	}
+	store := db.NewStore(kvStoreName)
+	if err := checkKVVersion(store); err != nil {
+		db.Close()
+		return nil, err
+	}
+	var status PruneStatus
+	if data, err := store.Get(kvPruneStatusKey); err == nil {
+		if err := rlp.DecodeBytes(data, &status); err != nil {
+			db.Close()
+			return nil, err
+		}
+	} else if !store.IsNotFound(err) {
+		db.Close()
+		return nil, err
+	}
+	logDB := &kvLogDB{db: db, store: store}
+	logDB.pruneStatus.Store(&status)
+	return logDB, nil
+}
+
+// NewKVMem create a log db built on the kv engine in ram.
+func NewKVMem() LogStore {
+	db := muxdb.NewMem()
+	store := db.NewStore(kvStoreName)
+	_ = store.Put(kvVersionKey, kvUint64(kvVersion))
+	logDB := &kvLogDB{db: db, store: store}
+	logDB.pruneStatus.Store(&PruneStatus{})
+	return logDB
+}
+
+// checkKVVersion stamps the version of the key spaces on a new store, and rejects stores of other versions.
+func checkKVVersion(store kv.Store) error {
+	val, err := store.Get(kvVersionKey)
+	if err != nil {
+		if store.IsNotFound(err) {
+			return store.Put(kvVersionKey, kvUint64(kvVersion))
+		}
+		return err
+	}
+	if version := binary.BigEndian.Uint64(val); version != kvVersion {
+		return fmt.Errorf("unsupported kv log db version %d", version)
+	}
+	return nil
+}
+
+// Close close the log db.
+func (db *kvLogDB) Close() error {
+	return db.db.Close()
+}
+
+func (db *kvLogDB) WrittenBlockID() thor.Bytes32 {
+	if id := db.written.Load(); id != nil {
+		return *id
+	}
+	return thor.Bytes32{}
+}
+
+func (db *kvLogDB) FilterEvents(ctx context.Context, filter *EventFilter) ([]*Event, error) {
+	if filter == nil {
+		filter = &EventFilter{}
+	}
+	criteria := eventKVCriteria(filter.CriteriaSet)
+	return kvFilter(ctx, db.store, kvEventSpace, criteria, decodeKVEvent, filter.Range, filter.Options, filter.Order)
+}
+
+func eventKVCriteria(criteriaSet []*EventCriteria) []*kvCriteria[*Event] {
+	var criteria []*kvCriteria[*Event]
+	for _, c := range criteriaSet {
+		// OR-sets are scanned by the index of each value
+		for _, index := range eventCriteriaIndexes(c) {
+			criteria = append(criteria, &kvCriteria[*Event]{index: index, match: c.match})
+		}
+	}
+	return criteria
+}
+
+// eventCriteriaIndexes returns the prefixes of indexes to scan for the criteria, a nil prefix to scan all events.
+func eventCriteriaIndexes(c *EventCriteria) [][]byte {
+	switch {
+	case c.TxID != nil:
+		return [][]byte{append([]byte{kvEventTxIDSpace}, c.TxID[:]...)}
+	case c.Address != nil:
+		return [][]byte{append([]byte{kvAddressSpace}, c.Address[:]...)}
+	}
+	for i, topic := range c.Topics {
+		if topic != nil {
+			return [][]byte{append([]byte{kvTopicSpace, byte(i)}, topic[:]...)}
+		}
+	}
+	if len(c.AddressSet) > 0 {
+		indexes := make([][]byte, 0, len(c.AddressSet))
+		for _, addr := range c.AddressSet {
+			indexes = append(indexes, append([]byte{kvAddressSpace}, addr[:]...))
+		}
+		return indexes
+	}
+	for i, set := range c.TopicSets {
+		if len(set) > 0 {
+			indexes := make([][]byte, 0, len(set))
+			for _, topic := range set {
+				indexes = append(indexes, append([]byte{kvTopicSpace, byte(i)}, topic[:]...))
+			}
+			return indexes
+		}
+	}
+	if c.TxOrigin != nil {
+		return [][]byte{append([]byte{kvEventTxOriginSpace}, c.TxOrigin[:]...)}
+	}
+	return [][]byte{nil}
+}
+
+func (db *kvLogDB) FilterTransfers(ctx context.Context, filter *TransferFilter) ([]*Transfer, error) {
+	if filter == nil {
+		filter = &TransferFilter{}
+	}
+	criteria := transferKVCriteria(filter.CriteriaSet)
+	return kvFilter(ctx, db.store, kvTransferSpace, criteria, decodeKVTransfer, filter.Range, filter.Options, filter.Order)
+}
+
+func transferKVCriteria(criteriaSet []*TransferCriteria) []*kvCriteria[*Transfer] {
+	var criteria []*kvCriteria[*Transfer]
+	for _, c := range criteriaSet {
+		var index []byte
+		switch {
+		case c.TxOrigin != nil:
+			index = append([]byte{kvTxOriginSpace}, c.TxOrigin[:]...)
+		case c.Sender != nil:
+			index = append([]byte{kvSenderSpace}, c.Sender[:]...)
+		case c.Recipient != nil:
+			index = append([]byte{kvRecipientSpace}, c.Recipient[:]...)
+		}
+		criteria = append(criteria, &kvCriteria[*Transfer]{index: index, match: c.match})
+	}
+	return criteria
+}
+
+func (db *kvLogDB) FilterDeployments(ctx context.Context, filter *DeploymentFilter) ([]*Deployment, error) {
+	if filter == nil {
+		filter = &DeploymentFilter{}
+	}
+	var criteria []*kvCriteria[*Deployment]
+	for _, c := range filter.CriteriaSet {
+		var index []byte
+		switch {
+		case c.Address != nil:
+			index = append([]byte{kvContractSpace}, c.Address[:]...)
+		case c.Deployer != nil:
+			index = append([]byte{kvDeployerSpace}, c.Deployer[:]...)
+		}
+		criteria = append(criteria, &kvCriteria[*Deployment]{index: index, match: c.match})
+	}
+	return kvFilter(ctx, db.store, kvDeploymentSpace, criteria, decodeKVDeployment, filter.Range, filter.Options, filter.Order)
+}
+
+func (db *kvLogDB) FilterFailures(ctx context.Context, filter *FailureFilter) ([]*Failure, error) {
+	if filter == nil {
+		filter = &FailureFilter{}
+	}
+	var criteria []*kvCriteria[*Failure]
+	for _, c := range filter.CriteriaSet {
+		var index []byte
+		switch {
+		case c.To != nil:
+			index = append([]byte{kvFailureToSpace}, c.To[:]...)
+		case c.TxOrigin != nil:
+			index = append([]byte{kvFailureOriginSpace}, c.TxOrigin[:]...)
+		}
+		criteria = append(criteria, &kvCriteria[*Failure]{index: index, match: c.match})
+	}
+	return kvFilter(ctx, db.store, kvFailureSpace, criteria, decodeKVFailure, filter.Range, filter.Options, filter.Order)
+}
+
+func (db *kvLogDB) FilterTokenTransfers(ctx context.Context, filter *TokenTransferFilter) ([]*TokenTransfer, error) {
+	if filter == nil {
+		filter = &TokenTransferFilter{}
+	}
+	var criteria []*kvCriteria[*TokenTransfer]
+	for _, c := range filter.CriteriaSet {
+		// accounts are more selective than tokens
+		var index []byte
+		switch {
+		case c.Sender != nil:
+			index = append([]byte{kvTokenSenderSpace}, c.Sender[:]...)
+		case c.Recipient != nil:
+			index = append([]byte{kvTokenRecipientSpace}, c.Recipient[:]...)
+		case c.TxOrigin != nil:
+			index = append([]byte{kvTokenOriginSpace}, c.TxOrigin[:]...)
+		case c.Token != nil:
+			index = append([]byte{kvTokenSpace}, c.Token[:]...)
+		}
+		criteria = append(criteria, &kvCriteria[*TokenTransfer]{index: index, match: c.match})
+	}
+	return kvFilter(ctx, db.store, kvTokenTransferSpace, criteria, decodeKVTokenTransfer, filter.Range, filter.Options, filter.Order)
+}
+
+func (db *kvLogDB) CountEvents(ctx context.Context, filter *EventFilter, bucket uint64) ([]*EventCount, error) {
+	if filter == nil {
+		filter = &EventFilter{}
+	}
+	criteria := eventKVCriteria(filter.CriteriaSet)
+	return kvAggregate(
+		func(visit func(*Event) bool) error {
+			return kvScan(ctx, db.store, kvEventSpace, criteria, decodeKVEvent, filter.Range, nil, filter.Order, visit)
+		},
+		func(ev *Event) uint64 { return ev.BlockTime },
+		bucket,
+		func(time uint64) *EventCount { return &EventCount{BucketTime: time} },
+		func(count *EventCount, _ *Event) { count.Count++ },
+		filter.Options,
+	)
+}
+
+func (db *kvLogDB) SummarizeTransfers(ctx context.Context, filter *TransferFilter, bucket uint64) ([]*TransferSummary, error) {
+	if filter == nil {
+		filter = &TransferFilter{}
+	}
+	type summary struct {
+		*TransferSummary
+		senders map[thor.Address]struct{}
+	}
+	criteria := transferKVCriteria(filter.CriteriaSet)
+	aggregated, err := kvAggregate(
+		func(visit func(*Transfer) bool) error {
+			return kvScan(ctx, db.store, kvTransferSpace, criteria, decodeKVTransfer, filter.Range, nil, filter.Order, visit)
+		},
+		func(tr *Transfer) uint64 { return tr.BlockTime },
+		bucket,
+		func(time uint64) *summary {
+			return &summary{&TransferSummary{BucketTime: time, Amount: new(big.Int)}, make(map[thor.Address]struct{})}
+		},
+		func(s *summary, tr *Transfer) {
+			s.Count++
+			s.Amount.Add(s.Amount, tr.Amount)
+			s.senders[tr.Sender] = struct{}{}
+		},
+		filter.Options,
+	)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]*TransferSummary, 0, len(aggregated))
+	for _, s := range aggregated {
+		s.Senders = uint64(len(s.senders))
+		summaries = append(summaries, s.TransferSummary)
+	}
+	return summaries, nil
+}
+
+// NewestBlockID query newest written block id.
+func (db *kvLogDB) NewestBlockID() (thor.Bytes32, error) {
+	it := db.store.Iterate(kv.Range{Start: []byte{kvBlockSpace}, Limit: []byte{kvBlockSpace + 1}})
+	defer it.Release()
+
+	if it.Last() {
+		return thor.BytesToBytes32(it.Value()), nil
+	}
+	return thor.Bytes32{}, it.Error()
+}
+
+// HasBlockID query whether given block id related logs were written.
+func (db *kvLogDB) HasBlockID(id thor.Bytes32) (bool, error) {
+	val, err := db.store.Get(kvBlockKey(block.Number(id)))
+	if err != nil {
+		if db.store.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return thor.BytesToBytes32(val) == id, nil
+}
+
+func (db *kvLogDB) PruneStatus() *PruneStatus {
+	return db.pruneStatus.Load()
+}
+
+func (db *kvLogDB) Prune(ctx context.Context, blockNum uint32, keep []thor.Address) error {
+	// raise the pruned block number first, to reject queries of logs being deleted
+	status := db.PruneStatus().next(blockNum, keep)
+	if err := db.savePruneStatus(db.store.Bulk(), status); err != nil {
+		return err
+	}
+
+	isKept := func(addrs ...thor.Address) bool {
+		for _, addr := range addrs {
+			if slices.Contains(keep, addr) {
+				return true
+			}
+		}
+		return false
+	}
+	for status.Deleted < status.BlockNum {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		next := *status
+		next.Deleted += min(status.BlockNum-status.Deleted, pruneWindow)
+
+		var (
+			bulk     = db.store.Bulk()
+			from, to = newSequence(status.Deleted, 0), newSequence(next.Deleted, 0)
+		)
+		if err := db.pruneSpace(bulk, kvEventSpace, from, to, func(data []byte) ([][]byte, error) {
+			var ev kvEvent
+			if err := rlp.DecodeBytes(data, &ev); err != nil {
+				return nil, err
+			}
+			if isKept(ev.Address) {
+				return nil, nil
+			}
+			return eventIndexes(ev.TxID, ev.TxOrigin, ev.Address, ev.Topics), nil
+		}); err != nil {
+			return err
+		}
+		if err := db.pruneSpace(bulk, kvTransferSpace, from, to, func(data []byte) ([][]byte, error) {
+			var tr kvTransfer
+			if err := rlp.DecodeBytes(data, &tr); err != nil {
+				return nil, err
+			}
+			if isKept(tr.Sender, tr.Recipient) {
+				return nil, nil
+			}
+			return transferIndexes(tr.TxOrigin, tr.Sender, tr.Recipient), nil
+		}); err != nil {
+			return err
+		}
+		if err := db.pruneSpace(bulk, kvFailureSpace, from, to, func(data []byte) ([][]byte, error) {
+			var f kvFailure
+			if err := rlp.DecodeBytes(data, &f); err != nil {
+				return nil, err
+			}
+			if len(f.To) > 0 && isKept(thor.BytesToAddress(f.To)) {
+				return nil, nil
+			}
+			return failureIndexes(f.TxOrigin, f.To), nil
+		}); err != nil {
+			return err
+		}
+		if err := db.pruneSpace(bulk, kvTokenTransferSpace, from, to, func(data []byte) ([][]byte, error) {
+			var tr kvTokenTransfer
+			if err := rlp.DecodeBytes(data, &tr); err != nil {
+				return nil, err
+			}
+			if isKept(tr.Token, tr.Sender, tr.Recipient) {
+				return nil, nil
+			}
+			return tokenTransferIndexes(tr.TxOrigin, tr.Token, tr.Sender, tr.Recipient), nil
+		}); err != nil {
+			return err
+		}
+		if err := db.savePruneStatus(bulk, &next); err != nil {
+			return err
+		}
+		status = &next
+	}
+	return nil
+}
+
+// pruneSpace deletes logs in the range [from, to) of the space, along with the returned indexes.
+// Logs are kept if no index returned.
+func (db *kvLogDB) pruneSpace(bulk kv.Bulk, space byte, from, to sequence, indexes func([]byte) ([][]byte, error)) error {
+	it := db.store.Iterate(kv.Range{Start: kvLogKey(space, from), Limit: kvLogKey(space, to)})
+	defer it.Release()
+
+	for it.Next() {
+		prefixes, err := indexes(it.Value())
+		if err != nil {
+			return err
+		}
+		if len(prefixes) == 0 {
+			continue
+		}
+		seq := sequence(binary.BigEndian.Uint64(it.Key()[1:]))
+		for _, prefix := range prefixes {
+			if err := bulk.Delete(kvIndexKey(prefix, seq)); err != nil {
+				return err
+			}
+		}
+		if err := bulk.Delete(kvLogKey(space, seq)); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// savePruneStatus writes the bulk along with the prune status.
+func (db *kvLogDB) savePruneStatus(bulk kv.Bulk, status *PruneStatus) error {
+	data, err := rlp.EncodeToBytes(status)
+	if err != nil {
+		return err
+	}
+	if err := bulk.Put(kvPruneStatusKey, data); err != nil {
+		return err
+	}
+	if err := bulk.Write(); err != nil {
+		return err
+	}
+	db.pruneStatus.Store(status)
+	return nil
+}
+
+func (db *kvLogDB) loadRebuildStatus() (*rebuildStatus, error) {
+	data, err := db.store.Get(kvRebuildStatusKey)
+	if err != nil {
+		if db.store.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var status rebuildStatus
+	if err := rlp.DecodeBytes(data, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (db *kvLogDB) Rebuilding() (bool, error) {
+	status, err := db.loadRebuildStatus()
+	return status != nil, err
+}
+
+// Rebuild starts rebuilding the db from scratch, or resumes the unfinished rebuild.
+// Logs are written without indexes, which are built by scanning all logs when the rebuild is finished.
+func (db *kvLogDB) Rebuild() (Rebuilder, error) {
+	status, err := db.loadRebuildStatus()
+	if err != nil {
+		return nil, err
+	}
+	if status == nil {
+		status = &rebuildStatus{}
+		// all key spaces except properties
+		if err := db.store.DeleteRange(context.Background(), kv.Range{Start: []byte{kvEventSpace}, Limit: []byte{kvPropSpace}}); err != nil {
+			return nil, err
+		}
+		data, err := rlp.EncodeToBytes(status)
+		if err != nil {
+			return nil, err
+		}
+		bulk := db.store.Bulk()
+		if err := bulk.Delete(kvPruneStatusKey); err != nil {
+			return nil, err
+		}
+		if err := bulk.Put(kvRebuildStatusKey, data); err != nil {
+			return nil, err
+		}
+		if err := bulk.Write(); err != nil {
+			return nil, err
+		}
+		db.pruneStatus.Store(&PruneStatus{})
+	}
+	return &kvRebuilder{db: db, status: status}, nil
+}
+
+func (db *kvLogDB) RecordFailures(enabled bool) {
+	db.recording.failures = enabled
+}
+
+func (db *kvLogDB) RecordTokenTransfers(enabled bool) {
+	db.recording.tokenTransfers = enabled
+}
+
+func (db *kvLogDB) NewWriter() Writer {
+	return &kvWriter{db: db, recording: db.recording}
+}
+
+// NewWriterSyncOff creates a log writer, which is the same as the one created by NewWriter,
+// since writes of the kv engine are not synced.
+func (db *kvLogDB) NewWriterSyncOff() Writer {
+	return &kvWriter{db: db, recording: db.recording}
+}
+
+// kvPutLogs puts logs of a block, along with the indexes if withIndexes is true, and the optional logs being recorded.
+func kvPutLogs(logs *BlockLogs, withIndexes bool, rec recording, put func(key, val []byte) error) error {
+	var (
+		failures       []*Failure
+		tokenTransfers []*TokenTransfer
+	)
+	if rec.failures {
+		failures = logs.Failures
+	}
+	if rec.tokenTransfers {
+		tokenTransfers = logs.TokenTransfers
+	}
+
+	for _, ev := range logs.Events {
+		var topics []thor.Bytes32
+		for _, topic := range ev.Topics {
+			if topic == nil {
+				break
+			}
+			topics = append(topics, *topic)
+		}
+		data, err := rlp.EncodeToBytes(&kvEvent{
+			BlockID:     ev.BlockID,
+			BlockTime:   ev.BlockTime,
+			TxID:        ev.TxID,
+			TxOrigin:    ev.TxOrigin,
+			ClauseIndex: ev.ClauseIndex,
+			Address:     ev.Address,
+			Topics:      topics,
+			Data:        ev.Data,
+		})
+		if err != nil {
+			return err
+		}
+		seq := newSequence(ev.BlockNumber, ev.Index)
+		if err := put(kvLogKey(kvEventSpace, seq), data); err != nil {
+			return err
+		}
+		if withIndexes {
+			for _, prefix := range eventIndexes(ev.TxID, ev.TxOrigin, ev.Address, topics) {
+				if err := put(kvIndexKey(prefix, seq), []byte{}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, tr := range logs.Transfers {
+		data, err := rlp.EncodeToBytes(&kvTransfer{
+			BlockID:     tr.BlockID,
+			BlockTime:   tr.BlockTime,
+			TxID:        tr.TxID,
+			TxOrigin:    tr.TxOrigin,
+			ClauseIndex: tr.ClauseIndex,
+			Sender:      tr.Sender,
+			Recipient:   tr.Recipient,
+			Amount:      tr.Amount,
+		})
+		if err != nil {
+			return err
+		}
+		seq := newSequence(tr.BlockNumber, tr.Index)
+		if err := put(kvLogKey(kvTransferSpace, seq), data); err != nil {
+			return err
+		}
+		if withIndexes {
+			for _, prefix := range transferIndexes(tr.TxOrigin, tr.Sender, tr.Recipient) {
+				if err := put(kvIndexKey(prefix, seq), []byte{}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, d := range logs.Deployments {
+		data, err := rlp.EncodeToBytes(&kvDeployment{
+			BlockID:     d.BlockID,
+			BlockTime:   d.BlockTime,
+			TxID:        d.TxID,
+			TxOrigin:    d.TxOrigin,
+			ClauseIndex: d.ClauseIndex,
+			Address:     d.Address,
+		})
+		if err != nil {
+			return err
+		}
+		seq := newSequence(d.BlockNumber, d.Index)
+		if err := put(kvLogKey(kvDeploymentSpace, seq), data); err != nil {
+			return err
+		}
+		if withIndexes {
+			for _, prefix := range deploymentIndexes(d.TxOrigin, d.Address) {
+				if err := put(kvIndexKey(prefix, seq), []byte{}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, f := range failures {
+		var to, selector []byte
+		if f.To != nil {
+			to = f.To[:]
+		}
+		if f.Selector != nil {
+			selector = f.Selector[:]
+		}
+		data, err := rlp.EncodeToBytes(&kvFailure{
+			BlockID:     f.BlockID,
+			BlockTime:   f.BlockTime,
+			TxID:        f.TxID,
+			TxOrigin:    f.TxOrigin,
+			ClauseIndex: f.ClauseIndex,
+			To:          to,
+			Selector:    selector,
+		})
+		if err != nil {
+			return err
+		}
+		seq := newSequence(f.BlockNumber, f.Index)
+		if err := put(kvLogKey(kvFailureSpace, seq), data); err != nil {
+			return err
+		}
+		if withIndexes {
+			for _, prefix := range failureIndexes(f.TxOrigin, to) {
+				if err := put(kvIndexKey(prefix, seq), []byte{}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, tr := range tokenTransfers {
+		data, err := rlp.EncodeToBytes(&kvTokenTransfer{
+			BlockID:     tr.BlockID,
+			BlockTime:   tr.BlockTime,
+			TxID:        tr.TxID,
+			TxOrigin:    tr.TxOrigin,
+			ClauseIndex: tr.ClauseIndex,
+			Token:       tr.Token,
+			Sender:      tr.Sender,
+			Recipient:   tr.Recipient,
+			Amount:      tr.Amount,
+		})
+		if err != nil {
+			return err
+		}
+		seq := newSequence(tr.BlockNumber, tr.Index)
+		if err := put(kvLogKey(kvTokenTransferSpace, seq), data); err != nil {
+			return err
+		}
+		if withIndexes {
+			for _, prefix := range tokenTransferIndexes(tr.TxOrigin, tr.Token, tr.Sender, tr.Recipient) {
+				if err := put(kvIndexKey(prefix, seq), []byte{}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if len(logs.Events) > 0 || len(logs.Transfers) > 0 || len(logs.Deployments) > 0 || len(failures) > 0 || len(tokenTransfers) > 0 {
+		return put(kvBlockKey(block.Number(logs.BlockID)), logs.BlockID.Bytes())
+	}
+	return nil
+}
+
+func kvUint64(v uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	return b[:]
+}
+
+func kvLogKey(space byte, seq sequence) []byte {
+	return append([]byte{space}, kvUint64(uint64(seq))...)
+}
+
+func kvBlockKey(blockNum uint32) []byte {
+	var b [5]byte
+	b[0] = kvBlockSpace
+	binary.BigEndian.PutUint32(b[1:], blockNum)
+	return b[:]
+}
+
+func kvIndexKey(prefix []byte, seq sequence) []byte {
+	return append(append([]byte(nil), prefix...), kvUint64(uint64(seq))...)
+}
+
+func decodeKVEvent(seq sequence, data []byte) (*Event, error) {
+	var ev kvEvent
+	if err := rlp.DecodeBytes(data, &ev); err != nil {
+		return nil, err
+	}
+	event := &Event{
+		BlockNumber: seq.BlockNumber(),
+		Index:       seq.Index(),
+		BlockID:     ev.BlockID,
+		BlockTime:   ev.BlockTime,
+		TxID:        ev.TxID,
+		TxOrigin:    ev.TxOrigin,
+		ClauseIndex: ev.ClauseIndex,
+		Address:     ev.Address,
+	}
+	for i := range ev.Topics {
+		event.Topics[i] = &ev.Topics[i]
+	}
+	if len(ev.Data) > 0 {
+		event.Data = ev.Data
+	}
+	return event, nil
+}
+
+func decodeKVTransfer(seq sequence, data []byte) (*Transfer, error) {
+	var tr kvTransfer
+	if err := rlp.DecodeBytes(data, &tr); err != nil {
+		return nil, err
+	}
+	return &Transfer{
+		BlockNumber: seq.BlockNumber(),
+		Index:       seq.Index(),
+		BlockID:     tr.BlockID,
+		BlockTime:   tr.BlockTime,
+		TxID:        tr.TxID,
+		TxOrigin:    tr.TxOrigin,
+		ClauseIndex: tr.ClauseIndex,
+		Sender:      tr.Sender,
+		Recipient:   tr.Recipient,
+		Amount:      tr.Amount,
+	}, nil
+}
+
+func decodeKVDeployment(seq sequence, data []byte) (*Deployment, error) {
+	var d kvDeployment
+	if err := rlp.DecodeBytes(data, &d); err != nil {
+		return nil, err
+	}
+	return &Deployment{
+		BlockNumber: seq.BlockNumber(),
+		Index:       seq.Index(),
+		BlockID:     d.BlockID,
+		BlockTime:   d.BlockTime,
+		TxID:        d.TxID,
+		TxOrigin:    d.TxOrigin,
+		ClauseIndex: d.ClauseIndex,
+		Address:     d.Address,
+	}, nil
+}
+
+func decodeKVFailure(seq sequence, data []byte) (*Failure, error) {
+	var f kvFailure
+	if err := rlp.DecodeBytes(data, &f); err != nil {
+		return nil, err
+	}
+	failure := &Failure{
+		BlockNumber: seq.BlockNumber(),
+		Index:       seq.Index(),
+		BlockID:     f.BlockID,
+		BlockTime:   f.BlockTime,
+		TxID:        f.TxID,
+		TxOrigin:    f.TxOrigin,
+		ClauseIndex: f.ClauseIndex,
+	}
+	if len(f.To) > 0 {
+		to := thor.BytesToAddress(f.To)
+		failure.To = &to
+	}
+	if len(f.Selector) == 4 {
+		failure.Selector = (*[4]byte)(f.Selector)
+	}
+	return failure, nil
+}
+
+func decodeKVTokenTransfer(seq sequence, data []byte) (*TokenTransfer, error) {
+	var tr kvTokenTransfer
+	if err := rlp.DecodeBytes(data, &tr); err != nil {
+		return nil, err
+	}
+	return &TokenTransfer{
+		BlockNumber: seq.BlockNumber(),
+		Index:       seq.Index(),
+		BlockID:     tr.BlockID,
+		BlockTime:   tr.BlockTime,
+		TxID:        tr.TxID,
+		TxOrigin:    tr.TxOrigin,
+		ClauseIndex: tr.ClauseIndex,
+		Token:       tr.Token,
+		Sender:      tr.Sender,
+		Recipient:   tr.Recipient,
+		Amount:      tr.Amount,
+	}, nil
+}
+
+// eventIndexes returns the index prefixes of the event.
+func eventIndexes(txID thor.Bytes32, txOrigin, address thor.Address, topics []thor.Bytes32) [][]byte {
+	indexes := [][]byte{
+		append([]byte{kvEventTxIDSpace}, txID[:]...),
+		append([]byte{kvEventTxOriginSpace}, txOrigin[:]...),
+		append([]byte{kvAddressSpace}, address[:]...),
+	}
+	for i, topic := range topics {
+		indexes = append(indexes, append([]byte{kvTopicSpace, byte(i)}, topic[:]...))
+	}
+	return indexes
+}
+
+// transferIndexes returns the index prefixes of the transfer.
+func transferIndexes(txOrigin, sender, recipient thor.Address) [][]byte {
+	return [][]byte{
+		append([]byte{kvTxOriginSpace}, txOrigin[:]...),
+		append([]byte{kvSenderSpace}, sender[:]...),
+		append([]byte{kvRecipientSpace}, recipient[:]...),
+	}
+}
+
+// deploymentIndexes returns the index prefixes of the deployment.
+func deploymentIndexes(txOrigin, address thor.Address) [][]byte {
+	return [][]byte{
+		append([]byte{kvContractSpace}, address[:]...),
+		append([]byte{kvDeployerSpace}, txOrigin[:]...),
+	}
+}
+
+// failureIndexes returns the index prefixes of the failure, to is empty for contract creation.
+func failureIndexes(txOrigin thor.Address, to []byte) [][]byte {
+	indexes := [][]byte{append([]byte{kvFailureOriginSpace}, txOrigin[:]...)}
+	if len(to) > 0 {
+		indexes = append(indexes, append([]byte{kvFailureToSpace}, to...))
+	}
+	return indexes
+}
+
+// tokenTransferIndexes returns the index prefixes of the token transfer.
+func tokenTransferIndexes(txOrigin, token, sender, recipient thor.Address) [][]byte {
+	return [][]byte{
+		append([]byte{kvTokenOriginSpace}, txOrigin[:]...),
+		append([]byte{kvTokenSpace}, token[:]...),
+		append([]byte{kvTokenSenderSpace}, sender[:]...),
+		append([]byte{kvTokenRecipientSpace}, recipient[:]...),
+	}
+}
+
+// kvCriteria is a criteria of the filter.
+type kvCriteria[T any] struct {
+	index []byte // prefix of the index narrowing the logs to scan, or nil to scan all logs
+	match func(T) bool
+}
+
+// kvIter iterates logs matching the criteria.
+type kvIter[T any] struct {
+	store   kv.Store
+	space   byte
+	it      kv.Iterator
+	indexed bool
+	desc    bool
+	started bool
+	match   func(T) bool
+	decode  func(sequence, []byte) (T, error)
+
+	seq sequence // of the current log
+	log T
+}
+
+func (i *kvIter[T]) next() (bool, error) {
+	for {
+		var ok bool
+		switch {
+		case !i.started && i.desc:
+			ok = i.it.Last()
+		case !i.started:
+			ok = i.it.First()
+		case i.desc:
+			ok = i.it.Prev()
+		default:
+			ok = i.it.Next()
+		}
+		i.started = true
+		if !ok {
+			return false, i.it.Error()
+		}
+
+		key := i.it.Key()
+		seq := sequence(binary.BigEndian.Uint64(key[len(key)-8:]))
+		data := i.it.Value()
+		if i.indexed {
+			var err error
+			if data, err = i.store.Get(kvLogKey(i.space, seq)); err != nil {
+				return false, err
+			}
+		}
+		log, err := i.decode(seq, data)
+		if err != nil {
+			return false, err
+		}
+		if i.match(log) {
+			i.seq, i.log = seq, log
+			return true, nil
+		}
+	}
+}
+
+// kvFilter queries logs matching any of the criteria, by merging the logs iterated for each criteria.
+func kvFilter[T any](
+	ctx context.Context,
+	store kv.Store,
+	space byte,
+	criteria []*kvCriteria[T],
+	decode func(sequence, []byte) (T, error),
+	rng *Range,
+	opts *Options,
+	order Order,
+) ([]T, error) {
+	var (
+		offset uint64
+		limit  = uint64(math.MaxUint64)
+		cursor *Cursor
+	)
+	if opts != nil {
+		offset, limit, cursor = opts.Offset, opts.Limit, opts.Cursor
+	}
+	if limit == 0 {
+		return nil, nil
+	}
+
+	var (
+		logs    []T
+		skipped uint64
+	)
+	if err := kvScan(ctx, store, space, criteria, decode, rng, cursor, order, func(log T) bool {
+		if skipped < offset {
+			skipped++
+			return true
+		}
+		logs = append(logs, log)
+		return uint64(len(logs)) < limit
+	}); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// kvScan visits logs in order matching any of the criteria, until visit returns false.
+func kvScan[T any](
+	ctx context.Context,
+	store kv.Store,
+	space byte,
+	criteria []*kvCriteria[T],
+	decode func(sequence, []byte) (T, error),
+	rng *Range,
+	cursor *Cursor,
+	order Order,
+	visit func(T) bool,
+) error {
+	var (
+		desc     = order == DESC
+		from     = sequence(0)
+		to       = sequence(math.MaxInt64)
+		iters    []*kvIter[T]
+		releases []func()
+	)
+	defer func() {
+		for _, release := range releases {
+			release()
+		}
+	}()
+
+	if rng != nil {
+		from = newSequence(rng.From, 0)
+		if rng.To >= rng.From {
+			to = newSequence(rng.To, uint32(math.MaxInt32))
+		}
+	}
+	if cursor != nil {
+		if desc {
+			if cursor.seq <= from {
+				return nil
+			}
+			to = min(to, cursor.seq-1)
+		} else {
+			if cursor.seq >= to {
+				return nil
+			}
+			from = max(from, cursor.seq+1)
+		}
+	}
+	if from > to {
+		return nil
+	}
+
+	if len(criteria) == 0 {
+		criteria = []*kvCriteria[T]{{match: func(T) bool { return true }}}
+	}
+	for _, c := range criteria {
+		prefix := c.index
+		if prefix == nil {
+			prefix = []byte{space}
+		}
+		it := store.Iterate(kv.Range{
+			Start: kvIndexKey(prefix, from),
+			// to+1 never overflows as uint64
+			Limit: append(append([]byte(nil), prefix...), kvUint64(uint64(to)+1)...),
+		})
+		releases = append(releases, it.Release)
+
+		iter := &kvIter[T]{
+			store:   store,
+			space:   space,
+			it:      it,
+			indexed: c.index != nil,
+			desc:    desc,
+			match:   c.match,
+			decode:  decode,
+		}
+		ok, err := iter.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			iters = append(iters, iter)
+		}
+	}
+
+	for len(iters) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		// pick the next log in order, among the heads of the iterators
+		seq := iters[0].seq
+		for _, iter := range iters[1:] {
+			if (desc && iter.seq > seq) || (!desc && iter.seq < seq) {
+				seq = iter.seq
+			}
+		}
+
+		var log T
+		n := 0
+		for _, iter := range iters {
+			if iter.seq == seq {
+				log = iter.log
+				ok, err := iter.next()
+				if err != nil {
+					return err
+				}
+				if !ok {
+					continue
+				}
+			}
+			iters[n] = iter
+			n++
+		}
+		iters = iters[:n]
+
+		if !visit(log) {
+			break
+		}
+	}
+	return nil
+}
+
+// kvAggregate aggregates the scanned logs by buckets of block time, or into a single bucket if bucket is zero.
+// Logs are scanned in order of block, so are the buckets, which are then paged by the options.
+func kvAggregate[T, B any](
+	scan func(visit func(T) bool) error,
+	blockTime func(T) uint64,
+	bucket uint64,
+	newBucket func(time uint64) B,
+	add func(B, T),
+	opts *Options,
+) ([]B, error) {
+	var (
+		buckets []B
+		last    uint64 // start time of the last bucket
+	)
+	if bucket == 0 {
+		buckets = append(buckets, newBucket(0))
+	}
+	if err := scan(func(log T) bool {
+		if bucket > 0 {
+			if time := blockTime(log) / bucket * bucket; len(buckets) == 0 || time != last {
+				// stop once the buckets to return are all complete
+				if opts != nil && uint64(len(buckets)) >= opts.Offset && uint64(len(buckets))-opts.Offset >= opts.Limit {
+					return false
+				}
+				buckets = append(buckets, newBucket(time))
+				last = time
+			}
+		}
+		add(buckets[len(buckets)-1], log)
+		return true
+	}); err != nil {
+		return nil, err
+	}
+
+	if opts != nil {
+		if opts.Offset >= uint64(len(buckets)) {
+			return nil, nil
+		}
+		buckets = buckets[opts.Offset:]
+		if opts.Limit < uint64(len(buckets)) {
+			buckets = buckets[:opts.Limit]
+		}
+	}
+	return buckets, nil
+}
+
+// kvRebuilder is the rebuilder of the kv log db.
+type kvRebuilder struct {
+	db     *kvLogDB
+	status *rebuildStatus
+}
+
+func (r *kvRebuilder) Next() uint32 {
+	return r.status.Next
+}
+
+func (r *kvRebuilder) Write(logs []*BlockLogs) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	// partially flushed logs are rewritten on resuming, since the status is written last
+	bulk := r.db.store.Bulk()
+	bulk.EnableAutoFlush()
+
+	status := *r.status
+	for _, l := range logs {
+		if n := block.Number(l.BlockID); n != status.Next {
+			return fmt.Errorf("unexpected block %v, %v expected", n, status.Next)
+		}
+		if err := kvPutLogs(l, false, r.db.recording, bulk.Put); err != nil {
+			return err
+		}
+		status.Next++
+	}
+	data, err := rlp.EncodeToBytes(&status)
+	if err != nil {
+		return err
+	}
+	if err := bulk.Put(kvRebuildStatusKey, data); err != nil {
+		return err
+	}
+	if err := bulk.Write(); err != nil {
+		return err
+	}
+	r.status = &status
+	return nil
+}
+
+func (r *kvRebuilder) Finish() error {
+	bulk := r.db.store.Bulk()
+	bulk.EnableAutoFlush()
+
+	for _, space := range []byte{kvEventSpace, kvTransferSpace, kvDeploymentSpace, kvFailureSpace, kvTokenTransferSpace} {
+		if err := r.indexSpace(bulk, space); err != nil {
+			return err
+		}
+	}
+	if err := bulk.Delete(kvRebuildStatusKey); err != nil {
+		return err
+	}
+	return bulk.Write()
+}
+
+// indexSpace puts indexes of all logs in the space.
+func (r *kvRebuilder) indexSpace(bulk kv.Bulk, space byte) error {
+	it := r.db.store.Iterate(kv.Range{Start: []byte{space}, Limit: []byte{space + 1}})
+	defer it.Release()
+
+	for it.Next() {
+		var prefixes [][]byte
+		switch space {
+		case kvEventSpace:
+			var ev kvEvent
+			if err := rlp.DecodeBytes(it.Value(), &ev); err != nil {
+				return err
+			}
+			prefixes = eventIndexes(ev.TxID, ev.TxOrigin, ev.Address, ev.Topics)
+		case kvTransferSpace:
+			var tr kvTransfer
+			if err := rlp.DecodeBytes(it.Value(), &tr); err != nil {
+				return err
+			}
+			prefixes = transferIndexes(tr.TxOrigin, tr.Sender, tr.Recipient)
+		case kvDeploymentSpace:
+			var d kvDeployment
+			if err := rlp.DecodeBytes(it.Value(), &d); err != nil {
+				return err
+			}
+			prefixes = deploymentIndexes(d.TxOrigin, d.Address)
+		case kvFailureSpace:
+			var f kvFailure
+			if err := rlp.DecodeBytes(it.Value(), &f); err != nil {
+				return err
+			}
+			prefixes = failureIndexes(f.TxOrigin, f.To)
+		default:
+			var tr kvTokenTransfer
+			if err := rlp.DecodeBytes(it.Value(), &tr); err != nil {
+				return err
+			}
+			prefixes = tokenTransferIndexes(tr.TxOrigin, tr.Token, tr.Sender, tr.Recipient)
+		}
+		seq := sequence(binary.BigEndian.Uint64(it.Key()[1:]))
+		for _, prefix := range prefixes {
+			if err := bulk.Put(kvIndexKey(prefix, seq), []byte{}); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Error()
+}
+
+type kvOp struct {
+	blockNum uint32
+	key      []byte
+	val      []byte // nil to delete the key
+}
+
+// kvWriter is the transactional log writer of the kv log db, which buffers operations until committed.
+type kvWriter struct {
+	db          *kvLogDB
+	recording   recording
+	ops         []kvOp
+	lastBlockID *thor.Bytes32 // the last block written since the last commit
+}
+
+func (w *kvWriter) put(blockNum uint32, key, val []byte) {
+	w.ops = append(w.ops, kvOp{blockNum: blockNum, key: key, val: val})
+}
+
+func (w *kvWriter) delete(key []byte) {
+	w.ops = append(w.ops, kvOp{key: key})
+}
+
+// Truncate truncates the database by deleting logs after blockNum (included).
+func (w *kvWriter) Truncate(blockNum uint32) error {
+	// drop uncommitted logs, the deletions are kept
+	n := 0
+	for _, op := range w.ops {
+		if op.val != nil && op.blockNum >= blockNum {
+			continue
+		}
+		w.ops[n] = op
+		n++
+	}
+	w.ops = w.ops[:n]
+
+	// and delete committed ones
+	from := newSequence(blockNum, 0)
+	if err := w.truncateSpace(kvEventSpace, from, func(seq sequence, data []byte) ([][]byte, error) {
+		var ev kvEvent
+		if err := rlp.DecodeBytes(data, &ev); err != nil {
+			return nil, err
+		}
+		return eventIndexes(ev.TxID, ev.TxOrigin, ev.Address, ev.Topics), nil
+	}); err != nil {
+		return err
+	}
+	if err := w.truncateSpace(kvTransferSpace, from, func(seq sequence, data []byte) ([][]byte, error) {
+		var tr kvTransfer
+		if err := rlp.DecodeBytes(data, &tr); err != nil {
+			return nil, err
+		}
+		return transferIndexes(tr.TxOrigin, tr.Sender, tr.Recipient), nil
+	}); err != nil {
+		return err
+	}
+	if err := w.truncateSpace(kvDeploymentSpace, from, func(seq sequence, data []byte) ([][]byte, error) {
+		var d kvDeployment
+		if err := rlp.DecodeBytes(data, &d); err != nil {
+			return nil, err
+		}
+		return deploymentIndexes(d.TxOrigin, d.Address), nil
+	}); err != nil {
+		return err
+	}
+	if err := w.truncateSpace(kvFailureSpace, from, func(seq sequence, data []byte) ([][]byte, error) {
+		var f kvFailure
+		if err := rlp.DecodeBytes(data, &f); err != nil {
+			return nil, err
+		}
+		return failureIndexes(f.TxOrigin, f.To), nil
+	}); err != nil {
+		return err
+	}
+	if err := w.truncateSpace(kvTokenTransferSpace, from, func(seq sequence, data []byte) ([][]byte, error) {
+		var tr kvTokenTransfer
+		if err := rlp.DecodeBytes(data, &tr); err != nil {
+			return nil, err
+		}
+		return tokenTransferIndexes(tr.TxOrigin, tr.Token, tr.Sender, tr.Recipient), nil
+	}); err != nil {
+		return err
+	}
+
+	it := w.db.store.Iterate(kv.Range{Start: kvBlockKey(blockNum), Limit: []byte{kvBlockSpace + 1}})
+	defer it.Release()
+	for it.Next() {
+		w.delete(append([]byte(nil), it.Key()...))
+	}
+	return it.Error()
+}
+
+func (w *kvWriter) truncateSpace(space byte, from sequence, indexes func(sequence, []byte) ([][]byte, error)) error {
+	it := w.db.store.Iterate(kv.Range{Start: kvLogKey(space, from), Limit: []byte{space + 1}})
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		seq := sequence(binary.BigEndian.Uint64(key[1:]))
+		prefixes, err := indexes(seq, it.Value())
+		if err != nil {
+			return err
+		}
+		for _, prefix := range prefixes {
+			w.delete(kvIndexKey(prefix, seq))
+		}
+		w.delete(kvLogKey(space, seq))
+	}
+	return it.Error()
+}
+
+// Write writes all logs of the given block.
+func (w *kvWriter) Write(b *block.Block, receipts tx.Receipts) error {
+	var (
+		blockID  = b.Header().ID()
+		blockNum = b.Header().Number()
+	)
+	w.lastBlockID = &blockID
+
+	return kvPutLogs(NewBlockLogs(b, receipts), true, w.recording, func(key, val []byte) error {
+		w.put(blockNum, key, val)
+		return nil
+	})
+}
+
+// Commit commits accumulated logs.
+func (w *kvWriter) Commit() error {
+	if len(w.ops) > 0 {
+		bulk := w.db.store.Bulk()
+		for _, op := range w.ops {
+			var err error
+			if op.val == nil {
+				err = bulk.Delete(op.key)
+			} else {
+				err = bulk.Put(op.key, op.val)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err := bulk.Write(); err != nil {
+			return err
+		}
+		w.ops = nil
+	}
+	if w.lastBlockID != nil {
+		w.db.written.Store(w.lastBlockID)
+		w.lastBlockID = nil
+	}
+	return nil
+}
+
+// Rollback rollback all uncommitted logs.
+func (w *kvWriter) Rollback() error {
+	w.ops = nil
+	w.lastBlockID = nil
+	return nil
+}
+
+// UncommittedCount returns the count of uncommitted operations.
+func (w *kvWriter) UncommittedCount() int {
+	return len(w.ops)
+}
@@ -11,17 +11,56 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/ethereum/go-ethereum/rlp"
 	sqlite3 "github.com/mattn/go-sqlite3"
 	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/log"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/tx"
 )
 
+var logger = log.WithContext("pkg", "logdb")
+
 const (
 	refIDQuery = "(SELECT id FROM ref WHERE data=?)"
+
+	pruneStatusName   = "pruneStatus"
+	rebuildStatusName = "rebuildStatus"
+
+	// driverName is the sqlite driver extended with functions of the log db.
+	driverName = "sqlite3_logdb"
 )
 
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterAggregator("sum_amount", newSumAmount, true)
+		},
+	})
+}
+
+// sumAmount is the aggregate function summing amounts, which are big-endian encoded big integers.
+type sumAmount struct {
+	sum big.Int
+}
+
+func newSumAmount() *sumAmount { return &sumAmount{} }
+
+func (s *sumAmount) Step(amount interface{}) {
+	if b, ok := amount.([]byte); ok {
+		s.sum.Add(&s.sum, new(big.Int).SetBytes(b))
+	}
+}
+
+func (s *sumAmount) Done() []byte {
+	return s.sum.Bytes()
+}
+
+// LogDB is the SQLite backed LogStore.
 type LogDB struct {
 	path          string
 	driverVersion string
@@ -29,11 +68,15 @@ type LogDB struct {
 	wconn         *sql.Conn
 	wconnSyncOff  *sql.Conn
 	stmtCache     *stmtCache
+	written       atomic.Pointer[thor.Bytes32] // the last block written by committed writers
+	wlock         sync.RWMutex                 // shared by writers in transaction, exclusive to pruning
+	pruneStatus   atomic.Pointer[PruneStatus]
+	recording     recording
 }
 
 // New create or open log db at given path.
 func New(path string) (logDB *LogDB, err error) {
-	db, err := sql.Open("sqlite3", path+"?_journal=wal&cache=shared")
+	db, err := sql.Open(driverName, path+"?_journal=wal&cache=shared")
 	if err != nil {
 		return nil, err
 	}
@@ -46,6 +89,13 @@ func New(path string) (logDB *LogDB, err error) {
 	if _, err := db.Exec(refTableScheme + eventTableSchema + transferTableSchema); err != nil {
 		return nil, err
 	}
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+	pruneStatus, err := loadPruneStatus(db)
+	if err != nil {
+		return nil, err
+	}
 
 	wconn1, err := db.Conn(context.Background())
 	if err != nil {
@@ -62,14 +112,61 @@ func New(path string) (logDB *LogDB, err error) {
 	}
 
 	driverVer, _, _ := sqlite3.Version()
-	return &LogDB{
+	logDB = &LogDB{
 		path:          path,
 		driverVersion: driverVer,
 		db:            db,
 		wconn:         wconn1,
 		wconnSyncOff:  wconn2,
 		stmtCache:     newStmtCache(db),
-	}, nil
+	}
+	logDB.pruneStatus.Store(pruneStatus)
+	return logDB, nil
+}
+
+func loadPruneStatus(db *sql.DB) (*PruneStatus, error) {
+	var (
+		data   []byte
+		status PruneStatus
+	)
+	if err := db.QueryRow("SELECT value FROM prop WHERE name = ?", pruneStatusName).Scan(&data); err != nil {
+		if err == sql.ErrNoRows {
+			return &status, nil
+		}
+		return nil, err
+	}
+	if err := rlp.DecodeBytes(data, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// migrate applies migrations not yet applied to the db.
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("pragma user_version").Scan(&version); err != nil {
+		return err
+	}
+	for ; version < len(migrations); version++ {
+		// steps may take long on large databases, e.g. to build indexes
+		logger.Info("migrating log db", "step", migrations[version].desc)
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[version].query); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %v: %w", version+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("pragma user_version=%d", version+1)); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // NewMem create a log db in ram.
@@ -94,6 +191,15 @@ func (db *LogDB) Path() string {
 	return db.path
 }
 
+// WrittenBlockID returns the ID of the last block written by committed writers since the db is opened.
+// Unlike NewestBlockID, blocks without logs are counted. The zero ID is returned if nothing is written yet.
+func (db *LogDB) WrittenBlockID() thor.Bytes32 {
+	if id := db.written.Load(); id != nil {
+		return *id
+	}
+	return thor.Bytes32{}
+}
+
 func (db *LogDB) FilterEvents(ctx context.Context, filter *EventFilter) ([]*Event, error) {
 	const query = `SELECT e.seq, r0.data, e.blockTime, r1.data, r2.data, e.clauseIndex, r3.data, r4.data, r5.data, r6.data, r7.data, r8.data, e.data
 FROM (%v) e
@@ -111,32 +217,16 @@ FROM (%v) e
 		return db.queryEvents(ctx, fmt.Sprintf(query, "event"))
 	}
 
-	var (
-		subQuery = "SELECT seq FROM event WHERE 1"
-		args     []interface{}
-	)
-
-	if filter.Range != nil {
-		subQuery += " AND seq >= ?"
-		args = append(args, newSequence(filter.Range.From, 0))
-		if filter.Range.To >= filter.Range.From {
-			subQuery += " AND seq <= ?"
-			args = append(args, newSequence(filter.Range.To, uint32(math.MaxInt32)))
-		}
-	}
-
-	if len(filter.CriteriaSet) > 0 {
-		subQuery += " AND ("
+	cond, args := logsCondition(filter.Range, filter.CriteriaSet)
+	subQuery := "SELECT seq FROM event WHERE " + cond
 
-		for i, c := range filter.CriteriaSet {
-			cond, cargs := c.toWhereCondition()
-			if i > 0 {
-				subQuery += " OR"
-			}
-			subQuery += " (" + cond + ")"
-			args = append(args, cargs...)
+	if filter.Options != nil && filter.Options.Cursor != nil {
+		if filter.Order == DESC {
+			subQuery += " AND seq < ?"
+		} else {
+			subQuery += " AND seq > ?"
 		}
-		subQuery += ")"
+		args = append(args, filter.Options.Cursor.seq)
 	}
 
 	// if there is limit option, set order inside subquery
@@ -177,31 +267,64 @@ FROM (%v) t
 		return db.queryTransfers(ctx, fmt.Sprintf(query, "transfer"))
 	}
 
-	var (
-		subQuery = "SELECT seq FROM transfer WHERE 1"
-		args     []interface{}
-	)
+	cond, args := logsCondition(filter.Range, filter.CriteriaSet)
+	subQuery := "SELECT seq FROM transfer WHERE " + cond
 
-	if filter.Range != nil {
-		subQuery += " AND seq >= ?"
-		args = append(args, newSequence(filter.Range.From, 0))
-		if filter.Range.To >= filter.Range.From {
-			subQuery += " AND seq <= ?"
-			args = append(args, newSequence(filter.Range.To, uint32(math.MaxInt32)))
+	if filter.Options != nil && filter.Options.Cursor != nil {
+		if filter.Order == DESC {
+			subQuery += " AND seq < ?"
+		} else {
+			subQuery += " AND seq > ?"
 		}
+		args = append(args, filter.Options.Cursor.seq)
 	}
 
-	if len(filter.CriteriaSet) > 0 {
-		subQuery += " AND ("
-		for i, c := range filter.CriteriaSet {
-			cond, cargs := c.toWhereCondition()
-			if i > 0 {
-				subQuery += " OR"
-			}
-			subQuery += " (" + cond + ")"
-			args = append(args, cargs...)
+	// if there is limit option, set order inside subquery
+	if filter.Options != nil {
+		if filter.Order == DESC {
+			subQuery += " ORDER BY seq DESC"
+		} else {
+			subQuery += " ORDER BY seq ASC"
+		}
+		subQuery += " LIMIT ?, ?"
+		args = append(args, filter.Options.Offset, filter.Options.Limit)
+	}
+
+	subQuery = "SELECT e.* FROM (" + subQuery + ") s LEFT JOIN transfer e ON s.seq = e.seq"
+	transferQuery := fmt.Sprintf(query, subQuery)
+	// if there is no limit option, set order outside
+	if filter.Options == nil {
+		if filter.Order == DESC {
+			transferQuery += " ORDER BY seq DESC "
+		} else {
+			transferQuery += " ORDER BY seq ASC "
+		}
+	}
+	return db.queryTransfers(ctx, transferQuery, args...)
+}
+
+func (db *LogDB) FilterDeployments(ctx context.Context, filter *DeploymentFilter) ([]*Deployment, error) {
+	const query = `SELECT d.seq, r0.data, d.blockTime, r1.data, r2.data, d.clauseIndex, r3.data
+FROM (%v) d
+	LEFT JOIN ref r0 ON d.blockID = r0.id
+	LEFT JOIN ref r1 ON d.txID = r1.id
+	LEFT JOIN ref r2 ON d.txOrigin = r2.id
+	LEFT JOIN ref r3 ON d.address = r3.id`
+
+	if filter == nil {
+		return db.queryDeployments(ctx, fmt.Sprintf(query, "deployment"))
+	}
+
+	cond, args := logsCondition(filter.Range, filter.CriteriaSet)
+	subQuery := "SELECT seq FROM deployment WHERE " + cond
+
+	if filter.Options != nil && filter.Options.Cursor != nil {
+		if filter.Order == DESC {
+			subQuery += " AND seq < ?"
+		} else {
+			subQuery += " AND seq > ?"
 		}
-		subQuery += ")"
+		args = append(args, filter.Options.Cursor.seq)
 	}
 
 	// if there is limit option, set order inside subquery
@@ -215,7 +338,105 @@ FROM (%v) t
 		args = append(args, filter.Options.Offset, filter.Options.Limit)
 	}
 
-	subQuery = "SELECT e.* FROM (" + subQuery + ") s LEFT JOIN transfer e ON s.seq = e.seq"
+	subQuery = "SELECT e.* FROM (" + subQuery + ") s LEFT JOIN deployment e ON s.seq = e.seq"
+	deploymentQuery := fmt.Sprintf(query, subQuery)
+	// if there is no limit option, set order outside
+	if filter.Options == nil {
+		if filter.Order == DESC {
+			deploymentQuery += " ORDER BY seq DESC "
+		} else {
+			deploymentQuery += " ORDER BY seq ASC "
+		}
+	}
+	return db.queryDeployments(ctx, deploymentQuery, args...)
+}
+
+func (db *LogDB) FilterFailures(ctx context.Context, filter *FailureFilter) ([]*Failure, error) {
+	const query = `SELECT f.seq, r0.data, f.blockTime, r1.data, r2.data, f.clauseIndex, r3.data, f.selector
+FROM (%v) f
+	LEFT JOIN ref r0 ON f.blockID = r0.id
+	LEFT JOIN ref r1 ON f.txID = r1.id
+	LEFT JOIN ref r2 ON f.txOrigin = r2.id
+	LEFT JOIN ref r3 ON f.recipient = r3.id`
+
+	if filter == nil {
+		return db.queryFailures(ctx, fmt.Sprintf(query, "failure"))
+	}
+
+	cond, args := logsCondition(filter.Range, filter.CriteriaSet)
+	subQuery := "SELECT seq FROM failure WHERE " + cond
+
+	if filter.Options != nil && filter.Options.Cursor != nil {
+		if filter.Order == DESC {
+			subQuery += " AND seq < ?"
+		} else {
+			subQuery += " AND seq > ?"
+		}
+		args = append(args, filter.Options.Cursor.seq)
+	}
+
+	// if there is limit option, set order inside subquery
+	if filter.Options != nil {
+		if filter.Order == DESC {
+			subQuery += " ORDER BY seq DESC"
+		} else {
+			subQuery += " ORDER BY seq ASC"
+		}
+		subQuery += " LIMIT ?, ?"
+		args = append(args, filter.Options.Offset, filter.Options.Limit)
+	}
+
+	subQuery = "SELECT e.* FROM (" + subQuery + ") s LEFT JOIN failure e ON s.seq = e.seq"
+	failureQuery := fmt.Sprintf(query, subQuery)
+	// if there is no limit option, set order outside
+	if filter.Options == nil {
+		if filter.Order == DESC {
+			failureQuery += " ORDER BY seq DESC "
+		} else {
+			failureQuery += " ORDER BY seq ASC "
+		}
+	}
+	return db.queryFailures(ctx, failureQuery, args...)
+}
+
+func (db *LogDB) FilterTokenTransfers(ctx context.Context, filter *TokenTransferFilter) ([]*TokenTransfer, error) {
+	const query = `SELECT t.seq, r0.data, t.blockTime, r1.data, r2.data, t.clauseIndex, r3.data, r4.data, r5.data, t.amount
+FROM (%v) t
+	LEFT JOIN ref r0 ON t.blockID = r0.id
+	LEFT JOIN ref r1 ON t.txID = r1.id
+	LEFT JOIN ref r2 ON t.txOrigin = r2.id
+	LEFT JOIN ref r3 ON t.token = r3.id
+	LEFT JOIN ref r4 ON t.sender = r4.id
+	LEFT JOIN ref r5 ON t.recipient = r5.id`
+
+	if filter == nil {
+		return db.queryTokenTransfers(ctx, fmt.Sprintf(query, "token_transfer"))
+	}
+
+	cond, args := logsCondition(filter.Range, filter.CriteriaSet)
+	subQuery := "SELECT seq FROM token_transfer WHERE " + cond
+
+	if filter.Options != nil && filter.Options.Cursor != nil {
+		if filter.Order == DESC {
+			subQuery += " AND seq < ?"
+		} else {
+			subQuery += " AND seq > ?"
+		}
+		args = append(args, filter.Options.Cursor.seq)
+	}
+
+	// if there is limit option, set order inside subquery
+	if filter.Options != nil {
+		if filter.Order == DESC {
+			subQuery += " ORDER BY seq DESC"
+		} else {
+			subQuery += " ORDER BY seq ASC"
+		}
+		subQuery += " LIMIT ?, ?"
+		args = append(args, filter.Options.Offset, filter.Options.Limit)
+	}
+
+	subQuery = "SELECT e.* FROM (" + subQuery + ") s LEFT JOIN token_transfer e ON s.seq = e.seq"
 	transferQuery := fmt.Sprintf(query, subQuery)
 	// if there is no limit option, set order outside
 	if filter.Options == nil {
@@ -225,7 +446,122 @@ FROM (%v) t
 			transferQuery += " ORDER BY seq ASC "
 		}
 	}
-	return db.queryTransfers(ctx, transferQuery, args...)
+	return db.queryTokenTransfers(ctx, transferQuery, args...)
+}
+
+// logsCondition returns the condition of logs within the range and matching any of the criteria.
+func logsCondition[C interface {
+	toWhereCondition() (string, []interface{})
+}](rng *Range, criteriaSet []C) (cond string, args []interface{}) {
+	cond = "1"
+	if rng != nil {
+		cond += " AND seq >= ?"
+		args = append(args, newSequence(rng.From, 0))
+		if rng.To >= rng.From {
+			cond += " AND seq <= ?"
+			args = append(args, newSequence(rng.To, uint32(math.MaxInt32)))
+		}
+	}
+
+	if len(criteriaSet) > 0 {
+		cond += " AND ("
+		for i, c := range criteriaSet {
+			ccond, cargs := c.toWhereCondition()
+			if i > 0 {
+				cond += " OR"
+			}
+			cond += " (" + ccond + ")"
+			args = append(args, cargs...)
+		}
+		cond += ")"
+	}
+	return
+}
+
+// aggregateQuery builds the query of the aggregated columns of logs in the table, grouped by buckets of block time.
+func aggregateQuery[C interface {
+	toWhereCondition() (string, []interface{})
+}](table, columns string, rng *Range, criteriaSet []C, bucket uint64, opts *Options, order Order) (string, []interface{}) {
+	cond, cargs := logsCondition(rng, criteriaSet)
+
+	var (
+		query string
+		args  []interface{}
+	)
+	if bucket > 0 {
+		query = "SELECT blockTime / ? * ?, " + columns + " FROM " + table + " WHERE " + cond + " GROUP BY 1"
+		args = append(append(args, bucket, bucket), cargs...)
+		if order == DESC {
+			query += " ORDER BY 1 DESC"
+		} else {
+			query += " ORDER BY 1 ASC"
+		}
+	} else {
+		query = "SELECT 0, " + columns + " FROM " + table + " WHERE " + cond
+		args = cargs
+	}
+	if opts != nil {
+		query += " LIMIT ?, ?"
+		args = append(args, opts.Offset, opts.Limit)
+	}
+	return query, args
+}
+
+func (db *LogDB) CountEvents(ctx context.Context, filter *EventFilter, bucket uint64) ([]*EventCount, error) {
+	if filter == nil {
+		filter = &EventFilter{}
+	}
+	query, args := aggregateQuery("event", "COUNT(*)", filter.Range, filter.CriteriaSet, bucket, filter.Options, filter.Order)
+
+	rows, err := db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var counts []*EventCount
+	for rows.Next() {
+		var count EventCount
+		if err := rows.Scan(&count.BucketTime, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, &count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (db *LogDB) SummarizeTransfers(ctx context.Context, filter *TransferFilter, bucket uint64) ([]*TransferSummary, error) {
+	if filter == nil {
+		filter = &TransferFilter{}
+	}
+	query, args := aggregateQuery("transfer", "COUNT(*), sum_amount(amount), COUNT(DISTINCT sender)",
+		filter.Range, filter.CriteriaSet, bucket, filter.Options, filter.Order)
+
+	rows, err := db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var summaries []*TransferSummary
+	for rows.Next() {
+		var (
+			summary TransferSummary
+			amount  []byte
+		)
+		if err := rows.Scan(&summary.BucketTime, &summary.Count, &amount, &summary.Senders); err != nil {
+			return nil, err
+		}
+		summary.Amount = new(big.Int).SetBytes(amount)
+		summaries = append(summaries, &summary)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return summaries, nil
 }
 
 func (db *LogDB) queryEvents(ctx context.Context, query string, args ...interface{}) ([]*Event, error) {
@@ -352,213 +688,758 @@ func (db *LogDB) queryTransfers(ctx context.Context, query string, args ...inter
 	return transfers, nil
 }
 
-// NewestBlockID query newest written block id.
-func (db *LogDB) NewestBlockID() (thor.Bytes32, error) {
-	var data []byte
-	row := db.stmtCache.MustPrepare(`SELECT MAX(data) FROM (
-			SELECT data FROM ref WHERE id=(SELECT blockId FROM transfer ORDER BY seq DESC LIMIT 1)
-			UNION
-			SELECT data FROM ref WHERE id=(SELECT blockId FROM event ORDER BY seq DESC LIMIT 1))`).QueryRow()
-
-	if err := row.Scan(&data); err != nil {
-		if sql.ErrNoRows != err {
-			return thor.Bytes32{}, err
-		}
-	}
-
-	return thor.BytesToBytes32(data), nil
-}
-
-// HasBlockID query whether given block id related logs were written.
-func (db *LogDB) HasBlockID(id thor.Bytes32) (bool, error) {
-	const query = `SELECT COUNT(*) FROM (
-		SELECT * FROM (SELECT seq FROM transfer WHERE seq=? AND blockID=` + refIDQuery + ` LIMIT 1) 
-		UNION
-		SELECT * FROM (SELECT seq FROM event WHERE seq=? AND blockID=` + refIDQuery + ` LIMIT 1))`
-
-	seq := newSequence(block.Number(id), 0)
-	row := db.stmtCache.MustPrepare(query).QueryRow(seq, id[:], seq, id[:])
-	var count int
-	if err := row.Scan(&count); err != nil {
-		// no need to check ErrNoRows
-		return false, err
-	}
-	return count > 0, nil
-}
-
-// NewWriter creates a log writer.
-func (db *LogDB) NewWriter() *Writer {
-	return &Writer{conn: db.wconn, stmtCache: db.stmtCache}
-}
-
-// NewWriterSyncOff creates a log writer which applied 'pragma synchronous = off'.
-func (db *LogDB) NewWriterSyncOff() *Writer {
-	return &Writer{conn: db.wconnSyncOff, stmtCache: db.stmtCache}
-}
-
-func topicValue(topics []thor.Bytes32, i int) []byte {
-	if i < len(topics) {
-		return topics[i][:]
+func (db *LogDB) queryDeployments(ctx context.Context, query string, args ...interface{}) ([]*Deployment, error) {
+	rows, err := db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
-	return nil
-}
+	defer func() { _ = rows.Close() }()
+	var deployments []*Deployment
+	for rows.Next() {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		var (
+			seq         sequence
+			blockID     []byte
+			blockTime   uint64
+			txID        []byte
+			txOrigin    []byte
+			clauseIndex uint32
+			address     []byte
+		)
+		if err := rows.Scan(
+			&seq,
+			&blockID,
+			&blockTime,
+			&txID,
+			&txOrigin,
+			&clauseIndex,
+			&address,
+		); err != nil {
+			return nil, err
+		}
+		deployments = append(deployments, &Deployment{
+			BlockNumber: seq.BlockNumber(),
+			Index:       seq.Index(),
+			BlockID:     thor.BytesToBytes32(blockID),
+			BlockTime:   blockTime,
+			TxID:        thor.BytesToBytes32(txID),
+			TxOrigin:    thor.BytesToAddress(txOrigin),
+			ClauseIndex: clauseIndex,
+			Address:     thor.BytesToAddress(address),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
 
-// Writer is the transactional log writer.
-type Writer struct {
-	conn      *sql.Conn
-	stmtCache *stmtCache
+func (db *LogDB) queryFailures(ctx context.Context, query string, args ...interface{}) ([]*Failure, error) {
+	rows, err := db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var failures []*Failure
+	for rows.Next() {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		var (
+			seq         sequence
+			blockID     []byte
+			blockTime   uint64
+			txID        []byte
+			txOrigin    []byte
+			clauseIndex uint32
+			recipient   []byte
+			selector    []byte
+		)
+		if err := rows.Scan(
+			&seq,
+			&blockID,
+			&blockTime,
+			&txID,
+			&txOrigin,
+			&clauseIndex,
+			&recipient,
+			&selector,
+		); err != nil {
+			return nil, err
+		}
+		failure := &Failure{
+			BlockNumber: seq.BlockNumber(),
+			Index:       seq.Index(),
+			BlockID:     thor.BytesToBytes32(blockID),
+			BlockTime:   blockTime,
+			TxID:        thor.BytesToBytes32(txID),
+			TxOrigin:    thor.BytesToAddress(txOrigin),
+			ClauseIndex: clauseIndex,
+		}
+		if len(recipient) > 0 {
+			to := thor.BytesToAddress(recipient)
+			failure.To = &to
+		}
+		if len(selector) == 4 {
+			failure.Selector = (*[4]byte)(selector)
+		}
+		failures = append(failures, failure)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return failures, nil
+}
 
-	tx               *sql.Tx
-	uncommittedCount int
+func (db *LogDB) queryTokenTransfers(ctx context.Context, query string, args ...interface{}) ([]*TokenTransfer, error) {
+	rows, err := db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var transfers []*TokenTransfer
+	for rows.Next() {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		var (
+			seq         sequence
+			blockID     []byte
+			blockTime   uint64
+			txID        []byte
+			txOrigin    []byte
+			clauseIndex uint32
+			token       []byte
+			sender      []byte
+			recipient   []byte
+			amount      []byte
+		)
+		if err := rows.Scan(
+			&seq,
+			&blockID,
+			&blockTime,
+			&txID,
+			&txOrigin,
+			&clauseIndex,
+			&token,
+			&sender,
+			&recipient,
+			&amount,
+		); err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, &TokenTransfer{
+			BlockNumber: seq.BlockNumber(),
+			Index:       seq.Index(),
+			BlockID:     thor.BytesToBytes32(blockID),
+			BlockTime:   blockTime,
+			TxID:        thor.BytesToBytes32(txID),
+			TxOrigin:    thor.BytesToAddress(txOrigin),
+			ClauseIndex: clauseIndex,
+			Token:       thor.BytesToAddress(token),
+			Sender:      thor.BytesToAddress(sender),
+			Recipient:   thor.BytesToAddress(recipient),
+			Amount:      new(big.Int).SetBytes(amount),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return transfers, nil
 }
 
-// Truncate truncates the database by deleting logs after blockNum (included).
-func (w *Writer) Truncate(blockNum uint32) error {
-	seq := newSequence(blockNum, 0)
-	if err := w.exec("DELETE FROM event WHERE seq >= ?", seq); err != nil {
+// NewestBlockID query newest written block id.
+func (db *LogDB) NewestBlockID() (thor.Bytes32, error) {
+	var data []byte
+	row := db.stmtCache.MustPrepare(`SELECT MAX(data) FROM (
+			SELECT data FROM ref WHERE id=(SELECT blockId FROM transfer ORDER BY seq DESC LIMIT 1)
+			UNION
+			SELECT data FROM ref WHERE id=(SELECT blockId FROM event ORDER BY seq DESC LIMIT 1)
+			UNION
+			SELECT data FROM ref WHERE id=(SELECT blockId FROM deployment ORDER BY seq DESC LIMIT 1)
+			UNION
+			SELECT data FROM ref WHERE id=(SELECT blockId FROM failure ORDER BY seq DESC LIMIT 1)
+			UNION
+			SELECT data FROM ref WHERE id=(SELECT blockId FROM token_transfer ORDER BY seq DESC LIMIT 1))`).QueryRow()
+
+	if err := row.Scan(&data); err != nil {
+		if sql.ErrNoRows != err {
+			return thor.Bytes32{}, err
+		}
+	}
+
+	return thor.BytesToBytes32(data), nil
+}
+
+// HasBlockID query whether given block id related logs were written.
+func (db *LogDB) HasBlockID(id thor.Bytes32) (bool, error) {
+	const query = `SELECT COUNT(*) FROM (
+		SELECT * FROM (SELECT seq FROM transfer WHERE seq=? AND blockID=` + refIDQuery + ` LIMIT 1) 
+		UNION
+		SELECT * FROM (SELECT seq FROM event WHERE seq=? AND blockID=` + refIDQuery + ` LIMIT 1)
+		UNION
+		SELECT * FROM (SELECT seq FROM deployment WHERE seq=? AND blockID=` + refIDQuery + ` LIMIT 1)
+		UNION
+		SELECT * FROM (SELECT seq FROM failure WHERE seq=? AND blockID=` + refIDQuery + ` LIMIT 1)
+		UNION
+		SELECT * FROM (SELECT seq FROM token_transfer WHERE seq=? AND blockID=` + refIDQuery + ` LIMIT 1))`
+
+	seq := newSequence(block.Number(id), 0)
+	row := db.stmtCache.MustPrepare(query).QueryRow(seq, id[:], seq, id[:], seq, id[:], seq, id[:], seq, id[:])
+	var count int
+	if err := row.Scan(&count); err != nil {
+		// no need to check ErrNoRows
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// PruneStatus returns the status of pruned logs.
+func (db *LogDB) PruneStatus() *PruneStatus {
+	return db.pruneStatus.Load()
+}
+
+// Prune deletes logs of blocks before blockNum, except events emitted by and transfers from or to the kept addresses.
+func (db *LogDB) Prune(ctx context.Context, blockNum uint32, keep []thor.Address) error {
+	// raise the pruned block number first, to reject queries of logs being deleted
+	status := db.PruneStatus().next(blockNum, keep)
+	if err := db.execPrune(ctx, status, nil); err != nil {
 		return err
 	}
-	if err := w.exec("DELETE FROM transfer WHERE seq >= ?", seq); err != nil {
+
+	var (
+		eventQuery    = "DELETE FROM event WHERE seq >= ? AND seq < ?"
+		transferQuery = "DELETE FROM transfer WHERE seq >= ? AND seq < ?"
+		failureQuery  = "DELETE FROM failure WHERE seq >= ? AND seq < ?"
+		tokenQuery    = "DELETE FROM token_transfer WHERE seq >= ? AND seq < ?"
+		keepArgs      []interface{}
+	)
+	if len(keep) > 0 {
+		eventQuery += " AND address NOT IN " + refIDSetQuery(len(keep))
+		transferQuery += " AND sender NOT IN " + refIDSetQuery(len(keep)) + " AND recipient NOT IN " + refIDSetQuery(len(keep))
+		failureQuery += " AND (recipient IS NULL OR recipient NOT IN " + refIDSetQuery(len(keep)) + ")"
+		tokenQuery += " AND token NOT IN " + refIDSetQuery(len(keep)) +
+			" AND sender NOT IN " + refIDSetQuery(len(keep)) + " AND recipient NOT IN " + refIDSetQuery(len(keep))
+		for _, addr := range keep {
+			keepArgs = append(keepArgs, addr.Bytes())
+		}
+	}
+
+	for status.Deleted < status.BlockNum {
+		next := *status
+		next.Deleted += min(status.BlockNum-status.Deleted, pruneWindow)
+
+		from, to := newSequence(status.Deleted, 0), newSequence(next.Deleted, 0)
+		if err := db.execPrune(ctx, &next, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, eventQuery, append([]interface{}{from, to}, keepArgs...)...); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, transferQuery, append(append([]interface{}{from, to}, keepArgs...), keepArgs...)...); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, failureQuery, append([]interface{}{from, to}, keepArgs...)...); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, tokenQuery, append(append(append([]interface{}{from, to}, keepArgs...), keepArgs...), keepArgs...)...)
+			return err
+		}); err != nil {
+			return err
+		}
+		status = &next
+	}
+	return nil
+}
+
+// execPrune deletes logs and saves the prune status in a transaction.
+func (db *LogDB) execPrune(ctx context.Context, status *PruneStatus, del func(tx *sql.Tx) error) error {
+	// wait for writers to commit instead of blocking them
+	for !db.wlock.TryLock() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	defer db.wlock.Unlock()
+
+	data, err := rlp.EncodeToBytes(status)
+	if err != nil {
 		return err
 	}
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if del != nil {
+		if err := del(tx); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT OR REPLACE INTO prop(name, value) VALUES(?, ?)", pruneStatusName, data); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	db.pruneStatus.Store(status)
 	return nil
 }
 
-// Write writes all logs of the given block.
-func (w *Writer) Write(b *block.Block, receipts tx.Receipts) error {
-	var (
-		blockID        = b.Header().ID()
-		blockNum       = b.Header().Number()
-		blockTimestamp = b.Header().Timestamp()
-		txs            = b.Transactions()
-		eventCount,
-		transferCount uint32
-		isReceiptEmpty = func(r *tx.Receipt) bool {
-			for _, o := range r.Outputs {
-				if len(o.Events) > 0 || len(o.Transfers) > 0 {
-					return false
-				}
+// rebuildStatus is the progress of the unfinished rebuild.
+type rebuildStatus struct {
+	Next    uint32         // the number of the next block to write
+	Indexes []rebuildIndex // the dropped indexes
+}
+
+type rebuildIndex struct {
+	Name string
+	SQL  string // the statement to create the index
+}
+
+func (db *LogDB) loadRebuildStatus() (*rebuildStatus, error) {
+	var data []byte
+	if err := db.db.QueryRow("SELECT value FROM prop WHERE name = ?", rebuildStatusName).Scan(&data); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var status rebuildStatus
+	if err := rlp.DecodeBytes(data, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func saveRebuildStatus(tx *sql.Tx, status *rebuildStatus) error {
+	data, err := rlp.EncodeToBytes(status)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT OR REPLACE INTO prop(name, value) VALUES(?, ?)", rebuildStatusName, data)
+	return err
+}
+
+// Rebuilding returns whether a rebuild is unfinished.
+func (db *LogDB) Rebuilding() (bool, error) {
+	status, err := db.loadRebuildStatus()
+	return status != nil, err
+}
+
+// Rebuild starts rebuilding the db from scratch, or resumes the unfinished rebuild.
+// Indexes of logs are dropped, and created again when the rebuild is finished.
+func (db *LogDB) Rebuild() (Rebuilder, error) {
+	status, err := db.loadRebuildStatus()
+	if err != nil {
+		return nil, err
+	}
+	if status == nil {
+		if status, err = db.startRebuild(); err != nil {
+			return nil, err
+		}
+	} else {
+		// indexes are created again by the schema when the db is reopened
+		for _, index := range status.Indexes {
+			if _, err := db.db.Exec("DROP INDEX IF EXISTS " + index.Name); err != nil {
+				return nil, err
 			}
-			return true
 		}
+	}
+	return &rebuilder{db: db, status: status}, nil
+}
+
+// startRebuild deletes all logs and drops the indexes.
+func (db *LogDB) startRebuild() (*rebuildStatus, error) {
+	tx, err := db.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.Query("SELECT name, sql FROM sqlite_master WHERE type = 'index' AND tbl_name IN ('event', 'transfer', 'deployment', 'failure', 'token_transfer') AND sql IS NOT NULL")
+	if err != nil {
+		return nil, err
+	}
+	var status rebuildStatus
+	for rows.Next() {
+		var index rebuildIndex
+		if err := rows.Scan(&index.Name, &index.SQL); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		status.Indexes = append(status.Indexes, index)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, index := range status.Indexes {
+		if _, err := tx.Exec("DROP INDEX " + index.Name); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM event; DELETE FROM transfer; DELETE FROM deployment; DELETE FROM failure; DELETE FROM token_transfer; DELETE FROM ref;"); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("DELETE FROM prop WHERE name = ?", pruneStatusName); err != nil {
+		return nil, err
+	}
+	if err := saveRebuildStatus(tx, &status); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	db.pruneStatus.Store(&PruneStatus{})
+	return &status, nil
+}
+
+// rebuilder is the rebuilder of the SQLite log db.
+type rebuilder struct {
+	db     *LogDB
+	status *rebuildStatus
+}
+
+func (r *rebuilder) Next() uint32 {
+	return r.status.Next
+}
+
+func (r *rebuilder) Write(logs []*BlockLogs) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	tx, err := r.db.wconnSyncOff.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	exec := func(query string, args ...interface{}) error {
+		_, err := tx.Stmt(r.db.stmtCache.MustPrepare(query)).Exec(args...)
+		return err
+	}
+	status := *r.status
+	for _, l := range logs {
+		if n := block.Number(l.BlockID); n != status.Next {
+			return fmt.Errorf("unexpected block %v, %v expected", n, status.Next)
+		}
+		if err := insertLogs(exec, l, r.db.recording); err != nil {
+			return err
+		}
+		status.Next++
+	}
+	if err := saveRebuildStatus(tx, &status); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	r.status = &status
+	return nil
+}
+
+func (r *rebuilder) Finish() error {
+	tx, err := r.db.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, index := range r.status.Indexes {
+		if _, err := tx.Exec(index.SQL); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM prop WHERE name = ?", rebuildStatusName); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RecordFailures sets whether failures are written.
+func (db *LogDB) RecordFailures(enabled bool) {
+	db.recording.failures = enabled
+}
+
+// RecordTokenTransfers sets whether token transfers are written.
+func (db *LogDB) RecordTokenTransfers(enabled bool) {
+	db.recording.tokenTransfers = enabled
+}
+
+// NewWriter creates a log writer.
+func (db *LogDB) NewWriter() Writer {
+	return &writer{conn: db.wconn, stmtCache: db.stmtCache, written: &db.written, wlock: &db.wlock, recording: db.recording}
+}
+
+// NewWriterSyncOff creates a log writer which applied 'pragma synchronous = off'.
+func (db *LogDB) NewWriterSyncOff() Writer {
+	return &writer{conn: db.wconnSyncOff, stmtCache: db.stmtCache, written: &db.written, wlock: &db.wlock, recording: db.recording}
+}
+
+func topicValue(topic *thor.Bytes32) []byte {
+	if topic != nil {
+		return topic[:]
+	}
+	return nil
+}
+
+// insertLogs inserts logs of a block by exec, along with the optional logs being recorded.
+func insertLogs(exec func(query string, args ...interface{}) error, logs *BlockLogs, rec recording) error {
+	var (
+		failures       []*Failure
+		tokenTransfers []*TokenTransfer
 	)
+	if rec.failures {
+		failures = logs.Failures
+	}
+	if rec.tokenTransfers {
+		tokenTransfers = logs.TokenTransfers
+	}
+	if len(logs.Events) == 0 && len(logs.Transfers) == 0 && len(logs.Deployments) == 0 && len(failures) == 0 && len(tokenTransfers) == 0 {
+		return nil
+	}
+	if err := exec("INSERT OR IGNORE INTO ref(data) VALUES(?)", logs.BlockID[:]); err != nil {
+		return err
+	}
 
-	for i, r := range receipts {
-		if isReceiptEmpty(r) {
-			continue
+	for _, ev := range logs.Events {
+		if err := exec(
+			"INSERT OR IGNORE INTO ref(data) VALUES(?),(?),(?),(?),(?),(?),(?),(?)",
+			ev.TxID[:],
+			ev.TxOrigin[:],
+			ev.Address[:],
+			topicValue(ev.Topics[0]),
+			topicValue(ev.Topics[1]),
+			topicValue(ev.Topics[2]),
+			topicValue(ev.Topics[3]),
+			topicValue(ev.Topics[4])); err != nil {
+			return err
 		}
 
-		if eventCount == 0 && transferCount == 0 {
-			// block id is not yet inserted
-			if err := w.exec(
-				"INSERT OR IGNORE INTO ref(data) VALUES(?)",
-				blockID[:]); err != nil {
-				return err
-			}
+		const query = "INSERT OR IGNORE INTO event(seq, blockTime, clauseIndex, data, blockID, txID, txOrigin, address, topic0, topic1, topic2, topic3, topic4) " +
+			"VALUES(?,?,?,?," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + ")"
+
+		if err := exec(
+			query,
+			newSequence(ev.BlockNumber, ev.Index),
+			ev.BlockTime,
+			ev.ClauseIndex,
+			ev.Data,
+			ev.BlockID[:],
+			ev.TxID[:],
+			ev.TxOrigin[:],
+			ev.Address[:],
+			topicValue(ev.Topics[0]),
+			topicValue(ev.Topics[1]),
+			topicValue(ev.Topics[2]),
+			topicValue(ev.Topics[3]),
+			topicValue(ev.Topics[4])); err != nil {
+			return err
 		}
+	}
 
-		var (
-			txID     thor.Bytes32
-			txOrigin thor.Address
-		)
-		if i < len(txs) { // block 0 has no tx, but has receipts
-			tx := txs[i]
-			txID = tx.ID()
-			txOrigin, _ = tx.Origin()
-		}
-		if err := w.exec(
-			"INSERT OR IGNORE INTO ref(data) VALUES(?),(?)",
-			txID[:], txOrigin[:]); err != nil {
+	for _, tr := range logs.Transfers {
+		if err := exec(
+			"INSERT OR IGNORE INTO ref(data) VALUES(?),(?),(?),(?)",
+			tr.TxID[:],
+			tr.TxOrigin[:],
+			tr.Sender[:],
+			tr.Recipient[:]); err != nil {
 			return err
 		}
+		const query = "INSERT OR IGNORE INTO transfer(seq, blockTime, clauseIndex, amount, blockID, txID, txOrigin, sender, recipient) " +
+			"VALUES(?,?,?,?," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + ")"
 
-		for clauseIndex, output := range r.Outputs {
-			for _, ev := range output.Events {
-				if err := w.exec(
-					"INSERT OR IGNORE INTO ref (data) VALUES(?),(?),(?),(?),(?),(?)",
-					ev.Address[:],
-					topicValue(ev.Topics, 0),
-					topicValue(ev.Topics, 1),
-					topicValue(ev.Topics, 2),
-					topicValue(ev.Topics, 3),
-					topicValue(ev.Topics, 4)); err != nil {
-					return err
-				}
-
-				const query = "INSERT OR IGNORE INTO event(seq, blockTime, clauseIndex, data, blockID, txID, txOrigin, address, topic0, topic1, topic2, topic3, topic4) " +
-					"VALUES(?,?,?,?," +
-					refIDQuery + "," +
-					refIDQuery + "," +
-					refIDQuery + "," +
-					refIDQuery + "," +
-					refIDQuery + "," +
-					refIDQuery + "," +
-					refIDQuery + "," +
-					refIDQuery + "," +
-					refIDQuery + ")"
-
-				var eventData []byte
-				if len(ev.Data) > 0 {
-					eventData = ev.Data
-				}
-
-				if err := w.exec(
-					query,
-					newSequence(blockNum, eventCount),
-					blockTimestamp,
-					clauseIndex,
-					eventData,
-					blockID[:],
-					txID[:],
-					txOrigin[:],
-					ev.Address[:],
-					topicValue(ev.Topics, 0),
-					topicValue(ev.Topics, 1),
-					topicValue(ev.Topics, 2),
-					topicValue(ev.Topics, 3),
-					topicValue(ev.Topics, 4)); err != nil {
-					return err
-				}
-				eventCount++
-			}
+		if err := exec(
+			query,
+			newSequence(tr.BlockNumber, tr.Index),
+			tr.BlockTime,
+			tr.ClauseIndex,
+			tr.Amount.Bytes(),
+			tr.BlockID[:],
+			tr.TxID[:],
+			tr.TxOrigin[:],
+			tr.Sender[:],
+			tr.Recipient[:]); err != nil {
+			return err
+		}
+	}
 
-			for _, tr := range output.Transfers {
-				if err := w.exec(
-					"INSERT OR IGNORE INTO ref (data) VALUES(?),(?)",
-					tr.Sender[:],
-					tr.Recipient[:]); err != nil {
-					return err
-				}
-				const query = "INSERT OR IGNORE INTO transfer(seq, blockTime, clauseIndex, amount, blockID, txID, txOrigin, sender, recipient) " +
-					"VALUES(?,?,?,?," +
-					refIDQuery + "," +
-					refIDQuery + "," +
-					refIDQuery + "," +
-					refIDQuery + "," +
-					refIDQuery + ")"
-
-				if err := w.exec(
-					query,
-					newSequence(blockNum, transferCount),
-					blockTimestamp,
-					clauseIndex,
-					tr.Amount.Bytes(),
-					blockID[:],
-					txID[:],
-					txOrigin[:],
-					tr.Sender[:],
-					tr.Recipient[:]); err != nil {
-					return err
-				}
-				transferCount++
-			}
+	for _, d := range logs.Deployments {
+		if err := exec(
+			"INSERT OR IGNORE INTO ref(data) VALUES(?),(?),(?)",
+			d.TxID[:],
+			d.TxOrigin[:],
+			d.Address[:]); err != nil {
+			return err
+		}
+		const query = "INSERT OR IGNORE INTO deployment(seq, blockTime, clauseIndex, blockID, txID, txOrigin, address) " +
+			"VALUES(?,?,?," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + ")"
+
+		if err := exec(
+			query,
+			newSequence(d.BlockNumber, d.Index),
+			d.BlockTime,
+			d.ClauseIndex,
+			d.BlockID[:],
+			d.TxID[:],
+			d.TxOrigin[:],
+			d.Address[:]); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range failures {
+		var recipient, selector []byte
+		if f.To != nil {
+			recipient = f.To[:]
+		}
+		if f.Selector != nil {
+			selector = f.Selector[:]
+		}
+		if err := exec(
+			"INSERT OR IGNORE INTO ref(data) VALUES(?),(?),(?)",
+			f.TxID[:],
+			f.TxOrigin[:],
+			recipient); err != nil {
+			return err
+		}
+		const query = "INSERT OR IGNORE INTO failure(seq, blockTime, clauseIndex, selector, blockID, txID, txOrigin, recipient) " +
+			"VALUES(?,?,?,?," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + ")"
+
+		if err := exec(
+			query,
+			newSequence(f.BlockNumber, f.Index),
+			f.BlockTime,
+			f.ClauseIndex,
+			selector,
+			f.BlockID[:],
+			f.TxID[:],
+			f.TxOrigin[:],
+			recipient); err != nil {
+			return err
+		}
+	}
+
+	for _, tr := range tokenTransfers {
+		if err := exec(
+			"INSERT OR IGNORE INTO ref(data) VALUES(?),(?),(?),(?),(?)",
+			tr.TxID[:],
+			tr.TxOrigin[:],
+			tr.Token[:],
+			tr.Sender[:],
+			tr.Recipient[:]); err != nil {
+			return err
+		}
+		const query = "INSERT OR IGNORE INTO token_transfer(seq, blockTime, clauseIndex, amount, blockID, txID, txOrigin, token, sender, recipient) " +
+			"VALUES(?,?,?,?," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + "," +
+			refIDQuery + ")"
+
+		if err := exec(
+			query,
+			newSequence(tr.BlockNumber, tr.Index),
+			tr.BlockTime,
+			tr.ClauseIndex,
+			tr.Amount.Bytes(),
+			tr.BlockID[:],
+			tr.TxID[:],
+			tr.TxOrigin[:],
+			tr.Token[:],
+			tr.Sender[:],
+			tr.Recipient[:]); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// writer is the transactional log writer of the SQLite log db.
+type writer struct {
+	conn      *sql.Conn
+	stmtCache *stmtCache
+	written   *atomic.Pointer[thor.Bytes32]
+	wlock     *sync.RWMutex
+	recording recording
+
+	tx               *sql.Tx
+	uncommittedCount int
+	lastBlockID      *thor.Bytes32 // the last block written since the last commit
+}
+
+// Truncate truncates the database by deleting logs after blockNum (included).
+func (w *writer) Truncate(blockNum uint32) error {
+	seq := newSequence(blockNum, 0)
+	if err := w.exec("DELETE FROM event WHERE seq >= ?", seq); err != nil {
+		return err
+	}
+	if err := w.exec("DELETE FROM transfer WHERE seq >= ?", seq); err != nil {
+		return err
+	}
+	if err := w.exec("DELETE FROM deployment WHERE seq >= ?", seq); err != nil {
+		return err
+	}
+	if err := w.exec("DELETE FROM failure WHERE seq >= ?", seq); err != nil {
+		return err
+	}
+	if err := w.exec("DELETE FROM token_transfer WHERE seq >= ?", seq); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Write writes all logs of the given block.
+func (w *writer) Write(b *block.Block, receipts tx.Receipts) error {
+	blockID := b.Header().ID()
+	w.lastBlockID = &blockID
+	return insertLogs(w.exec, NewBlockLogs(b, receipts), w.recording)
+}
+
 // Commit commits accumulated logs.
-func (w *Writer) Commit() (err error) {
+func (w *writer) Commit() (err error) {
+	defer func() {
+		if err == nil && w.lastBlockID != nil {
+			w.written.Store(w.lastBlockID)
+			w.lastBlockID = nil
+		}
+	}()
 	if w.tx == nil {
 		return nil
 	}
@@ -567,13 +1448,15 @@ func (w *Writer) Commit() (err error) {
 		if err == nil {
 			w.tx = nil
 			w.uncommittedCount = 0
+			w.wlock.RUnlock()
 		}
 	}()
 	return w.tx.Commit()
 }
 
 // Rollback rollback all uncommitted logs.
-func (w *Writer) Rollback() (err error) {
+func (w *writer) Rollback() (err error) {
+	w.lastBlockID = nil
 	if w.tx == nil {
 		return nil
 	}
@@ -581,19 +1464,22 @@ func (w *Writer) Rollback() (err error) {
 		if err == nil {
 			w.tx = nil
 			w.uncommittedCount = 0
+			w.wlock.RUnlock()
 		}
 	}()
 	return w.tx.Rollback()
 }
 
 // UncommittedCount returns the count of uncommitted logs.
-func (w *Writer) UncommittedCount() int {
+func (w *writer) UncommittedCount() int {
 	return w.uncommittedCount
 }
 
-func (w *Writer) exec(query string, args ...interface{}) (err error) {
+func (w *writer) exec(query string, args ...interface{}) (err error) {
 	if w.tx == nil {
+		w.wlock.RLock()
 		if w.tx, err = w.conn.BeginTx(context.Background(), nil); err != nil {
+			w.wlock.RUnlock()
 			return
 		}
 	}
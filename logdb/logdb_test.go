@@ -7,9 +7,13 @@ package logdb_test
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"crypto/rand"
+	"math"
 	"math/big"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
@@ -27,6 +31,18 @@ func newTx() *tx.Transaction {
 	return tx.WithSignature(sig)
 }
 
+// newDeployTx creates a tx of clauses creating a contract, calling the address and creating again.
+func newDeployTx(pk *ecdsa.PrivateKey, nonce uint64, to thor.Address) *tx.Transaction {
+	trx := new(tx.Builder).
+		Clause(tx.NewClause(nil)).
+		Clause(tx.NewClause(&to)).
+		Clause(tx.NewClause(nil)).
+		Nonce(nonce).
+		Build()
+	sig, _ := crypto.Sign(trx.Hash().Bytes(), pk)
+	return trx.WithSignature(sig)
+}
+
 func randAddress() (addr thor.Address) {
 	rand.Read(addr[:])
 	return
@@ -120,12 +136,28 @@ func (logs transferLogs) Reverse() (ret transferLogs) {
 	return
 }
 
-func TestEvents(t *testing.T) {
-	db, err := logdb.NewMem()
-	if err != nil {
-		t.Fatal(err)
+// forEachStore runs the test against each backend of the log store.
+func forEachStore(t *testing.T, test func(*testing.T, logdb.LogStore)) {
+	for name, open := range map[string]func() (logdb.LogStore, error){
+		"sqlite": func() (logdb.LogStore, error) { return logdb.NewMem() },
+		"kv":     func() (logdb.LogStore, error) { return logdb.NewKVMem(), nil },
+	} {
+		t.Run(name, func(t *testing.T) {
+			db, err := open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			test(t, db)
+		})
 	}
-	defer db.Close()
+}
+
+func TestEvents(t *testing.T) {
+	forEachStore(t, testEvents)
+}
+
+func testEvents(t *testing.T, db logdb.LogStore) {
 
 	b := new(block.Builder).Build()
 
@@ -195,12 +227,34 @@ func TestEvents(t *testing.T) {
 			{"query all events range", &logdb.EventFilter{Range: &logdb.Range{From: 10, To: 20}}, allEvents.Filter(func(ev *logdb.Event) bool { return ev.BlockNumber >= 10 && ev.BlockNumber <= 20 })},
 			{"query events with range and desc", &logdb.EventFilter{Range: &logdb.Range{From: 10, To: 20}, Order: logdb.DESC}, allEvents.Filter(func(ev *logdb.Event) bool { return ev.BlockNumber >= 10 && ev.BlockNumber <= 20 }).Reverse()},
 			{"query events with limit with desc", &logdb.EventFilter{Order: logdb.DESC, Options: &logdb.Options{Limit: 10}}, allEvents.Reverse()[0:10]},
+			{"query events with cursor", &logdb.EventFilter{Options: &logdb.Options{Limit: 10, Cursor: logdb.NewCursor(allEvents[9].BlockNumber, allEvents[9].Index)}}, allEvents[10:20]},
+			{"query events with cursor with desc", &logdb.EventFilter{Order: logdb.DESC, Options: &logdb.Options{Limit: 10, Cursor: logdb.NewCursor(allEvents[20].BlockNumber, allEvents[20].Index)}}, allEvents[10:20].Reverse()},
+			{"query events with cursor and range", &logdb.EventFilter{Range: &logdb.Range{From: 10, To: 20}, Options: &logdb.Options{Limit: 100, Cursor: logdb.NewCursor(15, 0)}}, allEvents.Filter(func(ev *logdb.Event) bool {
+				return (ev.BlockNumber == 15 && ev.Index > 0) || (ev.BlockNumber > 15 && ev.BlockNumber <= 20)
+			})},
 			{"query all events with criteria", &logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{Address: &allEvents[1].Address}}}, allEvents.Filter(func(ev *logdb.Event) bool {
 				return ev.Address == allEvents[1].Address
 			})},
 			{"query all events with multi-criteria", &logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{Address: &allEvents[1].Address}, {Topics: [5]*thor.Bytes32{allEvents[2].Topics[0]}}, {Topics: [5]*thor.Bytes32{allEvents[3].Topics[0]}}}}, allEvents.Filter(func(ev *logdb.Event) bool {
 				return ev.Address == allEvents[1].Address || *ev.Topics[0] == *allEvents[2].Topics[0] || *ev.Topics[0] == *allEvents[3].Topics[0]
 			})},
+			{"query events with tx id", &logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{TxID: &allEvents[4].TxID}}}, allEvents.Filter(func(ev *logdb.Event) bool {
+				return ev.TxID == allEvents[4].TxID
+			})},
+			{"query events with tx origin and range", &logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{TxOrigin: &allEvents[5].TxOrigin}}, Range: &logdb.Range{From: 0, To: 10}}, allEvents.Filter(func(ev *logdb.Event) bool {
+				return ev.TxOrigin == allEvents[5].TxOrigin
+			})},
+			{"query events with tx id and unmatched address", &logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{TxID: &allEvents[4].TxID, Address: &allEvents[5].Address}}}, nil},
+			{"query events with address set", &logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{AddressSet: []thor.Address{allEvents[1].Address, allEvents[6].Address}}}}, allEvents.Filter(func(ev *logdb.Event) bool {
+				return ev.Address == allEvents[1].Address || ev.Address == allEvents[6].Address
+			})},
+			{"query events with topic set", &logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{TopicSets: [5][]thor.Bytes32{{*allEvents[2].Topics[0], *allEvents[7].Topics[0]}}}}, Order: logdb.DESC}, allEvents.Filter(func(ev *logdb.Event) bool {
+				return *ev.Topics[0] == *allEvents[2].Topics[0] || *ev.Topics[0] == *allEvents[7].Topics[0]
+			}).Reverse()},
+			{"query events with address set and topic set", &logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{AddressSet: []thor.Address{allEvents[2].Address, allEvents[3].Address}, TopicSets: [5][]thor.Bytes32{{*allEvents[3].Topics[0], *allEvents[4].Topics[0]}}}}}, allEvents.Filter(func(ev *logdb.Event) bool {
+				return ev.Address == allEvents[3].Address && *ev.Topics[0] == *allEvents[3].Topics[0]
+			})},
+			{"query events with address and unmatched topic set", &logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{Address: &allEvents[1].Address, TopicSets: [5][]thor.Bytes32{{*allEvents[2].Topics[0]}}}}}, nil},
 		}
 
 		for _, tt := range tests {
@@ -226,6 +280,8 @@ func TestEvents(t *testing.T) {
 			{"query all transfers range", &logdb.TransferFilter{Range: &logdb.Range{From: 10, To: 20}}, allTransfers.Filter(func(tr *logdb.Transfer) bool { return tr.BlockNumber >= 10 && tr.BlockNumber <= 20 })},
 			{"query transfers with range and desc", &logdb.TransferFilter{Range: &logdb.Range{From: 10, To: 20}, Order: logdb.DESC}, allTransfers.Filter(func(tr *logdb.Transfer) bool { return tr.BlockNumber >= 10 && tr.BlockNumber <= 20 }).Reverse()},
 			{"query transfers with limit with desc", &logdb.TransferFilter{Order: logdb.DESC, Options: &logdb.Options{Limit: 10}}, allTransfers.Reverse()[0:10]},
+			{"query transfers with cursor", &logdb.TransferFilter{Options: &logdb.Options{Limit: 10, Cursor: logdb.NewCursor(allTransfers[9].BlockNumber, allTransfers[9].Index)}}, allTransfers[10:20]},
+			{"query transfers with cursor with desc", &logdb.TransferFilter{Order: logdb.DESC, Options: &logdb.Options{Limit: 10, Cursor: logdb.NewCursor(allTransfers[20].BlockNumber, allTransfers[20].Index)}}, allTransfers[10:20].Reverse()},
 			{"query all transfers with criteria", &logdb.TransferFilter{CriteriaSet: []*logdb.TransferCriteria{{Sender: &allTransfers[1].Sender}}}, allTransfers.Filter(func(tr *logdb.Transfer) bool {
 				return tr.Sender == allTransfers[1].Sender
 			})},
@@ -247,11 +303,10 @@ func TestEvents(t *testing.T) {
 // TestLogDB_NewestBlockID performs a series of read/write tests on the NewestBlockID functionality of the LogDB.
 // It validates the correctness of the NewestBlockID method under various scenarios.
 func TestLogDB_NewestBlockID(t *testing.T) {
-	db, err := logdb.NewMem()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer db.Close()
+	forEachStore(t, testLogDB_NewestBlockID)
+}
+
+func testLogDB_NewestBlockID(t *testing.T, db logdb.LogStore) {
 
 	b := new(block.Builder).Build()
 
@@ -370,11 +425,10 @@ func TestLogDB_NewestBlockID(t *testing.T) {
 
 // TestLogDB_HasBlockID performs a series of tests on the HasBlockID functionality of the LogDB.
 func TestLogDB_HasBlockID(t *testing.T) {
-	db, err := logdb.NewMem()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer db.Close()
+	forEachStore(t, testLogDB_HasBlockID)
+}
+
+func testLogDB_HasBlockID(t *testing.T, db logdb.LogStore) {
 
 	b0 := new(block.Builder).Build()
 
@@ -431,3 +485,828 @@ func TestLogDB_HasBlockID(t *testing.T) {
 	}
 	assert.True(t, has)
 }
+
+func TestLogDB_WrittenBlockID(t *testing.T) {
+	forEachStore(t, testLogDB_WrittenBlockID)
+}
+
+func testLogDB_WrittenBlockID(t *testing.T, db logdb.LogStore) {
+
+	assert.True(t, db.WrittenBlockID().IsZero())
+
+	b1 := new(block.Builder).
+		Transaction(newTx()).
+		Build()
+	w := db.NewWriter()
+	if err := w.Write(b1, tx.Receipts{newReceipt()}); err != nil {
+		t.Fatal(err)
+	}
+	// not committed yet
+	assert.True(t, db.WrittenBlockID().IsZero())
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, b1.Header().ID(), db.WrittenBlockID())
+
+	// block without logs is counted
+	b2 := new(block.Builder).ParentID(b1.Header().ID()).Build()
+	if err := w.Write(b2, tx.Receipts{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, b2.Header().ID(), db.WrittenBlockID())
+	newest, err := db.NewestBlockID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, b1.Header().ID(), newest)
+
+	// rolled back
+	b3 := new(block.Builder).ParentID(b2.Header().ID()).Build()
+	if err := w.Write(b3, tx.Receipts{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, b2.Header().ID(), db.WrittenBlockID())
+}
+
+func TestLogDB_Truncate(t *testing.T) {
+	forEachStore(t, testLogDB_Truncate)
+}
+
+func testLogDB_Truncate(t *testing.T, db logdb.LogStore) {
+	var blocks []*block.Block
+	b := new(block.Builder).Build()
+	w := db.NewWriter()
+	for i := 0; i < 3; i++ {
+		b = new(block.Builder).
+			ParentID(b.Header().ID()).
+			Transaction(newTx()).
+			Build()
+		blocks = append(blocks, b)
+		if err := w.Write(b, tx.Receipts{newReceipt()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// truncate committed logs and write a block on the new branch, along with uncommitted ones to be truncated
+	fork := new(block.Builder).
+		ParentID(blocks[0].Header().ID()).
+		Transaction(newTx()).
+		Build()
+	receipt := newEventOnlyReceipt()
+	if err := w.Write(fork, tx.Receipts{receipt}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Truncate(blocks[1].Header().Number()); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(fork, tx.Receipts{receipt}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := db.FilterEvents(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, events, 2)
+	assert.Equal(t, blocks[0].Header().ID(), events[0].BlockID)
+	assert.Equal(t, fork.Header().ID(), events[1].BlockID)
+
+	// indexes of truncated logs are removed as well
+	events, err = db.FilterEvents(context.Background(), &logdb.EventFilter{
+		CriteriaSet: []*logdb.EventCriteria{{Address: &receipt.Outputs[0].Events[0].Address}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, events, 1)
+
+	transfers, err := db.FilterTransfers(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, transfers, 1)
+	assert.Equal(t, blocks[0].Header().ID(), transfers[0].BlockID)
+
+	has, err := db.HasBlockID(blocks[2].Header().ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, has)
+	newest, err := db.NewestBlockID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fork.Header().ID(), newest)
+}
+
+func TestLogDB_Deployments(t *testing.T) {
+	forEachStore(t, testLogDB_Deployments)
+}
+
+func testLogDB_Deployments(t *testing.T, db logdb.LogStore) {
+	var (
+		ctx       = context.Background()
+		deployers = []*ecdsa.PrivateKey{}
+		to        = randAddress()
+	)
+	for i := 0; i < 2; i++ {
+		pk, _ := crypto.GenerateKey()
+		deployers = append(deployers, pk)
+	}
+	outputs := func() []*tx.Output { return []*tx.Output{{}, {}, {}} }
+
+	var (
+		blocks []*block.Block
+		all    []*logdb.Deployment
+	)
+	b := new(block.Builder).Build()
+	w := db.NewWriter()
+	for i := 0; i < 3; i++ {
+		deployTx, revertedTx := newDeployTx(deployers[i%2], uint64(i), to), newDeployTx(deployers[0], uint64(i+100), to)
+		b = new(block.Builder).
+			ParentID(b.Header().ID()).
+			Transaction(deployTx).
+			Transaction(revertedTx).
+			Build()
+		blocks = append(blocks, b)
+		if err := w.Write(b, tx.Receipts{{Outputs: outputs()}, {Reverted: true}}); err != nil {
+			t.Fatal(err)
+		}
+		origin, _ := deployTx.Origin()
+		for j, clauseIndex := range []uint32{0, 2} {
+			all = append(all, &logdb.Deployment{
+				BlockNumber: b.Header().Number(),
+				Index:       uint32(j),
+				BlockID:     b.Header().ID(),
+				BlockTime:   b.Header().Timestamp(),
+				TxID:        deployTx.ID(),
+				TxOrigin:    origin,
+				ClauseIndex: clauseIndex,
+				Address:     thor.CreateContractAddress(deployTx.ID(), clauseIndex, 0),
+			})
+		}
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := db.FilterDeployments(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, all, got)
+
+	deployer := all[2].TxOrigin
+	tests := []struct {
+		name   string
+		filter *logdb.DeploymentFilter
+		want   []*logdb.Deployment
+	}{
+		{"by address", &logdb.DeploymentFilter{CriteriaSet: []*logdb.DeploymentCriteria{{Address: &all[3].Address}}}, all[3:4]},
+		{"by deployer", &logdb.DeploymentFilter{CriteriaSet: []*logdb.DeploymentCriteria{{Deployer: &deployer}}}, all[2:4]},
+		{"by address and deployer", &logdb.DeploymentFilter{CriteriaSet: []*logdb.DeploymentCriteria{{Address: &all[0].Address, Deployer: &deployer}}}, nil},
+		{"by range", &logdb.DeploymentFilter{Range: &logdb.Range{From: 3, To: 4}}, all[2:]},
+		{"desc with options", &logdb.DeploymentFilter{Order: logdb.DESC, Options: &logdb.Options{Offset: 1, Limit: 2}}, []*logdb.Deployment{all[4], all[3]}},
+		{"after cursor", &logdb.DeploymentFilter{Options: &logdb.Options{Limit: 10, Cursor: logdb.NewCursor(all[1].BlockNumber, all[1].Index)}}, all[2:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.FilterDeployments(ctx, tt.filter)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// deployments are not pruned
+	if err := db.Prune(ctx, blocks[2].Header().Number(), nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err = db.FilterDeployments(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, all, got)
+
+	// but truncated along with indexes
+	if err := w.Truncate(blocks[2].Header().Number()); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	got, err = db.FilterDeployments(ctx, &logdb.DeploymentFilter{CriteriaSet: []*logdb.DeploymentCriteria{{Address: &all[4].Address}}})
+	assert.Nil(t, err)
+	assert.Empty(t, got)
+	newest, err := db.NewestBlockID()
+	assert.Nil(t, err)
+	assert.Equal(t, blocks[1].Header().ID(), newest)
+	has, err := db.HasBlockID(blocks[1].Header().ID())
+	assert.Nil(t, err)
+	assert.True(t, has)
+}
+
+func TestLogDB_Failures(t *testing.T) {
+	forEachStore(t, testLogDB_Failures)
+}
+
+func testLogDB_Failures(t *testing.T, db logdb.LogStore) {
+	var (
+		ctx      = context.Background()
+		pk, _    = crypto.GenerateKey()
+		to       = randAddress()
+		selector = [4]byte{0xa9, 0x05, 0x9c, 0xbb}
+	)
+	// a tx of clauses calling with a selector, creating, and calling with short data
+	newFailingTx := func(nonce uint64) *tx.Transaction {
+		trx := new(tx.Builder).
+			Clause(tx.NewClause(&to).WithData(append(selector[:], randBytes32().Bytes()...))).
+			Clause(tx.NewClause(nil)).
+			Clause(tx.NewClause(&to).WithData([]byte{1, 2})).
+			Nonce(nonce).
+			Build()
+		sig, _ := crypto.Sign(trx.SigningHash().Bytes(), pk)
+		return trx.WithSignature(sig)
+	}
+	writeBlock := func(parent *block.Block, nonce uint64) *block.Block {
+		failing := newFailingTx(nonce)
+		b := new(block.Builder).
+			ParentID(parent.Header().ID()).
+			Transaction(newTx()).
+			Transaction(failing).
+			Build()
+		w := db.NewWriter()
+		if err := w.Write(b, tx.Receipts{newReceipt(), {Reverted: true}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	// not recorded by default
+	b := writeBlock(new(block.Builder).Build(), 0)
+	got, err := db.FilterFailures(ctx, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, got)
+
+	db.RecordFailures(true)
+	var all []*logdb.Failure
+	for i := 1; i <= 3; i++ {
+		b = writeBlock(b, uint64(i))
+		failing := b.Transactions()[1]
+		origin, _ := failing.Origin()
+		for j, clause := range failing.Clauses() {
+			f := &logdb.Failure{
+				BlockNumber: b.Header().Number(),
+				Index:       uint32(j),
+				BlockID:     b.Header().ID(),
+				BlockTime:   b.Header().Timestamp(),
+				TxID:        failing.ID(),
+				TxOrigin:    origin,
+				ClauseIndex: uint32(j),
+				To:          clause.To(),
+			}
+			if j == 0 {
+				f.Selector = &selector
+			}
+			all = append(all, f)
+		}
+	}
+
+	got, err = db.FilterFailures(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, all, got)
+
+	origin := all[0].TxOrigin
+	tests := []struct {
+		name   string
+		filter *logdb.FailureFilter
+		want   []*logdb.Failure
+	}{
+		{"by to and selector", &logdb.FailureFilter{CriteriaSet: []*logdb.FailureCriteria{{To: &to, Selector: &selector}}}, []*logdb.Failure{all[0], all[3], all[6]}},
+		{"by origin", &logdb.FailureFilter{CriteriaSet: []*logdb.FailureCriteria{{TxOrigin: &origin}}, Range: &logdb.Range{From: 5, To: 5}}, all[6:]},
+		{"by selector", &logdb.FailureFilter{CriteriaSet: []*logdb.FailureCriteria{{Selector: &[4]byte{1, 2, 3, 4}}}}, nil},
+		{"desc with options", &logdb.FailureFilter{Order: logdb.DESC, Options: &logdb.Options{Offset: 1, Limit: 2}}, []*logdb.Failure{all[7], all[6]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.FilterFailures(ctx, tt.filter)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// pruned except those to kept addresses
+	if err := db.Prune(ctx, 5, []thor.Address{to}); err != nil {
+		t.Fatal(err)
+	}
+	got, err = db.FilterFailures(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []*logdb.Failure{all[0], all[2], all[3], all[5], all[6], all[7], all[8]}, got)
+	assert.False(t, db.PruneStatus().FailuresPruned(&logdb.FailureFilter{CriteriaSet: []*logdb.FailureCriteria{{To: &to}}}))
+	assert.True(t, db.PruneStatus().FailuresPruned(&logdb.FailureFilter{CriteriaSet: []*logdb.FailureCriteria{{TxOrigin: &origin}}}))
+
+	// truncated along with indexes
+	w := db.NewWriter()
+	if err := w.Truncate(5); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	got, err = db.FilterFailures(ctx, &logdb.FailureFilter{CriteriaSet: []*logdb.FailureCriteria{{To: &to}}})
+	assert.Nil(t, err)
+	assert.Equal(t, []*logdb.Failure{all[0], all[2], all[3], all[5]}, got)
+}
+
+func TestLogDB_TokenTransfers(t *testing.T) {
+	forEachStore(t, testLogDB_TokenTransfers)
+}
+
+func testLogDB_TokenTransfers(t *testing.T, db logdb.LogStore) {
+	var (
+		ctx        = context.Background()
+		topic      = thor.MustParseBytes32("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+		tokens     = []thor.Address{randAddress(), randAddress()}
+		alice, bob = randAddress(), randAddress()
+	)
+	transferEvent := func(token, from, to thor.Address, amount int64) *tx.Event {
+		return &tx.Event{
+			Address: token,
+			Topics:  []thor.Bytes32{topic, thor.BytesToBytes32(from[:]), thor.BytesToBytes32(to[:])},
+			Data:    thor.BytesToBytes32(big.NewInt(amount).Bytes()).Bytes(),
+		}
+	}
+	writeBlock := func(parent *block.Block) *block.Block {
+		b := new(block.Builder).
+			ParentID(parent.Header().ID()).
+			Transaction(newTx()).
+			Build()
+		nft := transferEvent(tokens[0], alice, bob, 0)
+		nft.Topics = append(nft.Topics, randBytes32())
+		nft.Data = nil
+		receipt := &tx.Receipt{Outputs: []*tx.Output{
+			{Events: tx.Events{transferEvent(tokens[0], alice, bob, 100), nft, newEventOnlyReceipt().Outputs[0].Events[0]}},
+			{Events: tx.Events{transferEvent(tokens[1], bob, alice, 200)}},
+		}}
+		w := db.NewWriter()
+		if err := w.Write(b, tx.Receipts{receipt}); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	// not recorded by default
+	b := writeBlock(new(block.Builder).Build())
+	got, err := db.FilterTokenTransfers(ctx, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, got)
+
+	db.RecordTokenTransfers(true)
+	var all []*logdb.TokenTransfer
+	for i := 0; i < 3; i++ {
+		b = writeBlock(b)
+		trx := b.Transactions()[0]
+		origin, _ := trx.Origin()
+		for j, tr := range []struct {
+			token, from, to thor.Address
+			amount          int64
+		}{{tokens[0], alice, bob, 100}, {tokens[1], bob, alice, 200}} {
+			all = append(all, &logdb.TokenTransfer{
+				BlockNumber: b.Header().Number(),
+				Index:       uint32(j),
+				BlockID:     b.Header().ID(),
+				BlockTime:   b.Header().Timestamp(),
+				TxID:        trx.ID(),
+				TxOrigin:    origin,
+				ClauseIndex: uint32(j),
+				Token:       tr.token,
+				Sender:      tr.from,
+				Recipient:   tr.to,
+				Amount:      big.NewInt(tr.amount),
+			})
+		}
+	}
+
+	got, err = db.FilterTokenTransfers(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, all, got)
+
+	tests := []struct {
+		name   string
+		filter *logdb.TokenTransferFilter
+		want   []*logdb.TokenTransfer
+	}{
+		{"by token", &logdb.TokenTransferFilter{CriteriaSet: []*logdb.TokenTransferCriteria{{Token: &tokens[1]}}}, []*logdb.TokenTransfer{all[1], all[3], all[5]}},
+		{"by sender and token", &logdb.TokenTransferFilter{CriteriaSet: []*logdb.TokenTransferCriteria{{Sender: &alice, Token: &tokens[1]}}}, nil},
+		{"by recipient in range", &logdb.TokenTransferFilter{CriteriaSet: []*logdb.TokenTransferCriteria{{Recipient: &bob}}, Range: &logdb.Range{From: 4, To: 5}}, []*logdb.TokenTransfer{all[2], all[4]}},
+		{"by either account", &logdb.TokenTransferFilter{CriteriaSet: []*logdb.TokenTransferCriteria{{Sender: &bob}, {Recipient: &bob}}, Options: &logdb.Options{Limit: 3}}, all[:3]},
+		{"desc by origin", &logdb.TokenTransferFilter{CriteriaSet: []*logdb.TokenTransferCriteria{{TxOrigin: &all[0].TxOrigin}}, Order: logdb.DESC}, []*logdb.TokenTransfer{all[1], all[0]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.FilterTokenTransfers(ctx, tt.filter)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// pruned except those of kept tokens
+	if err := db.Prune(ctx, 5, []thor.Address{tokens[0]}); err != nil {
+		t.Fatal(err)
+	}
+	got, err = db.FilterTokenTransfers(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []*logdb.TokenTransfer{all[0], all[2], all[4], all[5]}, got)
+	assert.False(t, db.PruneStatus().TokenTransfersPruned(&logdb.TokenTransferFilter{CriteriaSet: []*logdb.TokenTransferCriteria{{Token: &tokens[0], Sender: &bob}}}))
+	assert.True(t, db.PruneStatus().TokenTransfersPruned(&logdb.TokenTransferFilter{CriteriaSet: []*logdb.TokenTransferCriteria{{Sender: &bob}}}))
+
+	// truncated along with indexes
+	w := db.NewWriter()
+	if err := w.Truncate(5); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	got, err = db.FilterTokenTransfers(ctx, &logdb.TokenTransferFilter{CriteriaSet: []*logdb.TokenTransferCriteria{{Token: &tokens[0]}}})
+	assert.Nil(t, err)
+	assert.Equal(t, []*logdb.TokenTransfer{all[0], all[2]}, got)
+}
+
+func TestLogDB_Aggregate(t *testing.T) {
+	forEachStore(t, testLogDB_Aggregate)
+}
+
+func testLogDB_Aggregate(t *testing.T, db logdb.LogStore) {
+	var (
+		contracts = []thor.Address{randAddress(), randAddress()}
+		senders   = []thor.Address{randAddress(), randAddress(), randAddress()}
+		recipient = randAddress()
+	)
+
+	b := new(block.Builder).Build()
+	w := db.NewWriter()
+	for i := 0; i < 10; i++ {
+		b = new(block.Builder).
+			ParentID(b.Header().ID()).
+			Timestamp(uint64(100 + i*10)).
+			Transaction(newTx()).
+			Transaction(newTx()).
+			Build()
+
+		var receipts tx.Receipts
+		for j := 0; j < 2; j++ {
+			receipts = append(receipts, &tx.Receipt{
+				Outputs: []*tx.Output{{
+					Events: tx.Events{{Address: contracts[(i+j)%2], Topics: []thor.Bytes32{randBytes32()}}},
+					Transfers: tx.Transfers{{
+						Sender:    senders[(i+j)%3],
+						Recipient: recipient,
+						Amount:    big.NewInt(int64(i + 1)),
+					}},
+				}},
+			})
+		}
+		if err := w.Write(b, receipts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	t.Run("count events", func(t *testing.T) {
+		counts, err := db.CountEvents(ctx, nil, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []*logdb.EventCount{{BucketTime: 0, Count: 20}}, counts)
+
+		// by buckets of 30 seconds, blocks #2..#11 at 100..190
+		counts, err = db.CountEvents(ctx, &logdb.EventFilter{
+			CriteriaSet: []*logdb.EventCriteria{{Address: &contracts[0]}},
+		}, 30)
+		assert.Nil(t, err)
+		assert.Equal(t, []*logdb.EventCount{
+			{BucketTime: 90, Count: 2},
+			{BucketTime: 120, Count: 3},
+			{BucketTime: 150, Count: 3},
+			{BucketTime: 180, Count: 2},
+		}, counts)
+
+		counts, err = db.CountEvents(ctx, &logdb.EventFilter{
+			Range:   &logdb.Range{From: 2, To: 9},
+			Order:   logdb.DESC,
+			Options: &logdb.Options{Offset: 1, Limit: 2},
+		}, 30)
+		assert.Nil(t, err)
+		assert.Equal(t, []*logdb.EventCount{
+			{BucketTime: 120, Count: 6},
+			{BucketTime: 90, Count: 4},
+		}, counts)
+
+		unmatched := randAddress()
+		counts, err = db.CountEvents(ctx, &logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{Address: &unmatched}}}, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []*logdb.EventCount{{BucketTime: 0, Count: 0}}, counts)
+
+		counts, err = db.CountEvents(ctx, &logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{Address: &unmatched}}}, 30)
+		assert.Nil(t, err)
+		assert.Empty(t, counts)
+	})
+
+	t.Run("summarize transfers", func(t *testing.T) {
+		summaries, err := db.SummarizeTransfers(ctx, nil, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []*logdb.TransferSummary{{BucketTime: 0, Count: 20, Amount: big.NewInt(110), Senders: 3}}, summaries)
+
+		summaries, err = db.SummarizeTransfers(ctx, &logdb.TransferFilter{
+			CriteriaSet: []*logdb.TransferCriteria{{Sender: &senders[0]}},
+			Range:       &logdb.Range{From: 1, To: 5},
+		}, 50)
+		assert.Nil(t, err)
+		// sender 0 sends in blocks #2 (1st tx), #4 (2nd tx), #5 (1st tx)
+		assert.Equal(t, []*logdb.TransferSummary{
+			{BucketTime: 100, Count: 3, Amount: big.NewInt(1 + 3 + 4), Senders: 1},
+		}, summaries)
+
+		summaries, err = db.SummarizeTransfers(ctx, &logdb.TransferFilter{
+			CriteriaSet: []*logdb.TransferCriteria{{Recipient: &recipient}},
+		}, 50)
+		assert.Nil(t, err)
+		assert.Equal(t, []*logdb.TransferSummary{
+			{BucketTime: 100, Count: 10, Amount: big.NewInt(2 * (1 + 2 + 3 + 4 + 5)), Senders: 3},
+			{BucketTime: 150, Count: 10, Amount: big.NewInt(2 * (6 + 7 + 8 + 9 + 10)), Senders: 3},
+		}, summaries)
+
+		summaries, err = db.SummarizeTransfers(ctx, &logdb.TransferFilter{Range: &logdb.Range{From: 20, To: 30}}, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []*logdb.TransferSummary{{BucketTime: 0, Count: 0, Amount: new(big.Int), Senders: 0}}, summaries)
+	})
+}
+
+func TestLogDB_Prune(t *testing.T) {
+	forEachStore(t, testLogDB_Prune)
+}
+
+func testLogDB_Prune(t *testing.T, db logdb.LogStore) {
+	var (
+		contracts = []thor.Address{randAddress(), randAddress()}
+		senders   = []thor.Address{randAddress(), randAddress()}
+		ctx       = context.Background()
+	)
+
+	// blocks #2..#11, each with an event and a transfer
+	b := new(block.Builder).Build()
+	w := db.NewWriter()
+	for i := 0; i < 10; i++ {
+		b = new(block.Builder).
+			ParentID(b.Header().ID()).
+			Transaction(newTx()).
+			Build()
+		if err := w.Write(b, tx.Receipts{{
+			Outputs: []*tx.Output{{
+				Events:    tx.Events{{Address: contracts[i%2], Topics: []thor.Bytes32{randBytes32()}}},
+				Transfers: tx.Transfers{{Sender: senders[i%2], Recipient: randAddress(), Amount: big.NewInt(1)}},
+			}},
+		}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &logdb.PruneStatus{}, db.PruneStatus())
+
+	if err := db.Prune(ctx, 6, []thor.Address{contracts[0], senders[1]}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &logdb.PruneStatus{BlockNum: 6, Deleted: 6, Kept: []thor.Address{contracts[0], senders[1]}}, db.PruneStatus())
+
+	events, err := db.FilterEvents(ctx, nil)
+	assert.Nil(t, err)
+	assert.Len(t, events, 8)
+	for _, ev := range events {
+		assert.True(t, ev.BlockNumber >= 6 || ev.Address == contracts[0])
+	}
+	transfers, err := db.FilterTransfers(ctx, nil)
+	assert.Nil(t, err)
+	assert.Len(t, transfers, 8)
+	for _, tr := range transfers {
+		assert.True(t, tr.BlockNumber >= 6 || tr.Sender == senders[1])
+	}
+
+	// queries of pruned logs
+	status := db.PruneStatus()
+	assert.True(t, status.EventsPruned(nil))
+	assert.True(t, status.EventsPruned(&logdb.EventFilter{Range: &logdb.Range{From: 5, To: 10}}))
+	assert.False(t, status.EventsPruned(&logdb.EventFilter{Range: &logdb.Range{From: 6, To: 10}}))
+	assert.False(t, status.EventsPruned(&logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{Address: &contracts[0]}}}))
+	assert.True(t, status.EventsPruned(&logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{Address: &contracts[0]}, {Address: &contracts[1]}}}))
+	assert.True(t, status.EventsPruned(&logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{AddressSet: contracts}}}))
+	assert.False(t, status.TransfersPruned(&logdb.TransferFilter{CriteriaSet: []*logdb.TransferCriteria{{Sender: &senders[1]}}}))
+	assert.True(t, status.TransfersPruned(&logdb.TransferFilter{CriteriaSet: []*logdb.TransferCriteria{{Sender: &senders[0]}}}))
+	assert.Equal(t, &logdb.Range{From: 6, To: math.MaxUint32}, status.Clamp(nil))
+	assert.Equal(t, &logdb.Range{From: 6, To: 10}, status.Clamp(&logdb.Range{From: 1, To: 10}))
+	assert.Equal(t, &logdb.Range{From: 7, To: 10}, status.Clamp(&logdb.Range{From: 7, To: 10}))
+	assert.Nil(t, status.Clamp(&logdb.Range{From: 1, To: 5}))
+
+	// wait for the uncommitted writer
+	if sqlite, ok := db.(*logdb.LogDB); ok {
+		b = new(block.Builder).ParentID(b.Header().ID()).Transaction(newTx()).Build()
+		if err := w.Write(b, tx.Receipts{newReceipt()}); err != nil {
+			t.Fatal(err)
+		}
+		timeoutCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, sqlite.Prune(timeoutCtx, 8, nil))
+		if err := w.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// addresses not kept previously are no longer kept
+	if err := db.Prune(ctx, 8, []thor.Address{contracts[0], contracts[1]}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &logdb.PruneStatus{BlockNum: 8, Deleted: 8, Kept: []thor.Address{contracts[0]}}, db.PruneStatus())
+	events, err = db.FilterEvents(ctx, &logdb.EventFilter{Range: &logdb.Range{From: 6, To: 7}})
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+	transfers, err = db.FilterTransfers(ctx, &logdb.TransferFilter{Range: &logdb.Range{From: 6, To: 7}})
+	assert.Nil(t, err)
+	assert.Len(t, transfers, 0)
+
+	// indexes of pruned logs are removed as well
+	events, err = db.FilterEvents(ctx, &logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{Address: &contracts[1]}}})
+	assert.Nil(t, err)
+	assert.Len(t, events, 3)
+	for _, ev := range events {
+		assert.True(t, ev.BlockNumber >= 6)
+	}
+}
+
+func TestLogDB_PruneStatusPersisted(t *testing.T) {
+	for name, open := range map[string]func(path string) (logdb.LogStore, error){
+		"sqlite": func(path string) (logdb.LogStore, error) { return logdb.New(path) },
+		"kv":     logdb.NewKV,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "logs")
+			db, err := open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			keep := []thor.Address{randAddress()}
+			if err := db.Prune(context.Background(), 1000, keep); err != nil {
+				t.Fatal(err)
+			}
+			if err := db.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			db, err = open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			assert.Equal(t, &logdb.PruneStatus{BlockNum: 1000, Deleted: 1000, Kept: keep}, db.PruneStatus())
+		})
+	}
+}
+
+func TestLogDB_Rebuild(t *testing.T) {
+	for name, open := range map[string]func(path string) (logdb.LogStore, error){
+		"sqlite": func(path string) (logdb.LogStore, error) { return logdb.New(path) },
+		"kv":     logdb.NewKV,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var (
+				path = filepath.Join(t.TempDir(), "logs")
+				ctx  = context.Background()
+			)
+			db, err := open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// stale logs, to be deleted by the rebuild
+			b := new(block.Builder).Build()
+			w := db.NewWriter()
+			if err := w.Write(b, tx.Receipts{newReceipt()}); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Commit(); err != nil {
+				t.Fatal(err)
+			}
+			if err := db.Prune(ctx, 1, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			// logs of blocks #1..#10, #0 has none
+			var (
+				logs        = []*logdb.BlockLogs{{}}
+				events      eventLogs
+				transfers   transferLogs
+				deployments []*logdb.Deployment
+				deployer, _ = crypto.GenerateKey()
+			)
+			for i := 0; i < 10; i++ {
+				builder := new(block.Builder).Transaction(newTx()).Transaction(newDeployTx(deployer, uint64(i), randAddress()))
+				if i > 0 {
+					builder.ParentID(b.Header().ID())
+				}
+				b = builder.Build()
+				l := logdb.NewBlockLogs(b, tx.Receipts{newReceipt(), newEventOnlyReceipt()})
+				logs = append(logs, l)
+				events = append(events, l.Events...)
+				transfers = append(transfers, l.Transfers...)
+				deployments = append(deployments, l.Deployments...)
+			}
+
+			rb, err := db.Rebuild()
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, uint32(0), rb.Next())
+			assert.Equal(t, &logdb.PruneStatus{}, db.PruneStatus())
+			rebuilding, err := db.Rebuilding()
+			assert.Nil(t, err)
+			assert.True(t, rebuilding)
+			got, err := db.FilterEvents(ctx, nil)
+			assert.Nil(t, err)
+			assert.Empty(t, got)
+
+			if err := rb.Write(logs[:6]); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, uint32(6), rb.Next())
+			assert.Error(t, rb.Write(logs[7:]), "non-consecutive blocks")
+
+			// resume after reopened
+			if err := db.Close(); err != nil {
+				t.Fatal(err)
+			}
+			db, err = open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			rebuilding, err = db.Rebuilding()
+			assert.Nil(t, err)
+			assert.True(t, rebuilding)
+			rb, err = db.Rebuild()
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, uint32(6), rb.Next())
+			if err := rb.Write(logs[6:]); err != nil {
+				t.Fatal(err)
+			}
+			if err := rb.Finish(); err != nil {
+				t.Fatal(err)
+			}
+			rebuilding, err = db.Rebuilding()
+			assert.Nil(t, err)
+			assert.False(t, rebuilding)
+
+			got, err = db.FilterEvents(ctx, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, events, eventLogs(got))
+			gotTransfers, err := db.FilterTransfers(ctx, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, transfers, transferLogs(gotTransfers))
+
+			// indexed queries
+			got, err = db.FilterEvents(ctx, &logdb.EventFilter{CriteriaSet: []*logdb.EventCriteria{{Address: &events[3].Address}}})
+			assert.Nil(t, err)
+			assert.Equal(t, eventLogs{events[3]}, eventLogs(got))
+			gotTransfers, err = db.FilterTransfers(ctx, &logdb.TransferFilter{CriteriaSet: []*logdb.TransferCriteria{{Recipient: &transfers[5].Recipient}}})
+			assert.Nil(t, err)
+			assert.Equal(t, transferLogs{transfers[5]}, transferLogs(gotTransfers))
+			gotDeployments, err := db.FilterDeployments(ctx, &logdb.DeploymentFilter{CriteriaSet: []*logdb.DeploymentCriteria{{Address: &deployments[7].Address}}})
+			assert.Nil(t, err)
+			assert.Equal(t, deployments[7:8], gotDeployments)
+
+			newest, err := db.NewestBlockID()
+			assert.Nil(t, err)
+			assert.Equal(t, logs[10].BlockID, newest)
+		})
+	}
+}
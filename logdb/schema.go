@@ -51,3 +51,62 @@ CREATE INDEX IF NOT EXISTS transfer_i0 ON transfer(txOrigin);
 CREATE INDEX IF NOT EXISTS transfer_i1 ON transfer(sender);
 CREATE INDEX IF NOT EXISTS transfer_i2 ON transfer(recipient);`
 )
+
+// migration is a step to bring existing databases up to date.
+type migration struct {
+	desc  string // numbered, and logged before the step is applied
+	query string
+}
+
+// migrations bring existing databases up to date, the number of applied ones is tracked by 'pragma user_version'.
+// Released migrations must never be changed, append new ones instead.
+var migrations = []migration{
+	{"1: index events by tx ID and tx origin", `CREATE INDEX IF NOT EXISTS event_i5 ON event(txID);
+CREATE INDEX IF NOT EXISTS event_i6 ON event(txOrigin);`},
+	{"2: create the table of properties, e.g. the prune status", `CREATE TABLE IF NOT EXISTS prop (
+	name TEXT PRIMARY KEY NOT NULL,
+	value BLOB NOT NULL
+);`},
+	{"3: create the table of contracts created by clauses", `CREATE TABLE IF NOT EXISTS deployment (
+	seq INTEGER PRIMARY KEY NOT NULL,
+	blockID INTEGER NOT NULL,
+	blockTime INTEGER NOT NULL,
+	txID INTEGER NOT NULL,
+	txOrigin INTEGER NOT NULL,
+	clauseIndex INTEGER NOT NULL,
+	address INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS deployment_i0 ON deployment(address);
+CREATE INDEX IF NOT EXISTS deployment_i1 ON deployment(txOrigin);`},
+	{"4: create the table of clauses of reverted txs", `CREATE TABLE IF NOT EXISTS failure (
+	seq INTEGER PRIMARY KEY NOT NULL,
+	blockID INTEGER NOT NULL,
+	blockTime INTEGER NOT NULL,
+	txID INTEGER NOT NULL,
+	txOrigin INTEGER NOT NULL,
+	clauseIndex INTEGER NOT NULL,
+	recipient INTEGER,
+	selector BLOB(4)
+);
+
+CREATE INDEX IF NOT EXISTS failure_i0 ON failure(recipient, selector);
+CREATE INDEX IF NOT EXISTS failure_i1 ON failure(txOrigin);`},
+	{"5: create the table of fungible token transfers", `CREATE TABLE IF NOT EXISTS token_transfer (
+	seq INTEGER PRIMARY KEY NOT NULL,
+	blockID INTEGER NOT NULL,
+	blockTime INTEGER NOT NULL,
+	txID INTEGER NOT NULL,
+	txOrigin INTEGER NOT NULL,
+	clauseIndex INTEGER NOT NULL,
+	token INTEGER NOT NULL,
+	sender INTEGER NOT NULL,
+	recipient INTEGER NOT NULL,
+	amount BLOB(32)
+);
+
+CREATE INDEX IF NOT EXISTS token_transfer_i0 ON token_transfer(txOrigin);
+CREATE INDEX IF NOT EXISTS token_transfer_i1 ON token_transfer(sender, token);
+CREATE INDEX IF NOT EXISTS token_transfer_i2 ON token_transfer(recipient, token);
+CREATE INDEX IF NOT EXISTS token_transfer_i3 ON token_transfer(token);`},
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.db")
+
+	// create a db of the original schema
+	raw, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = raw.Exec(refTableScheme + eventTableSchema + transferTableSchema)
+	require.NoError(t, err)
+	require.NoError(t, raw.Close())
+
+	db, err := New(path)
+	require.NoError(t, err)
+
+	var version int
+	require.NoError(t, db.db.QueryRow("pragma user_version").Scan(&version))
+	assert.Equal(t, len(migrations), version)
+
+	var count int
+	require.NoError(t, db.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='index' AND name IN ('event_i5', 'event_i6')").Scan(&count))
+	assert.Equal(t, 2, count)
+	require.NoError(t, db.Close())
+
+	// reopen without migrations to apply
+	db, err = New(path)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+}
+
+func TestKVVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.kvdb")
+
+	store, err := NewKV(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	// reopen a store of the current version
+	store, err = NewKV(path)
+	require.NoError(t, err)
+	require.NoError(t, store.(*kvLogDB).store.Put(kvVersionKey, kvUint64(kvVersion+1)))
+	require.NoError(t, store.Close())
+
+	_, err = NewKV(path)
+	assert.EqualError(t, err, fmt.Sprintf("unsupported kv log db version %d", kvVersion+1))
+}
@@ -5,7 +5,12 @@
 
 package logdb
 
-import "math"
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math"
+)
 
 type sequence int64
 
@@ -23,3 +28,40 @@ func (s sequence) BlockNumber() uint32 {
 func (s sequence) Index() uint32 {
 	return uint32(s & math.MaxInt32)
 }
+
+// Cursor is an opaque position of a log, derived from its sequence.
+// Filtering with a cursor continues right after the log it points to.
+type Cursor struct {
+	seq sequence
+}
+
+// NewCursor creates a cursor pointing to the log at the given position.
+func NewCursor(blockNum uint32, index uint32) *Cursor {
+	return &Cursor{newSequence(blockNum, index)}
+}
+
+// String returns the encoded cursor.
+func (c Cursor) String() string {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(c.seq))
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c Cursor) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Cursor) UnmarshalText(text []byte) error {
+	b, err := base64.RawURLEncoding.DecodeString(string(text))
+	if err != nil || len(b) != 8 {
+		return errors.New("invalid cursor")
+	}
+	seq := sequence(binary.BigEndian.Uint64(b))
+	if seq < 0 {
+		return errors.New("invalid cursor")
+	}
+	c.seq = seq
+	return nil
+}
@@ -8,6 +8,8 @@ package logdb
 import (
 	"math"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSequence(t *testing.T) {
@@ -44,3 +46,19 @@ func TestSequence(t *testing.T) {
 	}()
 	newSequence(1, math.MaxInt32+1)
 }
+
+func TestCursor(t *testing.T) {
+	c := NewCursor(100, 3)
+	text, err := c.MarshalText()
+	assert.Nil(t, err)
+
+	var decoded Cursor
+	assert.Nil(t, decoded.UnmarshalText(text))
+	assert.Equal(t, *c, decoded)
+	assert.Equal(t, uint32(100), decoded.seq.BlockNumber())
+	assert.Equal(t, uint32(3), decoded.seq.Index())
+
+	for _, bad := range []string{"", "invalid!", "AAAAAAAAAA", "__________8"} {
+		assert.EqualError(t, decoded.UnmarshalText([]byte(bad)), "invalid cursor", bad)
+	}
+}
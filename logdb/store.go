@@ -0,0 +1,96 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"context"
+
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+// LogStore is the storage of event and transfer logs, along with contract deployments.
+type LogStore interface {
+	FilterEvents(ctx context.Context, filter *EventFilter) ([]*Event, error)
+	FilterTransfers(ctx context.Context, filter *TransferFilter) ([]*Transfer, error)
+	// FilterDeployments queries contracts created by clauses. Deployments are never pruned.
+	FilterDeployments(ctx context.Context, filter *DeploymentFilter) ([]*Deployment, error)
+	// FilterFailures queries clauses of reverted txs, which are empty unless failures are recorded.
+	FilterFailures(ctx context.Context, filter *FailureFilter) ([]*Failure, error)
+	// FilterTokenTransfers queries fungible token transfers, which are empty unless token transfers are recorded.
+	FilterTokenTransfers(ctx context.Context, filter *TokenTransferFilter) ([]*TokenTransfer, error)
+
+	// CountEvents counts events matching the filter, grouped by buckets of block time if bucket (in seconds) is not zero,
+	// otherwise a single count is returned. The range and criteria of the filter select events, while the order and
+	// options page the buckets, where the cursor is ignored.
+	CountEvents(ctx context.Context, filter *EventFilter, bucket uint64) ([]*EventCount, error)
+	// SummarizeTransfers summarizes transfers matching the filter, grouped like CountEvents.
+	SummarizeTransfers(ctx context.Context, filter *TransferFilter, bucket uint64) ([]*TransferSummary, error)
+
+	// NewestBlockID returns the ID of the newest block with logs written.
+	NewestBlockID() (thor.Bytes32, error)
+	// HasBlockID returns whether logs of the given block were written.
+	HasBlockID(id thor.Bytes32) (bool, error)
+	// WrittenBlockID returns the ID of the last block written by committed writers since the store is opened.
+	// Unlike NewestBlockID, blocks without logs are counted. The zero ID is returned if nothing is written yet.
+	WrittenBlockID() thor.Bytes32
+
+	// PruneStatus returns the status of pruned logs.
+	PruneStatus() *PruneStatus
+	// Prune deletes logs of blocks before blockNum in batches, except events emitted by and transfers from or to
+	// the kept addresses. Interrupted pruning is resumed by the next call. It must not be called concurrently.
+	Prune(ctx context.Context, blockNum uint32, keep []thor.Address) error
+
+	// Rebuild starts rebuilding the store from scratch by deleting all logs, or resumes the unfinished rebuild.
+	// It must not be used along with writers or pruning.
+	Rebuild() (Rebuilder, error)
+	// Rebuilding returns whether a rebuild is unfinished, when logs are incomplete.
+	Rebuilding() (bool, error)
+
+	// RecordFailures sets whether clauses of reverted txs are written by writers and rebuilders created afterwards,
+	// which are skipped by default.
+	RecordFailures(enabled bool)
+	// RecordTokenTransfers sets whether token transfers are written like RecordFailures.
+	RecordTokenTransfers(enabled bool)
+
+	// NewWriter creates a log writer.
+	NewWriter() Writer
+	// NewWriterSyncOff creates a log writer trading durability for speed, to quickly catch up.
+	NewWriterSyncOff() Writer
+
+	Close() error
+}
+
+// Writer is the transactional log writer.
+type Writer interface {
+	// Truncate truncates the store by deleting logs after blockNum (included).
+	Truncate(blockNum uint32) error
+	// Write writes all logs of the given block.
+	Write(b *block.Block, receipts tx.Receipts) error
+	// Commit commits accumulated logs.
+	Commit() error
+	// Rollback rollback all uncommitted logs.
+	Rollback() error
+	// UncommittedCount returns the count of uncommitted operations.
+	UncommittedCount() int
+}
+
+// Rebuilder writes logs of blocks in bulk, with secondary indexes disabled until finished.
+// The progress is saved along with each write, so an interrupted rebuild can be resumed.
+type Rebuilder interface {
+	// Next returns the number of the next block to write.
+	Next() uint32
+	// Write writes logs of consecutive blocks, starting from the next block.
+	Write(logs []*BlockLogs) error
+	// Finish creates the indexes and ends the rebuild.
+	Finish() error
+}
+
+// pruneWindow is the number of blocks of which logs are deleted in a batch.
+const pruneWindow = 100
+
+var _ LogStore = (*LogDB)(nil)
@@ -7,9 +7,14 @@ package logdb
 
 import (
 	"fmt"
+	"math"
 	"math/big"
+	"slices"
+	"strings"
 
+	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
 )
 
 // Event represents tx.Event that can be stored in db.
@@ -40,6 +45,296 @@ type Transfer struct {
 	Amount      *big.Int
 }
 
+// Deployment represents a contract created by a clause, which is indexed along with logs.
+type Deployment struct {
+	BlockNumber uint32
+	Index       uint32
+	BlockID     thor.Bytes32
+	BlockTime   uint64
+	TxID        thor.Bytes32
+	TxOrigin    thor.Address // the deployer
+	ClauseIndex uint32
+	Address     thor.Address // the created contract
+}
+
+// Failure represents a clause of a reverted tx. Receipts don't tell which clause reverted, or the VM error,
+// so all clauses of the tx are recorded.
+type Failure struct {
+	BlockNumber uint32
+	Index       uint32
+	BlockID     thor.Bytes32
+	BlockTime   uint64
+	TxID        thor.Bytes32
+	TxOrigin    thor.Address
+	ClauseIndex uint32
+	To          *thor.Address // nil for contract creation
+	Selector    *[4]byte      // the function selector, nil if the clause data is shorter
+}
+
+// TokenTransfer represents a fungible token transfer, normalized from a Transfer(address,address,uint256) event,
+// e.g. of VTHO or VIP-180 tokens.
+type TokenTransfer struct {
+	BlockNumber uint32
+	Index       uint32
+	BlockID     thor.Bytes32
+	BlockTime   uint64
+	TxID        thor.Bytes32
+	TxOrigin    thor.Address
+	ClauseIndex uint32
+	Token       thor.Address // the token contract emitting the event
+	Sender      thor.Address
+	Recipient   thor.Address
+	Amount      *big.Int
+}
+
+// tokenTransferTopic is the topic of Transfer(address,address,uint256) events.
+var tokenTransferTopic = thor.MustParseBytes32("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+// BlockLogs are logs of a block, in the form written to the store.
+type BlockLogs struct {
+	BlockID     thor.Bytes32
+	Events      []*Event
+	Transfers   []*Transfer
+	Deployments []*Deployment
+	Failures    []*Failure // written only if the store records failures
+
+	TokenTransfers []*TokenTransfer // written only if the store records token transfers
+}
+
+// recording tells which optional logs are written.
+type recording struct {
+	failures       bool
+	tokenTransfers bool
+}
+
+// NewBlockLogs extracts logs of the block from its receipts.
+// It recovers tx origins, so it's worth being called in parallel for bulk blocks.
+func NewBlockLogs(b *block.Block, receipts tx.Receipts) *BlockLogs {
+	var (
+		header = b.Header()
+		txs    = b.Transactions()
+		logs   = &BlockLogs{BlockID: header.ID()}
+	)
+	for i, r := range receipts {
+		var (
+			txID     thor.Bytes32
+			txOrigin thor.Address
+			clauses  []*tx.Clause
+		)
+		if i < len(txs) { // block 0 has no tx, but has receipts
+			txID = txs[i].ID()
+			txOrigin, _ = txs[i].Origin()
+			clauses = txs[i].Clauses()
+		}
+
+		if r.Reverted {
+			for clauseIndex, clause := range clauses {
+				failure := &Failure{
+					BlockNumber: header.Number(),
+					Index:       uint32(len(logs.Failures)),
+					BlockID:     logs.BlockID,
+					BlockTime:   header.Timestamp(),
+					TxID:        txID,
+					TxOrigin:    txOrigin,
+					ClauseIndex: uint32(clauseIndex),
+					To:          clause.To(),
+				}
+				if data := clause.Data(); len(data) >= 4 {
+					failure.Selector = (*[4]byte)(data[:4])
+				}
+				logs.Failures = append(logs.Failures, failure)
+			}
+		}
+
+		for clauseIndex, output := range r.Outputs {
+			// outputs of reverted txs are absent, so are the contracts they failed to create
+			if clauseIndex < len(clauses) && clauses[clauseIndex].To() == nil {
+				logs.Deployments = append(logs.Deployments, &Deployment{
+					BlockNumber: header.Number(),
+					Index:       uint32(len(logs.Deployments)),
+					BlockID:     logs.BlockID,
+					BlockTime:   header.Timestamp(),
+					TxID:        txID,
+					TxOrigin:    txOrigin,
+					ClauseIndex: uint32(clauseIndex),
+					Address:     thor.CreateContractAddress(txID, uint32(clauseIndex), 0),
+				})
+			}
+			for _, ev := range output.Events {
+				event := &Event{
+					BlockNumber: header.Number(),
+					Index:       uint32(len(logs.Events)),
+					BlockID:     logs.BlockID,
+					BlockTime:   header.Timestamp(),
+					TxID:        txID,
+					TxOrigin:    txOrigin,
+					ClauseIndex: uint32(clauseIndex),
+					Address:     ev.Address,
+				}
+				for j := 0; j < len(ev.Topics) && j < len(event.Topics); j++ {
+					event.Topics[j] = &ev.Topics[j]
+				}
+				if len(ev.Data) > 0 {
+					event.Data = ev.Data
+				}
+				logs.Events = append(logs.Events, event)
+
+				// events of non-fungible tokens share the topic, but have the token ID indexed instead of the amount
+				if len(ev.Topics) == 3 && ev.Topics[0] == tokenTransferTopic && len(ev.Data) == 32 {
+					logs.TokenTransfers = append(logs.TokenTransfers, &TokenTransfer{
+						BlockNumber: header.Number(),
+						Index:       uint32(len(logs.TokenTransfers)),
+						BlockID:     logs.BlockID,
+						BlockTime:   header.Timestamp(),
+						TxID:        txID,
+						TxOrigin:    txOrigin,
+						ClauseIndex: uint32(clauseIndex),
+						Token:       ev.Address,
+						Sender:      thor.BytesToAddress(ev.Topics[1][:]),
+						Recipient:   thor.BytesToAddress(ev.Topics[2][:]),
+						Amount:      new(big.Int).SetBytes(ev.Data),
+					})
+				}
+			}
+			for _, tr := range output.Transfers {
+				logs.Transfers = append(logs.Transfers, &Transfer{
+					BlockNumber: header.Number(),
+					Index:       uint32(len(logs.Transfers)),
+					BlockID:     logs.BlockID,
+					BlockTime:   header.Timestamp(),
+					TxID:        txID,
+					TxOrigin:    txOrigin,
+					ClauseIndex: uint32(clauseIndex),
+					Sender:      tr.Sender,
+					Recipient:   tr.Recipient,
+					Amount:      tr.Amount,
+				})
+			}
+		}
+	}
+	return logs
+}
+
+// EventCount is the number of events within a bucket of block time.
+type EventCount struct {
+	BucketTime uint64 // start of the bucket, always 0 if not grouped
+	Count      uint64
+}
+
+// TransferSummary summarizes transfers within a bucket of block time.
+type TransferSummary struct {
+	BucketTime uint64 // start of the bucket, always 0 if not grouped
+	Count      uint64
+	Amount     *big.Int // the total amount
+	Senders    uint64   // the number of distinct senders
+}
+
+// PruneStatus is the status of logs pruned by the retention policy.
+type PruneStatus struct {
+	BlockNum uint32         // logs of blocks before it are pruned, except the kept ones
+	Deleted  uint32         // logs of blocks before it are deleted, lower than BlockNum if pruning is interrupted
+	Kept     []thor.Address // events emitted by and transfers from or to the addresses are kept by all pruning
+}
+
+func (s *PruneStatus) kept(addr *thor.Address) bool {
+	return addr != nil && slices.Contains(s.Kept, *addr)
+}
+
+// next returns the status of pruning logs before blockNum with the kept addresses.
+func (s *PruneStatus) next(blockNum uint32, keep []thor.Address) *PruneStatus {
+	next := &PruneStatus{BlockNum: max(s.BlockNum, blockNum), Deleted: s.Deleted}
+	for _, addr := range keep {
+		// logs of addresses not kept by previous pruning are incomplete
+		if (s.BlockNum == 0 || s.kept(&addr)) && !next.kept(&addr) {
+			next.Kept = append(next.Kept, addr)
+		}
+	}
+	return next
+}
+
+// Clamp narrows the range to the blocks not pruned, nil range means all blocks.
+// It returns nil if all blocks of the range are pruned.
+func (s *PruneStatus) Clamp(rng *Range) *Range {
+	if rng == nil {
+		return &Range{From: s.BlockNum, To: math.MaxUint32}
+	}
+	if rng.To < s.BlockNum {
+		return nil
+	}
+	return &Range{From: max(rng.From, s.BlockNum), To: rng.To}
+}
+
+// EventsPruned returns whether events queried by the filter may be pruned.
+func (s *PruneStatus) EventsPruned(filter *EventFilter) bool {
+	if s.BlockNum == 0 || (filter != nil && filter.Range != nil && filter.Range.From >= s.BlockNum) {
+		return false
+	}
+	if filter == nil || len(filter.CriteriaSet) == 0 {
+		return true
+	}
+	for _, c := range filter.CriteriaSet {
+		kept := s.kept(c.Address)
+		if !kept && len(c.AddressSet) > 0 {
+			kept = true
+			for _, addr := range c.AddressSet {
+				kept = kept && s.kept(&addr)
+			}
+		}
+		if !kept {
+			return true
+		}
+	}
+	return false
+}
+
+// TransfersPruned returns whether transfers queried by the filter may be pruned.
+func (s *PruneStatus) TransfersPruned(filter *TransferFilter) bool {
+	if s.BlockNum == 0 || (filter != nil && filter.Range != nil && filter.Range.From >= s.BlockNum) {
+		return false
+	}
+	if filter == nil || len(filter.CriteriaSet) == 0 {
+		return true
+	}
+	for _, c := range filter.CriteriaSet {
+		if !s.kept(c.Sender) && !s.kept(c.Recipient) {
+			return true
+		}
+	}
+	return false
+}
+
+// FailuresPruned returns whether failures queried by the filter may be pruned.
+func (s *PruneStatus) FailuresPruned(filter *FailureFilter) bool {
+	if s.BlockNum == 0 || (filter != nil && filter.Range != nil && filter.Range.From >= s.BlockNum) {
+		return false
+	}
+	if filter == nil || len(filter.CriteriaSet) == 0 {
+		return true
+	}
+	for _, c := range filter.CriteriaSet {
+		if !s.kept(c.To) {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenTransfersPruned returns whether token transfers queried by the filter may be pruned.
+func (s *PruneStatus) TokenTransfersPruned(filter *TokenTransferFilter) bool {
+	if s.BlockNum == 0 || (filter != nil && filter.Range != nil && filter.Range.From >= s.BlockNum) {
+		return false
+	}
+	if filter == nil || len(filter.CriteriaSet) == 0 {
+		return true
+	}
+	for _, c := range filter.CriteriaSet {
+		if !s.kept(c.Token) && !s.kept(c.Sender) && !s.kept(c.Recipient) {
+			return true
+		}
+	}
+	return false
+}
+
 type Order string
 
 const (
@@ -55,15 +350,34 @@ type Range struct {
 type Options struct {
 	Offset uint64
 	Limit  uint64
+	Cursor *Cursor // if set, only logs after the cursor are queried, in the given order
 }
 
 type EventCriteria struct {
-	Address *thor.Address // always a contract address
-	Topics  [5]*thor.Bytes32
+	Address  *thor.Address // always a contract address
+	Topics   [5]*thor.Bytes32
+	TxID     *thor.Bytes32 // the tx emitting the event
+	TxOrigin *thor.Address // who send transaction
+
+	AddressSet []thor.Address    // matches any of the addresses, if not empty
+	TopicSets  [5][]thor.Bytes32 // the topic at each position matches any of the set, if not empty
+}
+
+// refIDSetQuery returns the query selecting ref IDs of the n values.
+func refIDSetQuery(n int) string {
+	return "(SELECT id FROM ref WHERE data IN (?" + strings.Repeat(",?", n-1) + "))"
 }
 
 func (c *EventCriteria) toWhereCondition() (cond string, args []interface{}) {
 	cond = "1"
+	if c.TxID != nil {
+		cond += " AND txID = " + refIDQuery
+		args = append(args, c.TxID.Bytes())
+	}
+	if c.TxOrigin != nil {
+		cond += " AND txOrigin = " + refIDQuery
+		args = append(args, c.TxOrigin.Bytes())
+	}
 	if c.Address != nil {
 		cond += " AND address = " + refIDQuery
 		args = append(args, c.Address.Bytes())
@@ -74,9 +388,50 @@ func (c *EventCriteria) toWhereCondition() (cond string, args []interface{}) {
 			args = append(args, topic.Bytes())
 		}
 	}
+	if len(c.AddressSet) > 0 {
+		cond += " AND address IN " + refIDSetQuery(len(c.AddressSet))
+		for _, addr := range c.AddressSet {
+			args = append(args, addr.Bytes())
+		}
+	}
+	for i, set := range c.TopicSets {
+		if len(set) > 0 {
+			cond += fmt.Sprintf(" AND topic%v IN ", i) + refIDSetQuery(len(set))
+			for _, topic := range set {
+				args = append(args, topic.Bytes())
+			}
+		}
+	}
 	return
 }
 
+// match returns whether the event matches the criteria.
+func (c *EventCriteria) match(ev *Event) bool {
+	if c.TxID != nil && *c.TxID != ev.TxID {
+		return false
+	}
+	if c.TxOrigin != nil && *c.TxOrigin != ev.TxOrigin {
+		return false
+	}
+	if c.Address != nil && *c.Address != ev.Address {
+		return false
+	}
+	for i, topic := range c.Topics {
+		if topic != nil && (ev.Topics[i] == nil || *topic != *ev.Topics[i]) {
+			return false
+		}
+	}
+	if len(c.AddressSet) > 0 && !slices.Contains(c.AddressSet, ev.Address) {
+		return false
+	}
+	for i, set := range c.TopicSets {
+		if len(set) > 0 && (ev.Topics[i] == nil || !slices.Contains(set, *ev.Topics[i])) {
+			return false
+		}
+	}
+	return true
+}
+
 // EventFilter filter
 type EventFilter struct {
 	CriteriaSet []*EventCriteria
@@ -108,9 +463,155 @@ func (c *TransferCriteria) toWhereCondition() (cond string, args []interface{})
 	return
 }
 
+// match returns whether the transfer matches the criteria.
+func (c *TransferCriteria) match(tr *Transfer) bool {
+	if c.TxOrigin != nil && *c.TxOrigin != tr.TxOrigin {
+		return false
+	}
+	if c.Sender != nil && *c.Sender != tr.Sender {
+		return false
+	}
+	if c.Recipient != nil && *c.Recipient != tr.Recipient {
+		return false
+	}
+	return true
+}
+
 type TransferFilter struct {
 	CriteriaSet []*TransferCriteria
 	Range       *Range
 	Options     *Options
 	Order       Order //default asc
 }
+
+type DeploymentCriteria struct {
+	Address  *thor.Address // the created contract
+	Deployer *thor.Address // the tx origin creating the contract
+}
+
+func (c *DeploymentCriteria) toWhereCondition() (cond string, args []interface{}) {
+	cond = "1"
+	if c.Address != nil {
+		cond += " AND address = " + refIDQuery
+		args = append(args, c.Address.Bytes())
+	}
+	if c.Deployer != nil {
+		cond += " AND txOrigin = " + refIDQuery
+		args = append(args, c.Deployer.Bytes())
+	}
+	return
+}
+
+// match returns whether the deployment matches the criteria.
+func (c *DeploymentCriteria) match(d *Deployment) bool {
+	if c.Address != nil && *c.Address != d.Address {
+		return false
+	}
+	if c.Deployer != nil && *c.Deployer != d.TxOrigin {
+		return false
+	}
+	return true
+}
+
+type DeploymentFilter struct {
+	CriteriaSet []*DeploymentCriteria
+	Range       *Range
+	Options     *Options
+	Order       Order //default asc
+}
+
+type FailureCriteria struct {
+	TxOrigin *thor.Address // who send transaction
+	To       *thor.Address // the clause recipient
+	Selector *[4]byte      // the function selector
+}
+
+func (c *FailureCriteria) toWhereCondition() (cond string, args []interface{}) {
+	cond = "1"
+	if c.TxOrigin != nil {
+		cond += " AND txOrigin = " + refIDQuery
+		args = append(args, c.TxOrigin.Bytes())
+	}
+	if c.To != nil {
+		cond += " AND recipient = " + refIDQuery
+		args = append(args, c.To.Bytes())
+	}
+	if c.Selector != nil {
+		cond += " AND selector = ?"
+		args = append(args, c.Selector[:])
+	}
+	return
+}
+
+// match returns whether the failure matches the criteria.
+func (c *FailureCriteria) match(f *Failure) bool {
+	if c.TxOrigin != nil && *c.TxOrigin != f.TxOrigin {
+		return false
+	}
+	if c.To != nil && (f.To == nil || *c.To != *f.To) {
+		return false
+	}
+	if c.Selector != nil && (f.Selector == nil || *c.Selector != *f.Selector) {
+		return false
+	}
+	return true
+}
+
+type FailureFilter struct {
+	CriteriaSet []*FailureCriteria
+	Range       *Range
+	Options     *Options
+	Order       Order //default asc
+}
+
+type TokenTransferCriteria struct {
+	TxOrigin  *thor.Address //who send transaction
+	Sender    *thor.Address //who transferred tokens
+	Recipient *thor.Address //who received tokens
+	Token     *thor.Address //the token contract
+}
+
+func (c *TokenTransferCriteria) toWhereCondition() (cond string, args []interface{}) {
+	cond = "1"
+	if c.TxOrigin != nil {
+		cond += " AND txOrigin = " + refIDQuery
+		args = append(args, c.TxOrigin.Bytes())
+	}
+	if c.Sender != nil {
+		cond += " AND sender = " + refIDQuery
+		args = append(args, c.Sender.Bytes())
+	}
+	if c.Recipient != nil {
+		cond += " AND recipient = " + refIDQuery
+		args = append(args, c.Recipient.Bytes())
+	}
+	if c.Token != nil {
+		cond += " AND token = " + refIDQuery
+		args = append(args, c.Token.Bytes())
+	}
+	return
+}
+
+// match returns whether the token transfer matches the criteria.
+func (c *TokenTransferCriteria) match(tr *TokenTransfer) bool {
+	if c.TxOrigin != nil && *c.TxOrigin != tr.TxOrigin {
+		return false
+	}
+	if c.Sender != nil && *c.Sender != tr.Sender {
+		return false
+	}
+	if c.Recipient != nil && *c.Recipient != tr.Recipient {
+		return false
+	}
+	if c.Token != nil && *c.Token != tr.Token {
+		return false
+	}
+	return true
+}
+
+type TokenTransferFilter struct {
+	CriteriaSet []*TokenTransferCriteria
+	Range       *Range
+	Options     *Options
+	Order       Order //default asc
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package thorclient
+
+import (
+	"github.com/vechain/thor/v2/api/accounts"
+	"github.com/vechain/thor/v2/api/deployments"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// Account returns the account at the revision, an empty revision refers to the best block.
+func (c *Client) Account(addr thor.Address, revision string) (*accounts.Account, error) {
+	var acc *accounts.Account
+	if _, err := c.get("/accounts/"+addr.String(), revisionQuery(revision), &acc); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// AccountCode returns the code of the account at the revision.
+func (c *Client) AccountCode(addr thor.Address, revision string) (*accounts.GetCodeResult, error) {
+	var res *accounts.GetCodeResult
+	if _, err := c.get("/accounts/"+addr.String()+"/code", revisionQuery(revision), &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// AccountStorage returns the storage value of the account at the revision.
+func (c *Client) AccountStorage(addr thor.Address, key thor.Bytes32, revision string) (*accounts.GetStorageResult, error) {
+	var res *accounts.GetStorageResult
+	if _, err := c.get("/accounts/"+addr.String()+"/storage/"+key.String(), revisionQuery(revision), &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// AccountCreation returns the clause creating the contract, which responds 404 if not created by a clause.
+func (c *Client) AccountCreation(addr thor.Address) (*deployments.FilteredDeployment, error) {
+	var res *deployments.FilteredDeployment
+	if _, err := c.get("/accounts/"+addr.String()+"/creation", nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// InspectClauses simulates the execution of the clauses at the revision, which may also be "next".
+func (c *Client) InspectClauses(data *accounts.BatchCallData, revision string) (accounts.BatchCallResults, error) {
+	var res accounts.BatchCallResults
+	if _, err := c.post("/accounts/*", revisionQuery(revision), data, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CallContract simulates a single clause sent to the address, or deploying a contract if to is nil.
+//
+// Deprecated: use InspectClauses.
+func (c *Client) CallContract(to *thor.Address, data *accounts.CallData, revision string) (*accounts.CallResult, error) {
+	path := "/accounts"
+	if to != nil {
+		path += "/" + to.String()
+	}
+	var res *accounts.CallResult
+	if _, err := c.post(path, revisionQuery(revision), data, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package thorclient
+
+import (
+	"net/url"
+
+	"github.com/vechain/thor/v2/api/blocks"
+)
+
+// Block returns the block with tx IDs, nil if not found.
+// The revision is a block ID or number, or one of "best", "justified" and "finalized".
+func (c *Client) Block(revision string) (*blocks.JSONCollapsedBlock, error) {
+	var blk *blocks.JSONCollapsedBlock
+	if _, err := c.get("/blocks/"+url.PathEscape(revision), nil, &blk); err != nil {
+		return nil, err
+	}
+	return blk, nil
+}
+
+// ExpandedBlock returns the block with txs and their receipts, nil if not found.
+func (c *Client) ExpandedBlock(revision string) (*blocks.JSONExpandedBlock, error) {
+	var blk *blocks.JSONExpandedBlock
+	if _, err := c.get("/blocks/"+url.PathEscape(revision), url.Values{"expanded": {"true"}}, &blk); err != nil {
+		return nil, err
+	}
+	return blk, nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package thorclient is a typed client of the Thor REST API, reusing the request and response types of the API packages.
+package thorclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// apiKeyHeader is the request header carrying the API key, see api.APIKeyHeader.
+const apiKeyHeader = "X-API-Key"
+
+// Options options of the client.
+type Options struct {
+	HTTPClient *http.Client      // defaults to http.DefaultClient
+	WSDialer   *websocket.Dialer // defaults to websocket.DefaultDialer
+	APIKey     string            // sent with each request if not empty
+	Header     http.Header       // extra headers sent with each request
+}
+
+// Client the client of the Thor REST API.
+type Client struct {
+	url    string
+	opts   Options
+	header http.Header
+}
+
+// New creates a client of the API served at the given URL, e.g. http://localhost:8669.
+func New(baseURL string, opts Options) *Client {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	if opts.WSDialer == nil {
+		opts.WSDialer = websocket.DefaultDialer
+	}
+
+	header := opts.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	if opts.APIKey != "" {
+		header.Set(apiKeyHeader, opts.APIKey)
+	}
+	return &Client{
+		url:    strings.TrimRight(baseURL, "/"),
+		opts:   opts,
+		header: header,
+	}
+}
+
+// URL returns the base URL of the API.
+func (c *Client) URL() string {
+	return c.url
+}
+
+// Error the error responded by the API.
+type Error struct {
+	StatusCode int
+	Message    string        // the trimmed response body
+	RetryAfter time.Duration // the Retry-After header of rate limited responses
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// newError reads the error from the response.
+func newError(res *http.Response) *Error {
+	body, _ := io.ReadAll(res.Body)
+	e := &Error{
+		StatusCode: res.StatusCode,
+		Message:    strings.TrimSpace(string(body)),
+	}
+	if secs, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil {
+		e.RetryAfter = time.Duration(secs) * time.Second
+	}
+	return e
+}
+
+// get sends a GET request and decodes the JSON response into out.
+func (c *Client) get(path string, query url.Values, out interface{}) (http.Header, error) {
+	return c.do(http.MethodGet, path, query, nil, out)
+}
+
+// post sends a POST request with the JSON encoded body and decodes the JSON response into out.
+func (c *Client) post(path string, query url.Values, body, out interface{}) (http.Header, error) {
+	return c.do(http.MethodPost, path, query, body, out)
+}
+
+func (c *Client) do(method, path string, query url.Values, body, out interface{}) (http.Header, error) {
+	res, err := c.send(method, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, newError(res)
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return res.Header, nil
+}
+
+func (c *Client) send(method, path string, query url.Values, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	u := c.url + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.opts.HTTPClient.Do(req)
+}
+
+// revisionQuery returns the query of the revision, an empty revision refers to the best block.
+func revisionQuery(revision string) url.Values {
+	if revision == "" {
+		return nil
+	}
+	return url.Values{"revision": {revision}}
+}
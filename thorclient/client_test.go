@@ -0,0 +1,461 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package thorclient_test
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/api/accounts"
+	"github.com/vechain/thor/v2/api/debug"
+	"github.com/vechain/thor/v2/api/deployments"
+	"github.com/vechain/thor/v2/api/events"
+	"github.com/vechain/thor/v2/api/failures"
+	"github.com/vechain/thor/v2/api/health"
+	"github.com/vechain/thor/v2/api/subscriptions"
+	"github.com/vechain/thor/v2/api/tokentransfers"
+	"github.com/vechain/thor/v2/api/transfers"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/cmd/thor/solo"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/packer"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	_ "github.com/vechain/thor/v2/tracers/native"
+	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+var recipient = thor.BytesToAddress([]byte("recipient"))
+
+type mockOptimizer struct{}
+
+func (o *mockOptimizer) Progress() (uint32, uint32) {
+	return 0, 0
+}
+
+type testEnv struct {
+	repo   *chain.Repository
+	block  *block.Block
+	tx     *tx.Transaction
+	client *thorclient.Client
+}
+
+func newTx(t *testing.T, repo *chain.Repository, nonce uint64, clauses ...*tx.Clause) *tx.Transaction {
+	builder := new(tx.Builder).
+		ChainTag(repo.ChainTag()).
+		GasPriceCoef(1).
+		Expiration(100).
+		Gas(100000).
+		Nonce(nonce).
+		BlockRef(tx.NewBlockRef(0))
+	for _, c := range clauses {
+		builder.Clause(c)
+	}
+	trx := builder.Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	require.NoError(t, err)
+	return trx.WithSignature(sig)
+}
+
+func initEnv(t *testing.T) *testEnv {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	gene := genesis.NewDevnet()
+
+	b, _, _, err := gene.Build(stater)
+	require.NoError(t, err)
+	repo, err := chain.NewRepository(db, b)
+	require.NoError(t, err)
+
+	trx := newTx(t, repo, 1, tx.NewClause(&recipient).WithValue(big.NewInt(10000)))
+
+	packer := packer.New(repo, stater, genesis.DevAccounts()[0].Address, &genesis.DevAccounts()[0].Address, thor.NoFork)
+	sum, _ := repo.GetBlockSummary(b.Header().ID())
+	flow, err := packer.Schedule(sum, uint64(time.Now().Unix()))
+	require.NoError(t, err)
+	require.NoError(t, flow.Adopt(trx))
+	blk, stage, receipts, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey, 0, false)
+	require.NoError(t, err)
+	_, err = stage.Commit()
+	require.NoError(t, err)
+	require.NoError(t, repo.AddBlock(blk, receipts, 0))
+	require.NoError(t, repo.SetBestBlockID(blk.Header().ID()))
+
+	logDB, err := logdb.NewMem()
+	require.NoError(t, err)
+	w := logDB.NewWriter()
+	require.NoError(t, w.Write(blk, receipts))
+	require.NoError(t, w.Commit())
+
+	txPool := txpool.New(repo, stater, txpool.Options{Limit: 10000, LimitPerAccount: 16, MaxLifetime: 10 * time.Minute})
+	t.Cleanup(txPool.Close)
+
+	handler, closeFunc := api.New(
+		repo,
+		stater,
+		txPool,
+		newSchedule(t),
+		logDB,
+		solo.NewBFTEngine(repo),
+		&solo.Communicator{},
+		&mockOptimizer{},
+		thor.NoFork,
+		"1.0.0-test",
+		"*",
+		1000,
+		40_000_000,
+		false,
+		false,
+		true,
+		true,
+		true,
+		false,
+		false,
+		1000,
+		256,
+		[]string{"all"},
+		health.Options{MinPeers: 1},
+		nil,
+		1024*1024,
+		false,
+	)
+	ts := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		closeFunc()
+		ts.Close()
+	})
+
+	return &testEnv{
+		repo:   repo,
+		block:  blk,
+		tx:     trx,
+		client: thorclient.New(ts.URL+"/", thorclient.Options{}),
+	}
+}
+
+func TestClient(t *testing.T) {
+	env := initEnv(t)
+
+	for name, tt := range map[string]func(*testing.T, *testEnv){
+		"accounts":      testAccounts,
+		"blocks":        testBlocks,
+		"transactions":  testTransactions,
+		"logs":          testLogs,
+		"node":          testNode,
+		"debug":         testDebug,
+		"subscriptions": testSubscriptions,
+		"mux":           testMux,
+		"error":         testError,
+	} {
+		t.Run(name, func(t *testing.T) {
+			tt(t, env)
+		})
+	}
+}
+
+func testAccounts(t *testing.T, env *testEnv) {
+	c := env.client
+
+	acc, err := c.Account(recipient, "")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(10000), (*big.Int)(&acc.Balance))
+
+	acc, err = c.Account(recipient, "0")
+	require.NoError(t, err)
+	assert.Equal(t, 0, (*big.Int)(&acc.Balance).Sign())
+
+	code, err := c.AccountCode(recipient, "best")
+	require.NoError(t, err)
+	assert.Equal(t, "0x", code.Code)
+
+	storage, err := c.AccountStorage(recipient, thor.Bytes32{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, thor.Bytes32{}.String(), storage.Value)
+
+	results, err := c.InspectClauses(&accounts.BatchCallData{
+		Clauses: accounts.Clauses{{To: &recipient, Value: (*math.HexOrDecimal256)(big.NewInt(1))}},
+		Caller:  &genesis.DevAccounts()[0].Address,
+	}, "")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.False(t, results[0].Reverted)
+	require.Len(t, results[0].Transfers, 1)
+
+	res, err := c.CallContract(&recipient, &accounts.CallData{Caller: &genesis.DevAccounts()[0].Address}, "")
+	require.NoError(t, err)
+	assert.False(t, res.Reverted)
+}
+
+func testBlocks(t *testing.T, env *testEnv) {
+	c := env.client
+	id := env.block.Header().ID()
+
+	blk, err := c.Block("best")
+	require.NoError(t, err)
+	assert.Equal(t, id, blk.ID)
+	assert.Equal(t, []thor.Bytes32{env.tx.ID()}, blk.Transactions)
+
+	blk, err = c.Block(id.String())
+	require.NoError(t, err)
+	assert.Equal(t, id, blk.ID)
+
+	expanded, err := c.ExpandedBlock("1")
+	require.NoError(t, err)
+	require.Len(t, expanded.Transactions, 1)
+	assert.Equal(t, env.tx.ID(), expanded.Transactions[0].ID)
+
+	blk, err = c.Block("100")
+	require.NoError(t, err)
+	assert.Nil(t, blk)
+}
+
+func testTransactions(t *testing.T, env *testEnv) {
+	c := env.client
+	id := env.tx.ID()
+
+	trx, err := c.Transaction(id, nil)
+	require.NoError(t, err)
+	assert.Equal(t, id, trx.ID)
+	assert.Equal(t, env.block.Header().ID(), trx.Meta.BlockID)
+
+	raw, err := c.RawTransaction(id, &thorclient.TxOptions{Pending: true})
+	require.NoError(t, err)
+	assert.NotEmpty(t, raw.Raw)
+
+	receipt, err := c.TransactionReceipt(id, nil)
+	require.NoError(t, err)
+	assert.False(t, receipt.Reverted)
+
+	missing, err := c.Transaction(thor.Bytes32{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	// send to the pool
+	pending := newTx(t, env.repo, 2)
+	res, err := c.SendTransaction(pending)
+	require.NoError(t, err)
+	assert.Equal(t, pending.ID(), res.ID)
+
+	poolTx, err := c.PoolTransaction(pending.ID())
+	require.NoError(t, err)
+	assert.Equal(t, pending.ID(), poolTx.ID)
+
+	origin := genesis.DevAccounts()[0].Address
+	poolTxs, err := c.PoolTransactions(&origin, nil)
+	require.NoError(t, err)
+	assert.Len(t, poolTxs, 1)
+
+	status, err := c.TxPoolStatus()
+	require.NoError(t, err)
+	assert.Equal(t, 1, status.Total)
+
+	batch, err := c.SendTransactions([]*tx.Transaction{newTx(t, env.repo, 3), newTx(t, env.repo, 4)}, false)
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+	for _, r := range batch {
+		assert.NotNil(t, r.ID)
+	}
+}
+
+func testLogs(t *testing.T, env *testEnv) {
+	c := env.client
+
+	transferLogs, cursor, err := c.FilterTransfers(&transfers.TransferFilter{
+		CriteriaSet: []*logdb.TransferCriteria{{Recipient: &recipient}},
+		Options:     &logdb.Options{Limit: 10},
+	})
+	require.NoError(t, err)
+	require.Len(t, transferLogs, 1)
+	assert.Equal(t, env.tx.ID(), transferLogs[0].Meta.TxID)
+	require.NotNil(t, cursor)
+
+	// continue after the cursor
+	transferLogs, cursor, err = c.FilterTransfers(&transfers.TransferFilter{
+		CriteriaSet: []*logdb.TransferCriteria{{Recipient: &recipient}},
+		Options:     &logdb.Options{Limit: 10, Cursor: cursor},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, transferLogs)
+	assert.Nil(t, cursor)
+
+	eventLogs, cursor, err := c.FilterEvents(&events.EventFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, eventLogs)
+	assert.Nil(t, cursor)
+
+	summaries, err := c.SummarizeTransfers(&transfers.TransferSummaryFilter{
+		CriteriaSet: []*logdb.TransferCriteria{{Recipient: &recipient}},
+	})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, uint64(1), summaries[0].Count)
+	assert.Equal(t, uint64(1), summaries[0].Senders)
+
+	counts, err := c.CountEvents(&events.EventCountFilter{Bucket: 3600})
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+
+	deploymentLogs, cursor, err := c.FilterDeployments(&deployments.DeploymentFilter{
+		CriteriaSet: []*logdb.DeploymentCriteria{{Deployer: &genesis.DevAccounts()[0].Address}},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, deploymentLogs)
+	assert.Nil(t, cursor)
+
+	failureLogs, cursor, err := c.FilterFailures(&failures.FailureFilter{
+		CriteriaSet: []*failures.FailureCriteria{{To: &recipient}},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, failureLogs)
+	assert.Nil(t, cursor)
+
+	tokenTransferLogs, cursor, err := c.FilterTokenTransfers(&tokentransfers.TokenTransferFilter{
+		CriteriaSet: []*logdb.TokenTransferCriteria{{Recipient: &recipient}},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, tokenTransferLogs)
+	assert.Nil(t, cursor)
+
+	_, err = c.AccountCreation(recipient)
+	var e *thorclient.Error
+	require.ErrorAs(t, err, &e)
+	assert.Equal(t, http.StatusNotFound, e.StatusCode)
+}
+
+func testNode(t *testing.T, env *testEnv) {
+	c := env.client
+
+	peers, err := c.Peers()
+	require.NoError(t, err)
+	assert.Empty(t, peers)
+
+	info, err := c.NodeInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0-test", info.Version)
+	assert.Equal(t, env.block.Header().ID(), info.Best.ID)
+
+	live, err := c.Liveness()
+	require.NoError(t, err)
+	assert.True(t, live.Alive)
+
+	// not ready since no peers connected
+	ready, err := c.Readiness()
+	require.NoError(t, err)
+	assert.False(t, ready.Ready)
+	assert.NotEmpty(t, ready.Checks)
+}
+
+func testDebug(t *testing.T, env *testEnv) {
+	c := env.client
+	target := env.block.Header().ID().String() + "/" + env.tx.ID().String()
+
+	res, err := c.TraceClause(&debug.TraceClauseOption{Name: "call", Target: target + "/0"})
+	require.NoError(t, err)
+	assert.Contains(t, string(res), `"type":"CALL"`)
+
+	traces, err := c.TraceTransaction(&debug.TraceClauseOption{Name: "call", Target: target})
+	require.NoError(t, err)
+	require.Len(t, traces, 1)
+	assert.Equal(t, uint32(0), traces[0].ClauseIndex)
+
+	traces, err = c.TraceBlock(&debug.TraceClauseOption{Name: "call", Target: env.block.Header().ID().String()})
+	require.NoError(t, err)
+	assert.Len(t, traces, 1)
+
+	res, err = c.TraceCall(&debug.TraceCallOption{Name: "call", To: &recipient, Caller: &genesis.DevAccounts()[0].Address}, "")
+	require.NoError(t, err)
+	assert.Contains(t, string(res), `"type":"CALL"`)
+
+	storage, err := c.StorageRange(&debug.StorageRangeOption{Address: recipient, Target: target + "/0", MaxResult: 10})
+	require.NoError(t, err)
+	assert.Empty(t, storage.Storage)
+}
+
+func receive[T any](t *testing.T, sub *thorclient.Subscription[T]) T {
+	select {
+	case msg, ok := <-sub.Messages():
+		require.True(t, ok, "subscription ended: %v", sub.Err())
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+	panic("unreachable")
+}
+
+func testSubscriptions(t *testing.T, env *testEnv) {
+	c := env.client
+	genesisID := env.repo.GenesisBlock().Header().ID().String()
+	id := env.block.Header().ID()
+
+	blocks, err := c.SubscribeBlocks(genesisID)
+	require.NoError(t, err)
+	defer blocks.Close()
+	assert.Equal(t, id, receive(t, blocks).ID)
+
+	beats, err := c.SubscribeBeats2(genesisID)
+	require.NoError(t, err)
+	defer beats.Close()
+	assert.Equal(t, id, receive(t, beats).ID)
+
+	transferSub, err := c.SubscribeTransfers(genesisID, &subscriptions.TransferFilter{Recipient: &recipient})
+	require.NoError(t, err)
+	defer transferSub.Close()
+	assert.Equal(t, env.tx.ID(), receive(t, transferSub).Meta.TxID)
+
+	status, err := c.SubscribeTxStatus(env.tx.ID())
+	require.NoError(t, err)
+	defer status.Close()
+	assert.Equal(t, "included", receive(t, status).Status)
+
+	// closed subscriptions end without error
+	require.NoError(t, blocks.Close())
+	for range blocks.Messages() {
+	}
+	assert.NoError(t, blocks.Err())
+
+	// rejected handshake
+	_, err = c.SubscribeBlocks("invalid")
+	var e *thorclient.Error
+	require.ErrorAs(t, err, &e)
+	assert.Equal(t, http.StatusBadRequest, e.StatusCode)
+}
+
+func testMux(t *testing.T, env *testEnv) {
+	conn, err := env.client.DialMux()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sub, err := conn.Subscribe("transfer", &subscriptions.TransferFilter{Recipient: &recipient})
+	require.NoError(t, err)
+	assert.NotEmpty(t, sub)
+
+	_, err = conn.Subscribe("unknown", nil)
+	assert.Error(t, err)
+
+	require.NoError(t, conn.Unsubscribe(sub))
+	assert.Error(t, conn.Unsubscribe(sub))
+}
+
+func testError(t *testing.T, env *testEnv) {
+	_, err := env.client.Account(recipient, "invalid")
+	var e *thorclient.Error
+	require.ErrorAs(t, err, &e)
+	assert.Equal(t, http.StatusBadRequest, e.StatusCode)
+	assert.Contains(t, e.Message, "revision")
+}
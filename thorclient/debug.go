@@ -0,0 +1,72 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package thorclient
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/vechain/thor/v2/api/debug"
+)
+
+// TraceClause traces the clause by the target "blockID/txIndex|txID/clauseIndex".
+func (c *Client) TraceClause(opt *debug.TraceClauseOption) (json.RawMessage, error) {
+	var res json.RawMessage
+	if _, err := c.post("/debug/tracers", nil, opt, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// TraceTransaction traces all clauses of the tx by the target "blockID/txIndex|txID".
+// If tracing failed halfway, the traces done are returned along with the error.
+func (c *Client) TraceTransaction(opt *debug.TraceClauseOption) ([]*debug.ClauseTrace, error) {
+	return c.traces("/debug/tracers/transaction", opt)
+}
+
+// TraceBlock traces all clauses in the block by the target block ID.
+// If tracing failed halfway, the traces done are returned along with the error.
+func (c *Client) TraceBlock(opt *debug.TraceClauseOption) ([]*debug.ClauseTrace, error) {
+	return c.traces("/debug/tracers/block", opt)
+}
+
+func (c *Client) traces(path string, opt *debug.TraceClauseOption) ([]*debug.ClauseTrace, error) {
+	// the error occurred after the first trace is streamed as the last element
+	var elems []struct {
+		*debug.ClauseTrace
+		Error string `json:"error"`
+	}
+	if _, err := c.post(path, nil, opt, &elems); err != nil {
+		return nil, err
+	}
+
+	traces := make([]*debug.ClauseTrace, 0, len(elems))
+	for _, elem := range elems {
+		if elem.Error != "" {
+			return traces, errors.New(elem.Error)
+		}
+		traces = append(traces, elem.ClauseTrace)
+	}
+	return traces, nil
+}
+
+// TraceCall traces the simulated call at the revision, which may also be "next".
+func (c *Client) TraceCall(opt *debug.TraceCallOption, revision string) (json.RawMessage, error) {
+	var res json.RawMessage
+	if _, err := c.post("/debug/tracers/call", revisionQuery(revision), opt, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// StorageRange returns the storage of the account after the clause by the target "blockID/txIndex|txID/clauseIndex".
+func (c *Client) StorageRange(opt *debug.StorageRangeOption) (*debug.StorageRangeResult, error) {
+	var res *debug.StorageRangeResult
+	if _, err := c.post("/debug/storage-range", nil, opt, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package thorclient
+
+import (
+	"net/http"
+
+	"github.com/vechain/thor/v2/api/deployments"
+	"github.com/vechain/thor/v2/api/events"
+	"github.com/vechain/thor/v2/api/failures"
+	"github.com/vechain/thor/v2/api/tokentransfers"
+	"github.com/vechain/thor/v2/api/transfers"
+	"github.com/vechain/thor/v2/api/utils"
+	"github.com/vechain/thor/v2/logdb"
+)
+
+// FilterEvents returns the event logs matching the filter, and the cursor pointing to the last returned log
+// which is nil if no log returned. Pass the cursor as options.cursor to fetch the next page.
+func (c *Client) FilterEvents(filter *events.EventFilter) ([]*events.FilteredEvent, *logdb.Cursor, error) {
+	var res []*events.FilteredEvent
+	header, err := c.post("/logs/event", nil, filter, &res)
+	if err != nil {
+		return nil, nil, err
+	}
+	cursor, err := parseCursor(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, cursor, nil
+}
+
+// FilterTransfers returns the transfer logs matching the filter, and the cursor pointing to the last returned log.
+func (c *Client) FilterTransfers(filter *transfers.TransferFilter) ([]*transfers.FilteredTransfer, *logdb.Cursor, error) {
+	var res []*transfers.FilteredTransfer
+	header, err := c.post("/logs/transfer", nil, filter, &res)
+	if err != nil {
+		return nil, nil, err
+	}
+	cursor, err := parseCursor(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, cursor, nil
+}
+
+// FilterDeployments returns the contract deployments matching the filter, and the cursor pointing to the last returned one.
+func (c *Client) FilterDeployments(filter *deployments.DeploymentFilter) ([]*deployments.FilteredDeployment, *logdb.Cursor, error) {
+	var res []*deployments.FilteredDeployment
+	header, err := c.post("/logs/deployment", nil, filter, &res)
+	if err != nil {
+		return nil, nil, err
+	}
+	cursor, err := parseCursor(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, cursor, nil
+}
+
+// FilterFailures returns the clauses of reverted txs matching the filter, and the cursor pointing to the last returned one.
+func (c *Client) FilterFailures(filter *failures.FailureFilter) ([]*failures.FilteredFailure, *logdb.Cursor, error) {
+	var res []*failures.FilteredFailure
+	header, err := c.post("/logs/failure", nil, filter, &res)
+	if err != nil {
+		return nil, nil, err
+	}
+	cursor, err := parseCursor(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, cursor, nil
+}
+
+// FilterTokenTransfers returns the fungible token transfers matching the filter, and the cursor pointing to the last returned one.
+func (c *Client) FilterTokenTransfers(filter *tokentransfers.TokenTransferFilter) ([]*tokentransfers.FilteredTokenTransfer, *logdb.Cursor, error) {
+	var res []*tokentransfers.FilteredTokenTransfer
+	header, err := c.post("/logs/token-transfer", nil, filter, &res)
+	if err != nil {
+		return nil, nil, err
+	}
+	cursor, err := parseCursor(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, cursor, nil
+}
+
+// CountEvents returns the numbers of events matching the filter, by buckets of block time if filter.Bucket is set.
+func (c *Client) CountEvents(filter *events.EventCountFilter) ([]*events.EventCount, error) {
+	var res []*events.EventCount
+	if _, err := c.post("/logs/event/count", nil, filter, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SummarizeTransfers returns the summaries of transfers matching the filter, by buckets of block time if filter.Bucket is set.
+func (c *Client) SummarizeTransfers(filter *transfers.TransferSummaryFilter) ([]*transfers.TransferSummary, error) {
+	var res []*transfers.TransferSummary
+	if _, err := c.post("/logs/transfer/summary", nil, filter, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// parseCursor parses the cursor from the response header, nil if absent.
+func parseCursor(header http.Header) (*logdb.Cursor, error) {
+	value := header.Get(utils.NextCursorHeader)
+	if value == "" {
+		return nil, nil
+	}
+	var cursor logdb.Cursor
+	if err := cursor.UnmarshalText([]byte(value)); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package thorclient
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/vechain/thor/v2/api/health"
+	"github.com/vechain/thor/v2/api/node"
+	"github.com/vechain/thor/v2/api/pool"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// Peers returns the stats of connected peers.
+func (c *Client) Peers() ([]*node.PeerStats, error) {
+	var peers []*node.PeerStats
+	if _, err := c.get("/node/network/peers", nil, &peers); err != nil {
+		return nil, err
+	}
+	return peers, nil
+}
+
+// NodeInfo returns the info of the node.
+func (c *Client) NodeInfo() (*node.Info, error) {
+	var info *node.Info
+	if _, err := c.get("/node/info", nil, &info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// TxPoolStatus returns the status of the tx pool.
+func (c *Client) TxPoolStatus() (*pool.Status, error) {
+	var status *pool.Status
+	if _, err := c.get("/txpool/status", nil, &status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// PoolTransactions returns the txs in the pool, filtered by the origin and the executable flag if not nil.
+func (c *Client) PoolTransactions(origin *thor.Address, executable *bool) ([]*pool.Transaction, error) {
+	query := url.Values{}
+	if origin != nil {
+		query.Set("origin", origin.String())
+	}
+	if executable != nil {
+		query.Set("executable", strconv.FormatBool(*executable))
+	}
+
+	var txs []*pool.Transaction
+	if _, err := c.get("/txpool/transactions", query, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// PoolTransaction returns the tx in the pool, nil if not found.
+func (c *Client) PoolTransaction(id thor.Bytes32) (*pool.Transaction, error) {
+	var trx *pool.Transaction
+	if _, err := c.get("/txpool/transactions/"+id.String(), nil, &trx); err != nil {
+		return nil, err
+	}
+	return trx, nil
+}
+
+// Liveness returns the liveness of the node.
+func (c *Client) Liveness() (*health.Liveness, error) {
+	var live *health.Liveness
+	if _, err := c.get("/health/live", nil, &live); err != nil {
+		return nil, err
+	}
+	return live, nil
+}
+
+// Readiness returns the readiness of the node, a not ready node is not treated as an error.
+func (c *Client) Readiness() (*health.Readiness, error) {
+	var ready *health.Readiness
+	if _, err := c.get("/health/ready", nil, &ready); err != nil {
+		// the checks are responded along with 503
+		var e *Error
+		if errors.As(err, &e) && e.StatusCode == http.StatusServiceUnavailable {
+			if json.Unmarshal([]byte(e.Message), &ready) == nil {
+				return ready, nil
+			}
+		}
+		return nil, err
+	}
+	return ready, nil
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+//go:build race
+
+package thorclient_test
+
+import (
+	"testing"
+
+	"github.com/vechain/thor/v2/schedule"
+)
+
+// newSchedule returns no schedule in race builds, see the one in schedule_test.go.
+func newSchedule(_ *testing.T) *schedule.Schedule {
+	return nil
+}
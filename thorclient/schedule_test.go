@@ -0,0 +1,35 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+//go:build !race
+
+package thorclient_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/schedule"
+)
+
+// newSchedule opens a schedule in a temp dir, which is left out of race builds since boltdb fails the pointer checks.
+func newSchedule(t *testing.T) *schedule.Schedule {
+	sched, err := schedule.NewSchedule(filepath.Join(t.TempDir(), "schedule.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { sched.Close() })
+	return sched
+}
+
+func TestScheduleTransaction(t *testing.T) {
+	env := initEnv(t)
+
+	scheduled := newTx(t, env.repo, 5)
+	res, err := env.client.ScheduleTransaction(scheduled, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, scheduled.ID(), res.ID)
+}
@@ -0,0 +1,444 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package thorclient
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/websocket"
+	"github.com/vechain/thor/v2/api/subscriptions"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// Subscription a websocket subscription, messages are delivered by the channel returned by Messages.
+type Subscription[T any] struct {
+	conn   *websocket.Conn
+	msgs   chan T
+	err    error
+	done   chan struct{}
+	closed sync.Once
+}
+
+func newSubscription[T any](conn *websocket.Conn, decode func([]byte) (T, error)) *Subscription[T] {
+	s := &Subscription[T]{
+		conn: conn,
+		msgs: make(chan T),
+		done: make(chan struct{}),
+	}
+	go s.loop(decode)
+	return s
+}
+
+func (s *Subscription[T]) loop(decode func([]byte) (T, error)) {
+	defer close(s.msgs)
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-s.done:
+				// closed by the client
+			default:
+				if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					s.err = err
+				}
+			}
+			return
+		}
+		msg, err := decode(data)
+		if err != nil {
+			s.err = err
+			return
+		}
+		select {
+		case s.msgs <- msg:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Messages returns the channel of messages, which is closed once the subscription ends.
+func (s *Subscription[T]) Messages() <-chan T {
+	return s.msgs
+}
+
+// Err returns the error which ended the subscription, nil if closed normally.
+// It should be called after the messages channel is closed.
+func (s *Subscription[T]) Err() error {
+	return s.err
+}
+
+// Close closes the subscription.
+func (s *Subscription[T]) Close() error {
+	var err error
+	s.closed.Do(func() {
+		close(s.done)
+		_ = s.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		err = s.conn.Close()
+	})
+	return err
+}
+
+// Finalizable a message of finalized subscriptions, which is either a message of the subject,
+// or a marker of the finalized block after all messages of the block are delivered.
+type Finalizable[T any] struct {
+	Message   T                             // zero value if it's a finalized marker
+	Finalized *subscriptions.FinalizedBlock // nil if it's a message of the subject
+}
+
+func decodeJSON[T any](data []byte) (T, error) {
+	var msg T
+	err := json.Unmarshal(data, &msg)
+	return msg, err
+}
+
+func decodeFinalizable[T any](data []byte) (*Finalizable[T], error) {
+	var marker struct {
+		Finalized *subscriptions.FinalizedBlock `json:"finalized"`
+	}
+	if err := json.Unmarshal(data, &marker); err != nil {
+		return nil, err
+	}
+	if marker.Finalized != nil {
+		return &Finalizable[T]{Finalized: marker.Finalized}, nil
+	}
+	msg, err := decodeJSON[T](data)
+	if err != nil {
+		return nil, err
+	}
+	return &Finalizable[T]{Message: msg}, nil
+}
+
+// dial connects the websocket of the path.
+func (c *Client) dial(path string, query url.Values) (*websocket.Conn, error) {
+	u, err := url.Parse(c.url + path)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.RawQuery = query.Encode()
+
+	conn, res, err := c.opts.WSDialer.Dial(u.String(), c.header)
+	if err != nil {
+		// the handshake is rejected by the API
+		if res != nil && res.StatusCode != http.StatusSwitchingProtocols {
+			defer res.Body.Close()
+			return nil, newError(res)
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+func subscribe[T any](c *Client, path string, query url.Values) (*Subscription[T], error) {
+	conn, err := c.dial(path, query)
+	if err != nil {
+		return nil, err
+	}
+	return newSubscription(conn, decodeJSON[T]), nil
+}
+
+func subscribeFinalized[T any](c *Client, path string, query url.Values) (*Subscription[*Finalizable[T]], error) {
+	query.Set("finalized", "true")
+	conn, err := c.dial(path, query)
+	if err != nil {
+		return nil, err
+	}
+	return newSubscription(conn, decodeFinalizable[T]), nil
+}
+
+// positionQuery returns the query of the position to start, an empty position refers to the best block.
+func positionQuery(pos string) url.Values {
+	query := url.Values{}
+	if pos != "" {
+		query.Set("pos", pos)
+	}
+	return query
+}
+
+func eventQuery(pos string, filter *subscriptions.EventFilter) url.Values {
+	query := positionQuery(pos)
+	if filter != nil {
+		if filter.Address != nil {
+			query.Set("addr", filter.Address.String())
+		}
+		for i, topic := range []*thor.Bytes32{filter.Topic0, filter.Topic1, filter.Topic2, filter.Topic3, filter.Topic4} {
+			if topic != nil {
+				query.Set("t"+strconv.Itoa(i), topic.String())
+			}
+		}
+	}
+	return query
+}
+
+func transferQuery(pos string, filter *subscriptions.TransferFilter) url.Values {
+	query := positionQuery(pos)
+	if filter != nil {
+		if filter.TxOrigin != nil {
+			query.Set("txOrigin", filter.TxOrigin.String())
+		}
+		if filter.Sender != nil {
+			query.Set("sender", filter.Sender.String())
+		}
+		if filter.Recipient != nil {
+			query.Set("recipient", filter.Recipient.String())
+		}
+	}
+	return query
+}
+
+// SubscribeBlocks subscribes new blocks, starting from the block after pos.
+func (c *Client) SubscribeBlocks(pos string) (*Subscription[*subscriptions.BlockMessage], error) {
+	return subscribe[*subscriptions.BlockMessage](c, "/subscriptions/block", positionQuery(pos))
+}
+
+// SubscribeFinalizedBlocks subscribes finalized blocks, starting from the block after pos.
+func (c *Client) SubscribeFinalizedBlocks(pos string) (*Subscription[*Finalizable[*subscriptions.BlockMessage]], error) {
+	return subscribeFinalized[*subscriptions.BlockMessage](c, "/subscriptions/block", positionQuery(pos))
+}
+
+// SubscribeEvents subscribes event logs matching the filter.
+func (c *Client) SubscribeEvents(pos string, filter *subscriptions.EventFilter) (*Subscription[*subscriptions.EventMessage], error) {
+	return subscribe[*subscriptions.EventMessage](c, "/subscriptions/event", eventQuery(pos, filter))
+}
+
+// SubscribeFinalizedEvents subscribes event logs of finalized blocks matching the filter.
+func (c *Client) SubscribeFinalizedEvents(pos string, filter *subscriptions.EventFilter) (*Subscription[*Finalizable[*subscriptions.EventMessage]], error) {
+	return subscribeFinalized[*subscriptions.EventMessage](c, "/subscriptions/event", eventQuery(pos, filter))
+}
+
+// SubscribeTransfers subscribes transfer logs matching the filter.
+func (c *Client) SubscribeTransfers(pos string, filter *subscriptions.TransferFilter) (*Subscription[*subscriptions.TransferMessage], error) {
+	return subscribe[*subscriptions.TransferMessage](c, "/subscriptions/transfer", transferQuery(pos, filter))
+}
+
+// SubscribeFinalizedTransfers subscribes transfer logs of finalized blocks matching the filter.
+func (c *Client) SubscribeFinalizedTransfers(pos string, filter *subscriptions.TransferFilter) (*Subscription[*Finalizable[*subscriptions.TransferMessage]], error) {
+	return subscribeFinalized[*subscriptions.TransferMessage](c, "/subscriptions/transfer", transferQuery(pos, filter))
+}
+
+// SubscribeBeats subscribes block beats.
+//
+// Deprecated: use SubscribeBeats2.
+func (c *Client) SubscribeBeats(pos string) (*Subscription[*subscriptions.BeatMessage], error) {
+	return subscribe[*subscriptions.BeatMessage](c, "/subscriptions/beat", positionQuery(pos))
+}
+
+// SubscribeBeats2 subscribes block beats.
+func (c *Client) SubscribeBeats2(pos string) (*Subscription[*subscriptions.Beat2Message], error) {
+	return subscribe[*subscriptions.Beat2Message](c, "/subscriptions/beat2", positionQuery(pos))
+}
+
+// SubscribeTxPool subscribes IDs of txs becoming executable in the pool.
+func (c *Client) SubscribeTxPool() (*Subscription[*subscriptions.PendingTxIDMessage], error) {
+	return subscribe[*subscriptions.PendingTxIDMessage](c, "/subscriptions/txpool", nil)
+}
+
+// SubscribePendingTxs subscribes status updates of txs in the pool matching the filter, which is optional.
+// The txs are included in messages if expanded.
+func (c *Client) SubscribePendingTxs(filter *subscriptions.PendingTxFilter, expanded bool) (*Subscription[*subscriptions.PendingTxMessage], error) {
+	// expanded is always given, to tell from the legacy subscription of tx IDs
+	query := url.Values{"expanded": {"false"}}
+	if expanded {
+		query.Set("expanded", "true")
+	}
+	if filter != nil {
+		if filter.Origin != nil {
+			query.Set("origin", filter.Origin.String())
+		}
+		if filter.Delegator != nil {
+			query.Set("delegator", filter.Delegator.String())
+		}
+		if filter.To != nil {
+			query.Set("to", filter.To.String())
+		}
+		if len(filter.Selector) > 0 {
+			query.Set("selector", hexutil.Encode(filter.Selector))
+		}
+	}
+	return subscribe[*subscriptions.PendingTxMessage](c, "/subscriptions/txpool", query)
+}
+
+// SubscribeTxStatus subscribes the status of the tx, the subscription ends once the tx is finalized, expired or dropped.
+func (c *Client) SubscribeTxStatus(id thor.Bytes32) (*Subscription[*subscriptions.TxStatusMessage], error) {
+	return subscribe[*subscriptions.TxStatusMessage](c, "/subscriptions/tx/"+id.String(), nil)
+}
+
+// MuxPush a message pushed by the multiplexed websocket.
+type MuxPush struct {
+	Subscription string          `json:"subscription"`
+	Data         json.RawMessage `json:"data"`
+}
+
+// MuxConn the multiplexed websocket, carrying multiple subscriptions.
+type MuxConn struct {
+	conn *websocket.Conn
+
+	lock    sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan *subscriptions.MuxResponse
+
+	pushes chan *MuxPush
+	err    error
+	done   chan struct{}
+	closed sync.Once
+}
+
+// DialMux connects the multiplexed websocket.
+func (c *Client) DialMux() (*MuxConn, error) {
+	conn, err := c.dial("/subscriptions/ws", nil)
+	if err != nil {
+		return nil, err
+	}
+	m := &MuxConn{
+		conn:    conn,
+		pending: make(map[uint64]chan *subscriptions.MuxResponse),
+		pushes:  make(chan *MuxPush),
+		done:    make(chan struct{}),
+	}
+	go m.loop()
+	return m, nil
+}
+
+func (m *MuxConn) loop() {
+	defer func() {
+		m.lock.Lock()
+		for id, ch := range m.pending {
+			close(ch)
+			delete(m.pending, id)
+		}
+		m.lock.Unlock()
+		close(m.pushes)
+	}()
+
+	for {
+		_, data, err := m.conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-m.done:
+			default:
+				if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					m.err = err
+				}
+			}
+			return
+		}
+
+		// responses carry the request ID, while pushes carry the data
+		var msg struct {
+			ID *uint64 `json:"id"`
+			subscriptions.MuxResponse
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			m.err = err
+			return
+		}
+		if msg.ID == nil {
+			select {
+			case m.pushes <- &MuxPush{Subscription: msg.Subscription, Data: msg.Data}:
+			case <-m.done:
+				return
+			}
+			continue
+		}
+
+		m.lock.Lock()
+		if ch, ok := m.pending[*msg.ID]; ok {
+			resp := msg.MuxResponse
+			resp.ID = *msg.ID
+			ch <- &resp
+			delete(m.pending, *msg.ID)
+		}
+		m.lock.Unlock()
+	}
+}
+
+func (m *MuxConn) call(req *subscriptions.MuxRequest) (*subscriptions.MuxResponse, error) {
+	ch := make(chan *subscriptions.MuxResponse, 1)
+
+	m.lock.Lock()
+	m.nextID++
+	req.ID = m.nextID
+	m.pending[req.ID] = ch
+	err := m.conn.WriteJSON(req)
+	if err != nil {
+		delete(m.pending, req.ID)
+	}
+	m.lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, ok := <-ch
+	if !ok {
+		return nil, errors.New("connection closed")
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+// Subscribe subscribes the subject, with the optional filter of event or transfer subject,
+// e.g. subscriptions.EventFilter. The subscription ID is returned.
+func (m *MuxConn) Subscribe(subject string, filter interface{}) (string, error) {
+	req := &subscriptions.MuxRequest{Method: "subscribe", Subject: subject}
+	if filter != nil {
+		data, err := json.Marshal(filter)
+		if err != nil {
+			return "", err
+		}
+		req.Filter = data
+	}
+	resp, err := m.call(req)
+	if err != nil {
+		return "", err
+	}
+	return resp.Subscription, nil
+}
+
+// Unsubscribe cancels the subscription.
+func (m *MuxConn) Unsubscribe(subscription string) error {
+	_, err := m.call(&subscriptions.MuxRequest{Method: "unsubscribe", Subscription: subscription})
+	return err
+}
+
+// Messages returns the channel of pushed messages, which is closed once the connection ends.
+// The channel must be drained, otherwise responses of requests are blocked.
+func (m *MuxConn) Messages() <-chan *MuxPush {
+	return m.pushes
+}
+
+// Err returns the error which ended the connection, nil if closed normally.
+func (m *MuxConn) Err() error {
+	return m.err
+}
+
+// Close closes the connection.
+func (m *MuxConn) Close() error {
+	var err error
+	m.closed.Do(func() {
+		close(m.done)
+		m.lock.Lock()
+		_ = m.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		m.lock.Unlock()
+		err = m.conn.Close()
+	})
+	return err
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2024 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package thorclient
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/v2/api/transactions"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+// TxOptions options of getting a tx.
+type TxOptions struct {
+	Head    *thor.Bytes32 // the head block of the chain to look up the tx, defaults to the best block
+	Pending bool          // whether to look up the tx pool if not found in the chain
+}
+
+func (opts *TxOptions) query() url.Values {
+	query := url.Values{}
+	if opts != nil {
+		if opts.Head != nil {
+			query.Set("head", opts.Head.String())
+		}
+		if opts.Pending {
+			query.Set("pending", "true")
+		}
+	}
+	return query
+}
+
+// Transaction returns the tx, nil if not found.
+func (c *Client) Transaction(id thor.Bytes32, opts *TxOptions) (*transactions.Transaction, error) {
+	var trx *transactions.Transaction
+	if _, err := c.get("/transactions/"+id.String(), opts.query(), &trx); err != nil {
+		return nil, err
+	}
+	return trx, nil
+}
+
+// RawTransaction returns the RLP encoded tx, nil if not found.
+func (c *Client) RawTransaction(id thor.Bytes32, opts *TxOptions) (*transactions.RawTransaction, error) {
+	query := opts.query()
+	query.Set("raw", "true")
+
+	var trx *transactions.RawTransaction
+	if _, err := c.get("/transactions/"+id.String(), query, &trx); err != nil {
+		return nil, err
+	}
+	return trx, nil
+}
+
+// TransactionReceipt returns the receipt of the tx, nil if not found.
+// The head block defaults to the best block if nil.
+func (c *Client) TransactionReceipt(id thor.Bytes32, head *thor.Bytes32) (*transactions.Receipt, error) {
+	var receipt *transactions.Receipt
+	if _, err := c.get("/transactions/"+id.String()+"/receipt", (&TxOptions{Head: head}).query(), &receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// SendTransaction sends the signed tx.
+func (c *Client) SendTransaction(trx *tx.Transaction) (*transactions.SendTxResult, error) {
+	raw, err := encodeTx(trx)
+	if err != nil {
+		return nil, err
+	}
+	return c.SendRawTransaction(raw)
+}
+
+// SendRawTransaction sends the RLP encoded tx.
+func (c *Client) SendRawTransaction(raw *transactions.RawTx) (*transactions.SendTxResult, error) {
+	var res *transactions.SendTxResult
+	if _, err := c.post("/transactions", nil, raw, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SendTransactions sends a batch of signed txs, the result of each tx is in the same order.
+// If atomic, either all or none of the txs are accepted by the pool.
+func (c *Client) SendTransactions(txs []*tx.Transaction, atomic bool) ([]*transactions.BatchResult, error) {
+	raws := make([]*transactions.RawTx, len(txs))
+	for i, trx := range txs {
+		raw, err := encodeTx(trx)
+		if err != nil {
+			return nil, err
+		}
+		raws[i] = raw
+	}
+
+	var query url.Values
+	if atomic {
+		query = url.Values{"atomic": {strconv.FormatBool(atomic)}}
+	}
+	var res []*transactions.BatchResult
+	if _, err := c.post("/transactions/batch", query, raws, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ScheduleTransaction schedules the signed tx to be sent at the given time.
+func (c *Client) ScheduleTransaction(trx *tx.Transaction, at time.Time) (*transactions.SendTxResult, error) {
+	raw, err := encodeTx(trx)
+	if err != nil {
+		return nil, err
+	}
+	var res *transactions.SendTxResult
+	if _, err := c.post("/transactions/schedule", nil, &transactions.RawScheduledTx{Raw: raw.Raw, Time: at}, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func encodeTx(trx *tx.Transaction) (*transactions.RawTx, error) {
+	data, err := rlp.EncodeToBytes(trx)
+	if err != nil {
+		return nil, err
+	}
+	return &transactions.RawTx{Raw: hexutil.Encode(data)}, nil
+}
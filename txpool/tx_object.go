@@ -8,6 +8,7 @@ package txpool
 import (
 	"math/big"
 	"sort"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -19,6 +20,9 @@ import (
 	"github.com/vechain/thor/v2/tx"
 )
 
+// errKnownTx is returned by Executable if the tx is already included by the chain.
+var errKnownTx = errors.New("known tx")
+
 type txObject struct {
 	*tx.Transaction
 	resolved *runtime.ResolvedTransaction
@@ -30,6 +34,16 @@ type txObject struct {
 
 	executable      bool     // don't touch this value, will be updated by the pool
 	overallGasPrice *big.Int // don't touch this value, it's only be used in pool's housekeeping
+	pendingReason   string   // why the tx is not executable yet, updated along with executable
+
+	status atomic.Pointer[txStatus] // copy of the fields above published by the pool, safe to read concurrently
+}
+
+// txStatus is the immutable status of a tx object, published by the pool for concurrent readers.
+type txStatus struct {
+	executable      bool
+	overallGasPrice *big.Int
+	pendingReason   string
 }
 
 func resolveTx(tx *tx.Transaction, localSubmitted bool) (*txObject, error) {
@@ -76,13 +90,14 @@ func (o *txObject) Executable(chain *chain.Chain, state *state.State, headBlock
 	if has, err := chain.HasTransaction(o.ID(), o.BlockRef().Number()); err != nil {
 		return false, err
 	} else if has {
-		return false, errors.New("known tx")
+		return false, errKnownTx
 	}
 
 	if dep := o.DependsOn(); dep != nil {
 		txMeta, err := chain.GetTransactionMeta(*dep)
 		if err != nil {
 			if chain.IsNotFound(err) {
+				o.pendingReason = "dependency not settled"
 				return false, nil
 			}
 			return false, err
@@ -94,6 +109,7 @@ func (o *txObject) Executable(chain *chain.Chain, state *state.State, headBlock
 
 	// Tx is considered executable when the BlockRef has passed in reference to the next block.
 	if o.BlockRef().Number() > headBlock.Number()+1 {
+		o.pendingReason = "block ref in future"
 		return false, nil
 	}
 
@@ -109,9 +125,51 @@ func (o *txObject) Executable(chain *chain.Chain, state *state.State, headBlock
 		o.payer = &payer
 		o.cost = prepaid
 	}
+	o.pendingReason = ""
 	return true, nil
 }
 
+// publishStatus publishes the status fields, if changed since last published.
+// It should only be called by the goroutine updating the fields.
+func (o *txObject) publishStatus() {
+	if s := o.status.Load(); s != nil &&
+		s.executable == o.executable &&
+		s.overallGasPrice == o.overallGasPrice &&
+		s.pendingReason == o.pendingReason {
+		return
+	}
+	o.status.Store(&txStatus{
+		executable:      o.executable,
+		overallGasPrice: o.overallGasPrice,
+		pendingReason:   o.pendingReason,
+	})
+}
+
+// publishedStatus returns the last published status, which is safe to read concurrently.
+func (o *txObject) publishedStatus() *txStatus {
+	if s := o.status.Load(); s != nil {
+		return s
+	}
+	return &txStatus{}
+}
+
+func (o *txObject) info() *TxInfo {
+	status := o.publishedStatus()
+	info := &TxInfo{
+		Tx:             o.Transaction,
+		Origin:         o.Origin(),
+		Delegator:      o.Delegator(),
+		TimeAdded:      o.timeAdded,
+		LocalSubmitted: o.localSubmitted,
+		Executable:     status.executable,
+		PendingReason:  status.pendingReason,
+	}
+	if status.overallGasPrice != nil {
+		info.OverallGasPrice = new(big.Int).Set(status.overallGasPrice)
+	}
+	return info
+}
+
 func sortTxObjsByOverallGasPriceDesc(txObjs []*txObject) {
 	sort.Slice(txObjs, func(i, j int) bool {
 		gp1, gp2 := txObjs[i].overallGasPrice, txObjs[j].overallGasPrice
@@ -93,6 +93,45 @@ func (m *txObjectMap) Add(txObj *txObject, limitPerAccount int, validatePayer fu
 	return nil
 }
 
+// CheckCapacity checks whether the given txs fit in the map without exceeding the limit
+// and the account quota. Txs already in the map are not counted.
+func (m *txObjectMap) CheckCapacity(txs tx.Transactions, limit int, limitPerAccount int) error {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var (
+		count = len(m.mapByHash)
+		seen  = make(map[thor.Bytes32]bool)
+		quota = make(map[thor.Address]int)
+	)
+	for _, t := range txs {
+		hash := t.Hash()
+		if _, found := m.mapByHash[hash]; found || seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		count++
+
+		// txs with bad signature are not counted in quota, they will be rejected on adding
+		if origin, err := t.Origin(); err == nil {
+			quota[origin]++
+			if m.quota[origin]+quota[origin] > limitPerAccount {
+				return errors.New("account quota exceeded")
+			}
+		}
+		if delegator, err := t.Delegator(); err == nil && delegator != nil {
+			quota[*delegator]++
+			if m.quota[*delegator]+quota[*delegator] > limitPerAccount {
+				return errors.New("delegator quota exceeded")
+			}
+		}
+	}
+	if count > limit {
+		return errors.New("pool is full")
+	}
+	return nil
+}
+
 func (m *txObjectMap) GetByID(id thor.Bytes32) *txObject {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
@@ -194,3 +194,26 @@ func TestPendingCost(t *testing.T) {
 	m.RemoveByHash(txObj3.Hash())
 	assert.Nil(t, m.cost[genesis.DevAccounts()[2].Address])
 }
+
+func TestCheckCapacity(t *testing.T) {
+	db := muxdb.NewMem()
+	repo := newChainRepo(db)
+
+	tx1 := newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), genesis.DevAccounts()[0])
+	tx2 := newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), genesis.DevAccounts()[0])
+	tx3 := newDelegatedTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, genesis.DevAccounts()[1], genesis.DevAccounts()[0])
+	tx4 := newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), genesis.DevAccounts()[2])
+
+	txObj1, _ := resolveTx(tx1, false)
+
+	m := newTxObjectMap()
+	assert.Nil(t, m.Add(txObj1, 2, func(_ thor.Address, _ *big.Int) error { return nil }))
+
+	// txs already in the map are not counted
+	assert.Nil(t, m.CheckCapacity(tx.Transactions{tx1, tx2}, 2, 2))
+	assert.Nil(t, m.CheckCapacity(tx.Transactions{tx2, tx2}, 2, 2))
+	assert.Equal(t, errors.New("pool is full"), m.CheckCapacity(tx.Transactions{tx2, tx4}, 2, 2))
+	assert.Equal(t, errors.New("account quota exceeded"), m.CheckCapacity(tx.Transactions{tx1, tx2}, 10, 1))
+	assert.Equal(t, errors.New("delegator quota exceeded"), m.CheckCapacity(tx.Transactions{tx2, tx3}, 10, 2))
+	assert.Nil(t, m.CheckCapacity(tx.Transactions{tx3, tx4}, 10, 2))
+}
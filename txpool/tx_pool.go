@@ -12,6 +12,7 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -46,10 +47,36 @@ type Options struct {
 	BlocklistFetchURL      string
 }
 
-// TxEvent will be posted when tx is added or status changed.
+// TxEvent will be posted when tx is added, status changed or evicted.
 type TxEvent struct {
 	Tx         *tx.Transaction
 	Executable *bool
+	Evicted    bool   // the tx is washed out of the pool
+	Included   bool   // the tx is washed out since it's included by the chain, not an eviction
+	Reason     string // why the tx is evicted
+}
+
+// TxInfo is a snapshot of a pooled tx along with its pool metadata.
+type TxInfo struct {
+	Tx              *tx.Transaction
+	Origin          thor.Address
+	Delegator       *thor.Address
+	TimeAdded       int64 // unix nano
+	LocalSubmitted  bool
+	Executable      bool
+	OverallGasPrice *big.Int // nil if the tx has never been executable
+	PendingReason   string   // why the tx is not executable, empty if unknown or executable
+}
+
+// Status summarizes the pool's usage against its limits.
+type Status struct {
+	Total           int
+	Executable      int
+	Local           int
+	Origins         int
+	Limit           int
+	LimitPerAccount int
+	BlocklistSize   int
 }
 
 // TxPool maintains unprocessed transactions.
@@ -62,6 +89,7 @@ type TxPool struct {
 	executables    atomic.Value
 	all            *txObjectMap
 	addedAfterWash uint32
+	addLock        sync.RWMutex // held exclusively while adding a batch atomically
 
 	ctx    context.Context
 	cancel func()
@@ -209,15 +237,29 @@ func (p *TxPool) SubscribeTxEvent(ch chan *TxEvent) event.Subscription {
 }
 
 func (p *TxPool) add(newTx *tx.Transaction, rejectNonExecutable bool, localSubmitted bool) error {
+	p.addLock.RLock()
+	defer p.addLock.RUnlock()
+
+	ev, err := p.insert(newTx, rejectNonExecutable, localSubmitted)
+	if err != nil {
+		return err
+	}
+	p.sendTxEvents(ev)
+	return nil
+}
+
+// insert validates and puts the tx into the pool. The returned event is nil if the tx was not
+// inserted, that is, it's already in the pool or its origin is blocked.
+func (p *TxPool) insert(newTx *tx.Transaction, rejectNonExecutable bool, localSubmitted bool) (*TxEvent, error) {
 	if p.all.ContainsHash(newTx.Hash()) {
 		// tx already in the pool
-		return nil
+		return nil, nil
 	}
 
 	origin, _ := newTx.Origin()
 	if thor.IsOriginBlocked(origin) || p.blocklist.Contains(origin) {
 		// tx origin blocked
-		return nil
+		return nil, nil
 	}
 
 	headSummary := p.repo.BestBlockSummary()
@@ -229,39 +271,41 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonExecutable bool, localSubmi
 		rec := []byte{newTx.ChainTag()}
 		exp := []byte{p.repo.ChainTag()}
 		fmt.Printf("received %v | expected %v ", hex.EncodeToString(rec), hex.EncodeToString(exp))
-		return badTxError{"chain tag mismatch"}
+		return nil, badTxError{"chain tag mismatch"}
 	case newTx.Size() > maxTxSize:
-		return txRejectedError{"size too large"}
+		return nil, txRejectedError{"size too large"}
 	}
 
 	if err := newTx.TestFeatures(headSummary.Header.TxsFeatures()); err != nil {
-		return txRejectedError{err.Error()}
+		return nil, txRejectedError{err.Error()}
 	}
 
+	var ev *TxEvent
 	txObj, err := resolveTx(newTx, localSubmitted)
 	if err != nil {
-		return badTxError{err.Error()}
+		return nil, badTxError{err.Error()}
 	}
 
 	if isChainSynced(uint64(time.Now().Unix()), headSummary.Header.Timestamp()) {
 		if !localSubmitted {
 			// reject when pool size exceeds 120% of limit
 			if p.all.Len() >= p.options.Limit*12/10 {
-				return txRejectedError{"pool is full"}
+				return nil, txRejectedError{"pool is full"}
 			}
 		}
 
 		state := p.stater.NewState(headSummary.Header.StateRoot(), headSummary.Header.Number(), headSummary.Conflicts, headSummary.SteadyNum)
 		executable, err := txObj.Executable(p.repo.NewChain(headSummary.Header.ID()), state, headSummary.Header)
 		if err != nil {
-			return txRejectedError{err.Error()}
+			return nil, txRejectedError{err.Error()}
 		}
 
 		if rejectNonExecutable && !executable {
-			return txRejectedError{"tx is not executable"}
+			return nil, txRejectedError{"tx is not executable"}
 		}
 
 		txObj.executable = executable
+		txObj.publishStatus()
 		if err := p.all.Add(txObj, p.options.LimitPerAccount, func(payer thor.Address, needs *big.Int) error {
 			// check payer's balance
 			balance, err := state.GetEnergy(payer, headSummary.Header.Timestamp()+thor.BlockInterval)
@@ -275,31 +319,38 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonExecutable bool, localSubmi
 
 			return nil
 		}); err != nil {
-			return txRejectedError{err.Error()}
+			return nil, txRejectedError{err.Error()}
 		}
 
-		p.goes.Go(func() {
-			p.txFeed.Send(&TxEvent{newTx, &executable})
-		})
+		ev = &TxEvent{Tx: newTx, Executable: &executable}
 		logger.Debug("tx added", "id", newTx.ID(), "executable", executable)
 	} else {
 		// we skip steps that rely on head block when chain is not synced,
 		// but check the pool's limit
 		if p.all.Len() >= p.options.Limit {
-			return txRejectedError{"pool is full"}
+			return nil, txRejectedError{"pool is full"}
 		}
 
 		// skip pending cost check when chain is not synced
 		if err := p.all.Add(txObj, p.options.LimitPerAccount, func(_ thor.Address, _ *big.Int) error { return nil }); err != nil {
-			return txRejectedError{err.Error()}
+			return nil, txRejectedError{err.Error()}
 		}
+		ev = &TxEvent{Tx: newTx}
 		logger.Debug("tx added", "id", newTx.ID())
+	}
+	atomic.AddUint32(&p.addedAfterWash, 1)
+	return ev, nil
+}
+
+func (p *TxPool) sendTxEvents(evs ...*TxEvent) {
+	for _, ev := range evs {
+		if ev == nil {
+			continue
+		}
 		p.goes.Go(func() {
-			p.txFeed.Send(&TxEvent{newTx, nil})
+			p.txFeed.Send(ev)
 		})
 	}
-	atomic.AddUint32(&p.addedAfterWash, 1)
-	return nil
 }
 
 // Add adds a new tx into pool.
@@ -315,6 +366,37 @@ func (p *TxPool) AddLocal(newTx *tx.Transaction) error {
 	return p.add(newTx, false, true)
 }
 
+// AddLocalAtomically adds a batch of locally submitted txs into pool, either all or none of them.
+// The capacity check and the inserts are done without any other tx being added in between, and
+// the txs already inserted are removed if one fails. The index of the failed tx is returned along
+// with the error, or -1 if the batch exceeds the pool limit or an account quota.
+func (p *TxPool) AddLocalAtomically(txs tx.Transactions) (int, error) {
+	metricTxPoolGauge().AddWithLabel(int64(len(txs)), map[string]string{"source": "local", "total": "true"})
+
+	p.addLock.Lock()
+	defer p.addLock.Unlock()
+
+	if err := p.all.CheckCapacity(txs, p.options.Limit, p.options.LimitPerAccount); err != nil {
+		return -1, txRejectedError{err.Error()}
+	}
+
+	evs := make([]*TxEvent, 0, len(txs))
+	for i, trx := range txs {
+		ev, err := p.insert(trx, false, true)
+		if err != nil {
+			for _, added := range evs {
+				if added != nil {
+					p.all.RemoveByHash(added.Tx.Hash())
+				}
+			}
+			return i, err
+		}
+		evs = append(evs, ev)
+	}
+	p.sendTxEvents(evs...)
+	return -1, nil
+}
+
 // Get get pooled tx by id.
 func (p *TxPool) Get(id thor.Bytes32) *tx.Transaction {
 	if txObj := p.all.GetByID(id); txObj != nil {
@@ -367,13 +449,58 @@ func (p *TxPool) Dump() tx.Transactions {
 	return p.all.ToTxs()
 }
 
+// DumpInfo dumps all txs in the pool along with their metadata.
+func (p *TxPool) DumpInfo() []*TxInfo {
+	txObjs := p.all.ToTxObjects()
+	infos := make([]*TxInfo, 0, len(txObjs))
+	for _, txObj := range txObjs {
+		infos = append(infos, txObj.info())
+	}
+	return infos
+}
+
+// GetInfo gets pooled tx along with its metadata by id.
+func (p *TxPool) GetInfo(id thor.Bytes32) *TxInfo {
+	if txObj := p.all.GetByID(id); txObj != nil {
+		return txObj.info()
+	}
+	return nil
+}
+
+// Status returns the summary of pool usage.
+func (p *TxPool) Status() *Status {
+	status := &Status{
+		Limit:           p.options.Limit,
+		LimitPerAccount: p.options.LimitPerAccount,
+		BlocklistSize:   p.blocklist.Len(),
+	}
+
+	origins := make(map[thor.Address]struct{})
+	for _, txObj := range p.all.ToTxObjects() {
+		status.Total++
+		if txObj.publishedStatus().executable {
+			status.Executable++
+		}
+		if txObj.localSubmitted {
+			status.Local++
+		}
+		origins[txObj.Origin()] = struct{}{}
+	}
+	status.Origins = len(origins)
+	return status
+}
+
 // wash to evict txs that are over limit, out of lifetime, out of energy, settled, expired or dep broken.
 // this method should only be called in housekeeping go routine
 func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transactions, removed int, err error) {
 	all := p.all.ToTxObjects()
-	var toRemove []*txObject
+	var toRemove []*TxEvent // events of the txs to be removed
 	var toUpdateCost []*txObject
+	evict := func(txObj *txObject, reason string) {
+		toRemove = append(toRemove, &TxEvent{Tx: txObj.Transaction, Evicted: true, Reason: reason})
+	}
 	defer func() {
+		var removedEvs []*TxEvent
 		if err != nil {
 			// in case of error, simply cut pool size to limit
 			for i, txObj := range all {
@@ -381,11 +508,15 @@ func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transacti
 					break
 				}
 				removed++
-				p.all.RemoveByHash(txObj.Hash())
+				if p.all.RemoveByHash(txObj.Hash()) {
+					removedEvs = append(removedEvs, &TxEvent{Tx: txObj.Transaction, Evicted: true, Reason: "pool is full"})
+				}
 			}
 		} else {
-			for _, txObj := range toRemove {
-				p.all.RemoveByHash(txObj.Hash())
+			for _, ev := range toRemove {
+				if p.all.RemoveByHash(ev.Tx.Hash()) {
+					removedEvs = append(removedEvs, ev)
+				}
 			}
 			removed = len(toRemove)
 		}
@@ -393,6 +524,17 @@ func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transacti
 		for _, txObj := range toUpdateCost {
 			p.all.UpdatePendingCost(txObj)
 		}
+		// publish the updated status to concurrent readers
+		for _, txObj := range all {
+			txObj.publishStatus()
+		}
+		if len(removedEvs) > 0 {
+			p.goes.Go(func() {
+				for _, ev := range removedEvs {
+					p.txFeed.Send(ev)
+				}
+			})
+		}
 	}()
 
 	// recreate state every time to avoid high RAM usage when the pool at hight water-mark.
@@ -413,21 +555,26 @@ func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transacti
 	)
 	for _, txObj := range all {
 		if thor.IsOriginBlocked(txObj.Origin()) || p.blocklist.Contains(txObj.Origin()) {
-			toRemove = append(toRemove, txObj)
+			evict(txObj, "blocked")
 			logger.Debug("tx washed out", "id", txObj.ID(), "err", "blocked")
 			continue
 		}
 
 		// out of lifetime
 		if !txObj.localSubmitted && now > txObj.timeAdded+int64(p.options.MaxLifetime) {
-			toRemove = append(toRemove, txObj)
+			evict(txObj, "out of lifetime")
 			logger.Debug("tx washed out", "id", txObj.ID(), "err", "out of lifetime")
 			continue
 		}
 		// settled, out of energy or dep broken
 		executable, err := txObj.Executable(chain, newState(), headSummary.Header)
+		if err == errKnownTx {
+			toRemove = append(toRemove, &TxEvent{Tx: txObj.Transaction, Included: true})
+			logger.Debug("tx washed out", "id", txObj.ID(), "err", err)
+			continue
+		}
 		if err != nil {
-			toRemove = append(toRemove, txObj)
+			evict(txObj, err.Error())
 			logger.Debug("tx washed out", "id", txObj.ID(), "err", err)
 			continue
 		}
@@ -435,7 +582,7 @@ func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transacti
 		if executable {
 			provedWork, err := txObj.ProvedWork(headSummary.Header.Number(), chain.GetBlockID)
 			if err != nil {
-				toRemove = append(toRemove, txObj)
+				evict(txObj, err.Error())
 				logger.Debug("tx washed out", "id", txObj.ID(), "err", err)
 				continue
 			}
@@ -460,18 +607,18 @@ func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transacti
 	// remove over limit txs, from non-executables to low priced
 	if len(executableObjs) > limit {
 		for _, txObj := range nonExecutableObjs {
-			toRemove = append(toRemove, txObj)
+			evict(txObj, "pool is full")
 			logger.Debug("non-executable tx washed out due to pool limit", "id", txObj.ID())
 		}
 		for _, txObj := range executableObjs[limit:] {
-			toRemove = append(toRemove, txObj)
+			evict(txObj, "pool is full")
 			logger.Debug("executable tx washed out due to pool limit", "id", txObj.ID())
 		}
 		executableObjs = executableObjs[:limit]
 	} else if len(executableObjs)+len(nonExecutableObjs) > limit {
 		// executableObjs + nonExecutableObjs over pool limit
 		for _, txObj := range nonExecutableObjs[limit-len(executableObjs):] {
-			toRemove = append(toRemove, txObj)
+			evict(txObj, "pool is full")
 			logger.Debug("non-executable tx washed out due to pool limit", "id", txObj.ID())
 		}
 	}
@@ -500,7 +647,7 @@ func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transacti
 	p.goes.Go(func() {
 		executable := true
 		for _, tx := range toBroadcast {
-			p.txFeed.Send(&TxEvent{tx, &executable})
+			p.txFeed.Send(&TxEvent{Tx: tx, Executable: &executable})
 		}
 	})
 	return executables, 0, nil
@@ -171,6 +171,78 @@ func TestDump(t *testing.T) {
 	}
 }
 
+func TestDumpInfoAndStatus(t *testing.T) {
+	pool := newPoolWithParams(LIMIT, LIMIT_PER_ACCOUNT, "", "", uint64(time.Now().Unix()))
+	defer pool.Close()
+
+	executable := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), devAccounts[0])
+	futureRef := newTx(pool.repo.ChainTag(), nil, 21000, tx.NewBlockRef(10), 100, nil, tx.Features(0), devAccounts[1])
+	pendingDep := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, &thor.Bytes32{1}, tx.Features(0), devAccounts[1])
+
+	assert.Nil(t, pool.AddLocal(executable))
+	assert.Nil(t, pool.Add(futureRef))
+	assert.Nil(t, pool.Add(pendingDep))
+
+	infos := pool.DumpInfo()
+	assert.Equal(t, 3, len(infos))
+
+	info := pool.GetInfo(executable.ID())
+	assert.True(t, info.Executable)
+	assert.True(t, info.LocalSubmitted)
+	assert.Equal(t, devAccounts[0].Address, info.Origin)
+	assert.Equal(t, "", info.PendingReason)
+
+	info = pool.GetInfo(futureRef.ID())
+	assert.False(t, info.Executable)
+	assert.Equal(t, "block ref in future", info.PendingReason)
+
+	info = pool.GetInfo(pendingDep.ID())
+	assert.False(t, info.Executable)
+	assert.Equal(t, "dependency not settled", info.PendingReason)
+
+	assert.Nil(t, pool.GetInfo(thor.Bytes32{}))
+
+	assert.Equal(t, &Status{
+		Total:           3,
+		Executable:      1,
+		Local:           1,
+		Origins:         2,
+		Limit:           LIMIT,
+		LimitPerAccount: LIMIT_PER_ACCOUNT,
+	}, pool.Status())
+}
+
+func TestInfoWhileWashing(t *testing.T) {
+	pool := newPoolWithParams(LIMIT, LIMIT_PER_ACCOUNT, "", "", uint64(time.Now().Unix()))
+	defer pool.Close()
+
+	executable := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), devAccounts[0])
+	futureRef := newTx(pool.repo.ChainTag(), nil, 21000, tx.NewBlockRef(10), 100, nil, tx.Features(0), devAccounts[1])
+	assert.Nil(t, pool.Add(executable))
+	assert.Nil(t, pool.Add(futureRef))
+
+	// status fields updated by washing are read concurrently, which is checked by the race detector
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			pool.DumpInfo()
+			pool.GetInfo(executable.ID())
+			pool.Status()
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		_, _, err := pool.wash(pool.repo.BestBlockSummary())
+		assert.Nil(t, err)
+	}
+	<-done
+
+	info := pool.GetInfo(executable.ID())
+	assert.True(t, info.Executable)
+	assert.NotNil(t, info.OverallGasPrice)
+	assert.Equal(t, "block ref in future", pool.GetInfo(futureRef.ID()).PendingReason)
+}
+
 func TestRemove(t *testing.T) {
 	pool := newPool(LIMIT, LIMIT_PER_ACCOUNT)
 	defer pool.Close()
@@ -206,6 +278,39 @@ func TestRemoveWithError(t *testing.T) {
 	assert.False(t, removed, "Transaction should not be successfully removed as it doesn't exist")
 }
 
+func TestAddLocalAtomically(t *testing.T) {
+	pool := newPool(LIMIT, 2)
+	defer pool.Close()
+
+	pooled := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), devAccounts[0])
+	assert.Nil(t, pool.AddLocal(pooled))
+
+	tx1 := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), devAccounts[1])
+	tx2 := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), devAccounts[2])
+	badTx := newTx(pool.repo.ChainTag()+1, nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), devAccounts[3])
+
+	// the inserted txs are rolled back, but not the one already in the pool
+	i, err := pool.AddLocalAtomically(tx.Transactions{pooled, tx1, badTx, tx2})
+	assert.Equal(t, 2, i)
+	assert.Equal(t, "bad tx: chain tag mismatch", err.Error())
+	assert.NotNil(t, pool.Get(pooled.ID()))
+	assert.Nil(t, pool.Get(tx1.ID()))
+	assert.Nil(t, pool.Get(tx2.ID()))
+
+	// the quota counts the txs already in the pool
+	over := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), devAccounts[0])
+	over2 := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{1}, 100, nil, tx.Features(0), devAccounts[0])
+	i, err = pool.AddLocalAtomically(tx.Transactions{tx1, over, over2})
+	assert.Equal(t, -1, i)
+	assert.Equal(t, "tx rejected: account quota exceeded", err.Error())
+	assert.Nil(t, pool.Get(tx1.ID()))
+
+	i, err = pool.AddLocalAtomically(tx.Transactions{tx1, tx2, over})
+	assert.Equal(t, -1, i)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, pool.all.Len())
+}
+
 func TestNewClose(t *testing.T) {
 	pool := newPool(LIMIT, LIMIT_PER_ACCOUNT)
 	defer pool.Close()
@@ -242,7 +347,7 @@ func TestSubscribeNewTx(t *testing.T) {
 	assert.Nil(t, pool.Add(tx))
 
 	v := true
-	assert.Equal(t, &TxEvent{tx, &v}, <-txCh)
+	assert.Equal(t, &TxEvent{Tx: tx, Executable: &v}, <-txCh)
 }
 
 func TestWashTxs(t *testing.T) {
@@ -458,13 +563,55 @@ func TestBlocked(t *testing.T) {
 	assert.Nil(t, err)
 	pool.all.Add(txObj, LIMIT_PER_ACCOUNT, func(_ thor.Address, _ *big.Int) error { return nil })
 
+	txCh := make(chan *TxEvent)
+	pool.SubscribeTxEvent(txCh)
+
 	pool.wash(pool.repo.BestBlockSummary())
 	got := pool.Get(trx.ID())
 	assert.Nil(t, got)
 
+	// eviction is notified
+	assert.Equal(t, &TxEvent{Tx: trx, Evicted: true, Reason: "blocked"}, <-txCh)
+
 	os.Remove(file.Name())
 }
 
+func TestWashIncluded(t *testing.T) {
+	pool := newPool(LIMIT, LIMIT_PER_ACCOUNT)
+	defer pool.Close()
+
+	trx := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), devAccounts[0])
+	txObj, err := resolveTx(trx, false)
+	assert.Nil(t, err)
+	pool.all.Add(txObj, LIMIT_PER_ACCOUNT, func(_ thor.Address, _ *big.Int) error { return nil })
+
+	st := pool.stater.NewState(pool.repo.GenesisBlock().Header().StateRoot(), 0, 0, 0)
+	stage, _ := st.Stage(1, 0)
+	root1, _ := stage.Commit()
+
+	b1 := new(block.Builder).
+		ParentID(pool.repo.GenesisBlock().Header().ID()).
+		Timestamp(uint64(time.Now().Unix())).
+		TotalScore(100).
+		GasLimit(10000000).
+		StateRoot(root1).
+		Transaction(trx).
+		Build()
+	if err := pool.repo.AddBlock(b1, tx.Receipts{&tx.Receipt{}}, 0); err != nil {
+		t.Fatal(err)
+	}
+	pool.repo.SetBestBlockID(b1.Header().ID())
+
+	txCh := make(chan *TxEvent)
+	pool.SubscribeTxEvent(txCh)
+
+	pool.wash(pool.repo.BestBlockSummary())
+	assert.Nil(t, pool.Get(trx.ID()))
+
+	// inclusion is not notified as an eviction
+	assert.Equal(t, &TxEvent{Tx: trx, Included: true}, <-txCh)
+}
+
 func TestWash(t *testing.T) {
 	pool := newPool(LIMIT, LIMIT_PER_ACCOUNT)
 	defer pool.Close()